@@ -31,7 +31,10 @@ const docTemplate = `{
     "paths": {
         "/physical_database/force_registration": {
             "get": {
-                "description": "Force this adapter to immediately register itself in dbaas-aggregator.\nAdapter initiates background task that tries to register physical database in dbaas-aggregator,\nand responds with status 202 before the background task finishes.",
+                "description": "Force this adapter to immediately register itself in dbaas-aggregator.\nAdapter initiates background task that tries to register physical database in dbaas-aggregator,\nand responds with status 202 before the background task finishes.\nReturned track can be used to get the registration result.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Common dbaas adapter operations"
                 ],
@@ -54,53 +57,41 @@ const docTemplate = `{
                     "202": {
                         "description": "if physical database registration process has been started successfully.",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.PhysicalDatabaseRegistrationTrack"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication is required and has failed or has not been provided",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Only dbaas aggregator can force registration",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Adapter is draining or too many registrations are queued",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/backups/collect": {
-            "post": {
-                "description": "Requests database backup daemon to collect backup for specified databases",
-                "consumes": [
-                    "application/json"
-                ],
+        "/physical_database/force_registration/{trackId}": {
+            "get": {
+                "description": "Returns status of the physical database registration initiated by force registration request",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Backup and Restore"
+                    "Common dbaas adapter operations"
                 ],
-                "summary": "Collect backup",
+                "summary": "Track forced physical database registration",
                 "parameters": [
-                    {
-                        "description": "Databases to backup",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    {
-                        "enum": [
-                            "postgresql",
-                            "arangodb",
-                            "clickhouse",
-                            "mongodb",
-                            "cassandra"
-                        ],
-                        "type": "string",
-                        "default": "postgresql",
-                        "description": "Application name",
-                        "name": "appName",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "enum": [
                             "v1",
@@ -112,34 +103,50 @@ const docTemplate = `{
                         "name": "apiVersion",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "trackId",
+                        "name": "trackId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dao.DatabaseAdapterBaseTrack"
+                            "$ref": "#/definitions/dao.PhysicalDatabaseRegistrationTrack"
                         }
                     },
-                    "500": {
-                        "description": "Unknown error",
+                    "401": {
+                        "description": "Authentication is required and has failed or has not been provided",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Registration track not found",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/backups/track/backup/{trackId}": {
-            "get": {
-                "description": "Return status of backup task",
+        "/{appName}/backups": {
+            "post": {
+                "description": "Initiates a backup of specified databases and stores it in the configured storage. The operation is asynchronous and returns immediately with current status of backup.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Backup and Restore"
                 ],
-                "summary": "Track backup",
+                "summary": "Initiate database backup",
                 "parameters": [
                     {
                         "enum": [
@@ -169,39 +176,65 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "trackId",
-                        "name": "trackId",
-                        "in": "path",
-                        "required": true
+                        "description": "Backup configuration details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dao.CreateBackupRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Backup operation accepted and is being processed",
                         "schema": {
-                            "$ref": "#/definitions/dao.DatabaseAdapterBaseTrack"
+                            "$ref": "#/definitions/dao.BackupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The request was invalid or cannot be served",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication is required and has failed or has not been provided",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "The request was valid, but the server is refusing action",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "The requested resource could not be found",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     },
                     "500": {
-                        "description": "Unknown error",
+                        "description": "An unexpected error occurred on the server",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/backups/track/restore/{trackId}": {
+        "/{appName}/backups/backup/{backupId}": {
             "get": {
-                "description": "Return status of restore task",
+                "description": "Retrieve details about a specific backup operation",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Backup and Restore"
                 ],
-                "summary": "Track restore",
+                "summary": "Get backup details",
                 "parameters": [
                     {
                         "enum": [
@@ -232,41 +265,40 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "trackId",
-                        "name": "trackId",
+                        "format": "uuid",
+                        "description": "Unique identifier of the backup operation",
+                        "name": "backupId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Backup details retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/dao.DatabaseAdapterBaseTrack"
+                            "$ref": "#/definitions/dao.BackupResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "The requested resource could not be found",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     },
                     "500": {
-                        "description": "Unknown error",
+                        "description": "An unexpected error occurred on the server",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/{appName}/backups/{backupId}": {
+            },
             "delete": {
-                "description": "Returns deletion status",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Delete a backup operation",
                 "tags": [
                     "Backup and Restore"
                 ],
-                "summary": "Evict backup",
+                "summary": "Delete backup",
                 "parameters": [
                     {
                         "enum": [
@@ -297,31 +329,35 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "trackId",
+                        "format": "uuid",
+                        "description": "Unique identifier of the backup operation",
                         "name": "backupId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Succesfull delete",
+                    "204": {
+                        "description": "Backup deleted successfully"
+                    },
+                    "404": {
+                        "description": "The requested resource could not be found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     },
                     "500": {
-                        "description": "Unknown error",
+                        "description": "An unexpected error occurred on the server",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/backups/{backupId}/restoration": {
+        "/{appName}/backups/backup/{backupId}/restore": {
             "post": {
-                "description": "Requests database backup daemon to restore specified backup with specified databases info",
+                "description": "Restores databases from a previously created backup. Supports dry-run mode to validate the restore operation without making changes. The operation is asynchronous and returns immediately with current status of restore.",
                 "consumes": [
                     "application/json"
                 ],
@@ -331,17 +367,8 @@ const docTemplate = `{
                 "tags": [
                     "Backup and Restore"
                 ],
-                "summary": "Restore backup",
+                "summary": "Restore databases from backup",
                 "parameters": [
-                    {
-                        "description": "List of databases to restore",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dao.RestorationRequest"
-                        }
-                    },
                     {
                         "enum": [
                             "postgresql",
@@ -371,37 +398,60 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Backup identifier",
+                        "format": "uuid",
+                        "description": "Unique identifier of the backup to restore from",
                         "name": "backupId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "If true, validates the restore operation without making any changes",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Restore configuration details",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dao.CreateRestoreRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Restore requested",
+                        "description": "Restore operation accepted and is being processed",
                         "schema": {
-                            "$ref": "#/definitions/dao.DatabaseAdapterRestoreTrack"
+                            "$ref": "#/definitions/dao.RestoreResponse"
                         }
                     },
-                    "500": {
-                        "description": "Unknown error",
+                    "400": {
+                        "description": "The request was invalid or cannot be served",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     },
-                    "501": {
-                        "description": "Cannot restore backup without explicitly specified list of databases in it",
+                    "404": {
+                        "description": "The requested resource could not be found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "An unexpected error occurred on the server",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/backups/{backupId}/restore": {
+        "/{appName}/backups/collect": {
             "post": {
-                "description": "Requests database backup daemon to restore specified backup. Deprecated, please use /restoration endpoint instead",
+                "description": "Requests database backup daemon to collect backup for specified databases",
                 "consumes": [
                     "application/json"
                 ],
@@ -411,18 +461,17 @@ const docTemplate = `{
                 "tags": [
                     "Backup and Restore"
                 ],
-                "summary": "Restore backup",
-                "deprecated": true,
+                "summary": "Collect backup",
                 "parameters": [
                     {
-                        "description": "List of database names for restore",
+                        "description": "Databases to backup",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dao.DbInfo"
+                                "type": "string"
                             }
                         }
                     },
@@ -452,53 +501,40 @@ const docTemplate = `{
                         "name": "apiVersion",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Backup identifier",
-                        "name": "backupId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "If this parameter has value true then restored databases will have new names and will be passed through associative array changedNameDb in response object",
-                        "name": "regenerateNames",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "202": {
                         "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/dao.DatabaseAdapterRestoreTrack"
+                            "$ref": "#/definitions/dao.DatabaseAdapterBaseTrack"
                         }
                     },
-                    "500": {
-                        "description": "Unknown error",
+                    "400": {
+                        "description": "Request body is not valid, violations are listed in errors field",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     },
-                    "501": {
-                        "description": "Cannot restore backup without explicitly specified list of databases in it",
+                    "500": {
+                        "description": "Unknown error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/databases": {
+        "/{appName}/backups/restore/{restoreId}": {
             "get": {
-                "description": "Returns list with names of databases",
+                "description": "Retrieves details of a restore operation",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Database administration"
+                    "Backup and Restore"
                 ],
-                "summary": "List of all databases",
+                "summary": "Get restore details",
                 "parameters": [
                     {
                         "enum": [
@@ -526,38 +562,43 @@ const docTemplate = `{
                         "name": "apiVersion",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Unique identifier of the restore operation",
+                        "name": "restoreId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Databases listed",
+                        "description": "Restore details retrieved successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dao.RestoreResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "The requested resource could not be found",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     },
                     "500": {
-                        "description": "Error occurred while databases listing.",
+                        "description": "An unexpected error occurred on the server",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Creates database with one user having readWrite role in it and returns connection parameters including credentials.\nAlso in created database provided metadata being inserted in _dbaas_metadata collection.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            "delete": {
+                "description": "Delete a restore operation",
                 "tags": [
-                    "Database administration"
+                    "Backup and Restore"
                 ],
-                "summary": "Create database",
+                "summary": "Delete restore",
                 "parameters": [
                     {
                         "enum": [
@@ -587,44 +628,43 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Create DB body request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dao.DbCreateRequest"
-                        }
-                    }
-                ],
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Unique identifier of the restore operation",
+                        "name": "restoreId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "204": {
+                        "description": "Restore deleted successfully"
+                    },
+                    "404": {
+                        "description": "The requested resource could not be found",
                         "schema": {
-                            "$ref": "#/definitions/dao.DbCreateResponseMultiUser"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     },
-                    "400": {
-                        "description": "Provided parameters does not meet the requirements",
+                    "500": {
+                        "description": "An unexpected error occurred on the server",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/databases/{dbName}/metadata": {
-            "put": {
-                "description": "Changes metadata saved in databaase",
-                "consumes": [
-                    "application/json"
-                ],
+        "/{appName}/backups/track/backup/{trackId}": {
+            "get": {
+                "description": "Return status of backup task",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Database administration"
+                    "Backup and Restore"
                 ],
-                "summary": "Update database metadata",
+                "summary": "Track backup",
                 "parameters": [
                     {
                         "enum": [
@@ -655,51 +695,38 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Databases to update",
-                        "name": "dbName",
+                        "description": "trackId",
+                        "name": "trackId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "New metadata",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Update metadata was successful",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.DatabaseAdapterBaseTrack"
                         }
                     },
                     "500": {
-                        "description": "Error was occurred during update metadata.",
+                        "description": "Unknown error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/databases/{dbName}/migrate-to-vault/{userName}": {
-            "post": {
-                "description": "Run migration of DB password to vault and returns vault secret",
-                "consumes": [
-                    "application/json"
-                ],
+        "/{appName}/backups/track/restore/{trackId}": {
+            "get": {
+                "description": "Return status of restore task",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Database administration"
+                    "Backup and Restore"
                 ],
-                "summary": "Migrate DB password to vault",
+                "summary": "Track restore",
                 "parameters": [
                     {
                         "enum": [
@@ -730,38 +757,31 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Databases to update",
-                        "name": "dbName",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User whose password should be migrated",
-                        "name": "userName",
+                        "description": "trackId",
+                        "name": "trackId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Vault role name",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.DatabaseAdapterBaseTrack"
                         }
                     },
                     "500": {
                         "description": "Unknown error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/describe/databases": {
-            "post": {
-                "description": "Returns info about requested databases, this is optional API",
+        "/{appName}/backups/{backupId}": {
+            "delete": {
+                "description": "Returns deletion status",
                 "consumes": [
                     "application/json"
                 ],
@@ -769,9 +789,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Database administration"
+                    "Backup and Restore"
                 ],
-                "summary": "Describe databases",
+                "summary": "Evict backup",
                 "parameters": [
                     {
                         "enum": [
@@ -801,52 +821,32 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "If present - should return connection properties of each requested database",
-                        "name": "resources",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "If present - should return resources of each requested database",
-                        "name": "connectionProperties",
-                        "in": "query"
-                    },
-                    {
-                        "description": "List of names of databases to describe",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
+                        "type": "string",
+                        "description": "trackId",
+                        "name": "backupId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Succesfull delete",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/dao.LogicalDatabaseDescribed"
-                            }
+                            "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Error occurred while databases describe.",
+                        "description": "Unknown error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/physical_database": {
-            "get": {
-                "description": "Adapter belongs only one database cluster and send own physical database information",
+        "/{appName}/backups/{backupId}/restoration": {
+            "post": {
+                "description": "Requests database backup daemon to restore specified backup with specified databases info",
                 "consumes": [
                     "application/json"
                 ],
@@ -854,10 +854,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Database administration"
+                    "Backup and Restore"
                 ],
-                "summary": "Physical database information",
+                "summary": "Restore backup",
                 "parameters": [
+                    {
+                        "description": "List of databases to restore",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dao.RestorationRequest"
+                        }
+                    },
                     {
                         "enum": [
                             "postgresql",
@@ -884,27 +893,46 @@ const docTemplate = `{
                         "name": "apiVersion",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Backup identifier",
+                        "name": "backupId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Own physical database information",
+                    "202": {
+                        "description": "Restore requested",
                         "schema": {
-                            "$ref": "#/definitions/service.PhysicalDatabase"
+                            "$ref": "#/definitions/dao.DatabaseAdapterRestoreTrack"
                         }
                     },
-                    "404": {
-                        "description": "Identifier is not specified",
+                    "400": {
+                        "description": "Request body is not valid, violations are listed in errors field",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown error",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Cannot restore backup without explicitly specified list of databases in it",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/resources/bulk-drop": {
+        "/{appName}/backups/{backupId}/restore": {
             "post": {
-                "description": "Can drop any previously created resources such as user or database",
+                "description": "Requests database backup daemon to restore specified backup. Deprecated, please use /restoration endpoint instead",
                 "consumes": [
                     "application/json"
                 ],
@@ -912,10 +940,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Database administration"
+                    "Backup and Restore"
                 ],
-                "summary": "Drop created resources",
+                "summary": "Restore backup",
+                "deprecated": true,
                 "parameters": [
+                    {
+                        "description": "List of database names for restore",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dao.DbInfo"
+                            }
+                        }
+                    },
                     {
                         "enum": [
                             "postgresql",
@@ -944,44 +985,57 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "List of resources to drop",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dao.DbResource"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Backup identifier",
+                        "name": "backupId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "If this parameter has value true then restored databases will have new names and will be passed through associative array changedNameDb in response object",
+                        "name": "regenerateNames",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Drop successful",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dao.DbResource"
-                            }
+                            "$ref": "#/definitions/dao.DatabaseAdapterRestoreTrack"
+                        }
+                    },
+                    "400": {
+                        "description": "Request body is not valid, violations are listed in errors field",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown error",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Cannot restore backup without explicitly specified list of databases in it",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
         },
-        "/{appName}/users": {
-            "put": {
-                "description": "Creates new user for specified database and returns it with connection information,\nor returns already created user if it exists. If database not specified will be used default database.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/{appName}/databases": {
+            "get": {
+                "description": "Returns list with names of databases",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Database administration"
                 ],
-                "summary": "Create user",
+                "summary": "List of all databases",
                 "parameters": [
                     {
                         "enum": [
@@ -1009,14 +1063,935 @@ const docTemplate = `{
                         "name": "apiVersion",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Info about user to create",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dao.UserCreateRequest"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Databases listed",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Error occurred while databases listing.",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates database with one user having readWrite role in it and returns connection parameters including credentials.\nAlso in created database provided metadata being inserted in _dbaas_metadata collection.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Create database",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create DB body request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dao.DbCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dao.DbCreateResponseMultiUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Provided parameters does not meet the requirements",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Adapter is shutting down",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/databases/{dbName}/metadata": {
+            "put": {
+                "description": "Changes metadata saved in databaase",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Update database metadata",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Databases to update",
+                        "name": "dbName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New metadata",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update metadata was successful",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Request body is not valid, violations are listed in errors field",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Error was occurred during update metadata.",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/databases/{dbName}/migrate-from-vault/{userName}": {
+            "post": {
+                "description": "Sets new generated password of the user, deletes its vault role and returns the new password",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Migrate DB password from vault",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Database of the user",
+                        "name": "dbName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User whose password should be migrated from vault",
+                        "name": "userName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New password of the user, Warning header is set if vault role cannot be deleted and the migration should be repeated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Vault integration is disabled or password of the user is not stored in vault",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown error",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Operation is not supported by the adapter",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/databases/{dbName}/migrate-to-vault/{userName}": {
+            "post": {
+                "description": "Run migration of DB password to vault and returns vault secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Migrate DB password to vault",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Databases to update",
+                        "name": "dbName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User whose password should be migrated",
+                        "name": "userName",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Vault role name",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Unknown error",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/databases/{dbName}/users/{name}/rotate": {
+            "post": {
+                "description": "Sets new generated password of the user and returns connection properties with the new password.\nPasswords stored in vault are not rotated by this operation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Rotate user password",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Database of the user",
+                        "name": "dbName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The username whose password should be rotated",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.CreatedUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Password of the user is managed by vault",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User is not found in the database",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Error occurred while password rotation.",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Operation is not supported by the adapter",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/databases/{dbName}/users/{name}/rotations": {
+            "get": {
+                "description": "Returns the latest password rotations of the user, performed on demand or by rotation policy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Password rotation audit",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Database of the user",
+                        "name": "dbName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The username",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dao.PasswordRotationRecord"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/describe/databases": {
+            "post": {
+                "description": "Returns info about requested databases, this is optional API",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Describe databases",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "If present - should return connection properties of each requested database",
+                        "name": "resources",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "If present - should return resources of each requested database",
+                        "name": "connectionProperties",
+                        "in": "query"
+                    },
+                    {
+                        "description": "List of names of databases to describe",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dao.LogicalDatabaseDescribed"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Request body is not valid, violations are listed in errors field",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Error occurred while databases describe.",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/migrate-to-vault": {
+            "get": {
+                "description": "Returns status of the last migration of all users to vault",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Status of migration to vault",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.VaultMigrationJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Migration to vault has not been started",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Starts background migration to vault of all users which passwords are not stored in vault yet.\nDry run only reports users which would be migrated. Interrupted migration is resumed by the next request, parameters of the next request are used and databases processed already are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Migrate all users to vault",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Migration parameters",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dao.VaultMigrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "if migration has been started successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dao.VaultMigrationJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Vault integration is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Migration to vault is already in progress",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/physical_database": {
+            "get": {
+                "description": "Adapter belongs only one database cluster and send own physical database information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Physical database information",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Own physical database information",
+                        "schema": {
+                            "$ref": "#/definitions/service.PhysicalDatabase"
+                        }
+                    },
+                    "404": {
+                        "description": "Identifier is not specified",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/resources/bulk-drop": {
+            "post": {
+                "description": "Can drop any previously created resources such as user or database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Drop created resources",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "List of resources to drop",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dao.DbResource"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Drop successful",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dao.DbResource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Request body is not valid, violations are listed in errors field",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Some resources are not dropped, results of all the resources are in resources field instead of the response body of previous versions",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/supports": {
+            "get": {
+                "description": "Returns features supported by the adapter",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Supported features",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dao.Supports"
+                        }
+                    }
+                }
+            }
+        },
+        "/{appName}/users": {
+            "put": {
+                "description": "Creates new user for specified database and returns it with connection information,\nor returns already created user if it exists. If database not specified will be used default database.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Database administration"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "enum": [
+                            "postgresql",
+                            "arangodb",
+                            "clickhouse",
+                            "mongodb",
+                            "cassandra"
+                        ],
+                        "type": "string",
+                        "default": "postgresql",
+                        "description": "Application name",
+                        "name": "appName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "v1",
+                            "v2"
+                        ],
+                        "type": "string",
+                        "default": "v2",
+                        "description": "API version of dbaas adapter",
+                        "name": "apiVersion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Info about user to create",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dao.UserCreateRequest"
                         }
                     }
                 ],
@@ -1027,10 +2002,10 @@ const docTemplate = `{
                             "$ref": "#/definitions/dao.CreatedUser"
                         }
                     },
-                    "500": {
+                    "400": {
                         "description": "Error occurred while user creation.",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
@@ -1101,20 +2076,130 @@ const docTemplate = `{
                             "$ref": "#/definitions/dao.CreatedUser"
                         }
                     },
-                    "500": {
+                    "400": {
                         "description": "Error occurred while user creation.",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dao.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dao.ConnectionProperties": {
+        }
+    },
+    "definitions": {
+        "dao.BackupDatabaseInfo": {
+            "type": "object",
+            "required": [
+                "databaseName"
+            ],
+            "properties": {
+                "databaseName": {
+                    "type": "string"
+                }
+            }
+        },
+        "dao.BackupResponse": {
+            "type": "object",
+            "required": [
+                "backupId",
+                "blobPath",
+                "creationTime",
+                "databases",
+                "status",
+                "storageName"
+            ],
+            "properties": {
+                "backupId": {
+                    "type": "string"
+                },
+                "blobPath": {
+                    "type": "string"
+                },
+                "completionTime": {
+                    "type": "string"
+                },
+                "creationTime": {
+                    "type": "string"
+                },
+                "databases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.LogicalDatabaseBackup"
+                    }
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/dao.BackupRestoreStatus"
+                },
+                "storageName": {
+                    "type": "string"
+                }
+            }
+        },
+        "dao.BackupRestoreStatus": {
+            "type": "string",
+            "enum": [
+                "notStarted",
+                "inProgress",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "NotStartedStatus",
+                "InProgressStatus",
+                "CompletedStatus",
+                "FailedStatus"
+            ]
+        },
+        "dao.ConnectionProperties": {
+            "type": "object",
+            "additionalProperties": true
+        },
+        "dao.CreateBackupRequest": {
+            "type": "object",
+            "required": [
+                "blobPath",
+                "databases",
+                "storageName"
+            ],
+            "properties": {
+                "blobPath": {
+                    "type": "string"
+                },
+                "databases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.BackupDatabaseInfo"
+                    }
+                },
+                "storageName": {
+                    "type": "string"
+                }
+            }
+        },
+        "dao.CreateRestoreRequest": {
             "type": "object",
-            "additionalProperties": true
+            "required": [
+                "blobPath",
+                "databases",
+                "storageName"
+            ],
+            "properties": {
+                "blobPath": {
+                    "type": "string"
+                },
+                "databases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.RestoreMapping"
+                    }
+                },
+                "storageName": {
+                    "type": "string"
+                }
+            }
         },
         "dao.CreatedUser": {
             "type": "object",
@@ -1259,6 +2344,9 @@ const docTemplate = `{
         },
         "dao.DbInfo": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
                 "microservice": {
                     "type": "string"
@@ -1276,6 +2364,10 @@ const docTemplate = `{
         },
         "dao.DbResource": {
             "type": "object",
+            "required": [
+                "kind",
+                "name"
+            ],
             "properties": {
                 "errorMessage": {
                     "type": "string"
@@ -1302,6 +2394,83 @@ const docTemplate = `{
                 "DELETE_FAILED"
             ]
         },
+        "dao.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "invalid-request",
+                "validation-failed",
+                "unauthorized",
+                "forbidden",
+                "not-found",
+                "method-not-allowed",
+                "conflict",
+                "payload-too-large",
+                "too-many-requests",
+                "internal-error",
+                "not-supported",
+                "unavailable",
+                "timeout",
+                "creation-failed",
+                "drop-failed",
+                "vault-disabled",
+                "user-not-in-vault",
+                "password-managed-by-vault",
+                "vault-migration-in-progress",
+                "restore-requires-databases"
+            ],
+            "x-enum-varnames": [
+                "InvalidRequestCode",
+                "ValidationFailedCode",
+                "UnauthorizedCode",
+                "ForbiddenCode",
+                "NotFoundCode",
+                "MethodNotAllowedCode",
+                "ConflictCode",
+                "PayloadTooLargeCode",
+                "TooManyRequestsCode",
+                "InternalErrorCode",
+                "NotSupportedCode",
+                "UnavailableCode",
+                "TimeoutCode",
+                "CreationFailedCode",
+                "DropFailedCode",
+                "VaultDisabledCode",
+                "UserNotInVaultCode",
+                "PasswordManagedByVaultCode",
+                "VaultMigrationInProgressCode",
+                "RestoreRequiresDatabasesCode"
+            ]
+        },
+        "dao.LogicalDatabaseBackup": {
+            "type": "object",
+            "required": [
+                "databaseName",
+                "status"
+            ],
+            "properties": {
+                "creationTime": {
+                    "type": "string"
+                },
+                "databaseName": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/dao.BackupRestoreStatus"
+                }
+            }
+        },
         "dao.LogicalDatabaseDescribed": {
             "type": "object",
             "properties": {
@@ -1319,6 +2488,173 @@ const docTemplate = `{
                 }
             }
         },
+        "dao.LogicalDatabaseRestore": {
+            "type": "object",
+            "required": [
+                "databaseName",
+                "status"
+            ],
+            "properties": {
+                "creationTime": {
+                    "type": "string"
+                },
+                "databaseName": {
+                    "type": "string"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "microserviceName": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "previousDatabaseName": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/dao.BackupRestoreStatus"
+                }
+            }
+        },
+        "dao.PasswordRotationRecord": {
+            "type": "object",
+            "properties": {
+                "dbName": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "trigger": {
+                    "$ref": "#/definitions/dao.PasswordRotationTrigger"
+                },
+                "userName": {
+                    "type": "string"
+                }
+            }
+        },
+        "dao.PasswordRotationTrigger": {
+            "type": "string",
+            "enum": [
+                "MANUAL",
+                "POLICY"
+            ],
+            "x-enum-varnames": [
+                "PasswordRotationManualTrigger",
+                "PasswordRotationPolicyTrigger"
+            ]
+        },
+        "dao.PhysicalDatabaseRegistrationTrack": {
+            "type": "object",
+            "properties": {
+                "completionTime": {
+                    "type": "string"
+                },
+                "creationTime": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/dao.RegistrationTrackStatus"
+                },
+                "trackId": {
+                    "type": "string"
+                }
+            }
+        },
+        "dao.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the stable error code",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dao.ErrorCode"
+                        }
+                    ],
+                    "example": "not-found"
+                },
+                "detail": {
+                    "description": "Detail explains this occurrence of the problem",
+                    "type": "string",
+                    "example": "Backup not found"
+                },
+                "errors": {
+                    "description": "Errors are failed validations of the request",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "instance": {
+                    "description": "Instance is the path of the request",
+                    "type": "string",
+                    "example": "/api/v2/dbaas/adapter/postgresql/backups/backup/1"
+                },
+                "requestId": {
+                    "description": "RequestId is X-Request-ID of the request or generated id responded in X-Request-ID header",
+                    "type": "string"
+                },
+                "resources": {
+                    "description": "Resources are results of partially failed drop of resources",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.DbResource"
+                    }
+                },
+                "retryable": {
+                    "description": "Retryable is true if the same request may succeed later",
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "Status is the status code of the response",
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "description": "Title is the status text",
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "description": "Type is URN of the error code",
+                    "type": "string",
+                    "example": "urn:dbaas-adapter:problem:not-found"
+                }
+            }
+        },
+        "dao.RegistrationTrackStatus": {
+            "type": "string",
+            "enum": [
+                "PROCEEDING",
+                "SUCCESS",
+                "FAIL"
+            ],
+            "x-enum-varnames": [
+                "RegistrationProceedingStatus",
+                "RegistrationSuccessStatus",
+                "RegistrationFailStatus"
+            ]
+        },
         "dao.RestorationRequest": {
             "type": "object",
             "properties": {
@@ -1333,6 +2669,74 @@ const docTemplate = `{
                 }
             }
         },
+        "dao.RestoreMapping": {
+            "type": "object",
+            "required": [
+                "databaseName",
+                "microserviceName",
+                "namespace"
+            ],
+            "properties": {
+                "databaseName": {
+                    "type": "string"
+                },
+                "microserviceName": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "dao.RestoreResponse": {
+            "type": "object",
+            "required": [
+                "blobPath",
+                "creationTime",
+                "databases",
+                "restoreId",
+                "status",
+                "storageName"
+            ],
+            "properties": {
+                "blobPath": {
+                    "type": "string"
+                },
+                "completionTime": {
+                    "type": "string"
+                },
+                "creationTime": {
+                    "type": "string"
+                },
+                "databases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.LogicalDatabaseRestore"
+                    }
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "restoreId": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/dao.BackupRestoreStatus"
+                },
+                "storageName": {
+                    "type": "string"
+                }
+            }
+        },
+        "dao.Supports": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "boolean"
+            }
+        },
         "dao.UserCreateRequest": {
             "type": "object",
             "properties": {
@@ -1350,6 +2754,109 @@ const docTemplate = `{
                 }
             }
         },
+        "dao.VaultMigrationJob": {
+            "type": "object",
+            "properties": {
+                "alreadyMigratedUsers": {
+                    "type": "integer"
+                },
+                "completionTime": {
+                    "type": "string"
+                },
+                "creationTime": {
+                    "type": "string"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.VaultMigrationUser"
+                    }
+                },
+                "jobId": {
+                    "type": "string"
+                },
+                "migratedUsers": {
+                    "type": "integer"
+                },
+                "pending": {
+                    "description": "Pending contains users which would be migrated, it is filled in dry run only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dao.VaultMigrationUser"
+                    }
+                },
+                "processedDatabases": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/dao.VaultMigrationStatus"
+                },
+                "totalDatabases": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dao.VaultMigrationRequest": {
+            "type": "object",
+            "required": [
+                "databases"
+            ],
+            "properties": {
+                "databases": {
+                    "description": "Databases limits migration to the listed logical databases, all databases are migrated if it is empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dryRun": {
+                    "description": "DryRun only reports users which would be migrated",
+                    "type": "boolean"
+                },
+                "ratePerSecond": {
+                    "description": "RatePerSecond limits the number of migrated users per second, adapter default is used if it is 0",
+                    "type": "number",
+                    "maximum": 1000,
+                    "minimum": 0
+                }
+            }
+        },
+        "dao.VaultMigrationStatus": {
+            "type": "string",
+            "enum": [
+                "PROCEEDING",
+                "SUCCESS",
+                "FAIL"
+            ],
+            "x-enum-varnames": [
+                "VaultMigrationProceedingStatus",
+                "VaultMigrationSuccessStatus",
+                "VaultMigrationFailStatus"
+            ]
+        },
+        "dao.VaultMigrationUser": {
+            "type": "object",
+            "properties": {
+                "dbName": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "userName": {
+                    "type": "string"
+                },
+                "vaultRole": {
+                    "type": "string"
+                }
+            }
+        },
         "service.PhysicalDatabase": {
             "type": "object",
             "properties": {
@@ -1374,7 +2881,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/api/{apiVersion}/dbaas/adapter",
 	Schemes:          []string{},
 	Title:            "Dbaas adapter API",
-	Description:      "",
+	Description:      "Errors are responded as application/problem+json problem details (RFC 7807) with stable error code,\nrequest id and retryable flag, see dao.Problem definition.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
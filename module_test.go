@@ -15,15 +15,36 @@
 package dbaas_adapter_core
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/audit"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/auth"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/config"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dbaas"
+	fiber2 "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/impl/fiber"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/service"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
 	testing2 "github.com/Netcracker/qubership-dbaas-adapter-core/testing"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap"
 )
 
@@ -162,6 +183,16 @@ func (d DbTestAdmin) MigrateToVault(ctx context.Context, dbName, userName string
 	return nil
 }
 
+func (d DbTestAdmin) RotatePassword(ctx context.Context, dbName, userName, password string) (dao.ConnectionProperties, error) {
+	v, ok := d.dbs[dbName]
+	if !ok || v.user != userName {
+		return nil, fmt.Errorf("user %s of database %s is not found", userName, dbName)
+	}
+	v.pass = password
+	d.dbs[dbName] = v
+	return dao.ConnectionProperties{"username": userName, "password": password}, nil
+}
+
 func (d DbTestAdmin) GetDBPrefix() string {
 	return "testcheckpref"
 }
@@ -191,10 +222,77 @@ func (d DbTestAdmin) GetROHost() string {
 }
 
 func (d DbTestAdmin) CreateRoles(ctx context.Context, roles []dao.AdditionalRole) ([]dao.Success, *dao.Failure) {
-	return []dao.Success{}, nil
+	success := make([]dao.Success, 0, len(roles))
+	for _, role := range roles {
+		success = append(success, dao.Success{Id: role.Id, DbName: role.DbName})
+	}
+	return success, nil
 }
 
 var _ service.DbAdministration = &DbTestAdmin{}
+var _ service.PasswordRotator = &DbTestAdmin{}
+
+// newTestDbAdmin returns DbTestAdmin of v2 API without databases
+func newTestDbAdmin(logger *zap.Logger) DbTestAdmin {
+	return DbTestAdmin{
+		version: "v2",
+		logger:  logger,
+		dbs: make(map[string]struct {
+			user string
+			pass string
+		}),
+	}
+}
+
+// testAdapter is the adapter registered in test aggregator, the aggregator is closed when the test finishes
+type testAdapter struct {
+	credentials testing2.AppCredentials
+	dbaasClient *dbaas.Client
+	// appPath is the path of v2 adapter API
+	appPath string
+}
+
+func newTestAdapter(t *testing.T) *testAdapter {
+	credentials := testing2.AppCredentials{
+		AppName:           testing2.Simplstr(),
+		AdapterApiUser:    testing2.Simplstr(),
+		AdapterApiPass:    testing2.Simplstr(),
+		AggregatorApiUser: testing2.Simplstr(),
+		AggregatorApiPass: testing2.Simplstr(),
+	}
+	aggregatorServer := testing2.GetTestHttpAggregatorServer(credentials.AggregatorApiUser, credentials.AggregatorApiPass, credentials.AppName, credentials.AppName, false)
+	t.Cleanup(aggregatorServer.Close)
+	dbaasClient, err := dbaas.NewDbaasClient(aggregatorServer.URL, &dao.BasicAuth{Username: credentials.AggregatorApiUser, Password: credentials.AggregatorApiPass}, nil)
+	assert.NoError(t, err)
+	return &testAdapter{
+		credentials: credentials,
+		dbaasClient: dbaasClient,
+		appPath:     fmt.Sprintf(dao.DefaultRouteFormat, dao.RootUrl, "v2") + "/" + credentials.AppName,
+	}
+}
+
+// config returns configuration of the adapter for fiber.BuildAdapter
+func (a *testAdapter) config() *config.AdapterConfig {
+	cfg := config.Default()
+	cfg.Namespace = testing2.Simplstr()
+	cfg.Adapter.Name = a.credentials.AppName
+	cfg.Adapter.Address = "adapter.svc:8080"
+	cfg.Adapter.Username = a.credentials.AdapterApiUser
+	cfg.Adapter.Password = a.credentials.AdapterApiPass
+	cfg.Adapter.PhysicalDatabaseId = a.credentials.AppName
+	return cfg
+}
+
+// prepareServer builds the adapter by testing.PrepareTestServer, the app is stopped when the test finishes
+func (a *testAdapter) prepareServer(t *testing.T, logger *zap.Logger, dbAdmin service.DbAdministration) *fiber2.AdapterServer {
+	cancel, server, err, _ := testing2.PrepareTestServer(a.dbaasClient, logger, dbAdmin, a.credentials, "")
+	if cancel != nil {
+		t.Cleanup(cancel)
+	}
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = server.App().Server().Shutdown() })
+	return server
+}
 
 func Test_FullFeaturedConfigV2(t *testing.T) {
 	logger := utils.GetLogger(true)
@@ -246,3 +344,845 @@ func Test_FullFeaturedConfigV2(t *testing.T) {
 		appCredentials.AppName, appCredentials.AdapterApiUser, appCredentials.AdapterApiPass, true, true,
 		appCredentials.BackupApiUser, appCredentials.BackupApiPass)
 }
+
+func Test_DrainingRejectsDatabaseCreation(t *testing.T) {
+	logger := utils.GetLogger(true)
+
+	dbAdminV2 := newTestDbAdmin(logger)
+	adapter := newTestAdapter(t)
+
+	server := adapter.prepareServer(t, logger, dbAdminV2)
+	testApp := server.App()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Drain(ctx)
+
+	appPath := adapter.appPath
+	resp, respErr := testing2.HandlerTest(logger, testApp,
+		http.MethodPost,
+		appPath+"/databases",
+		nil,
+		adapter.credentials.AdapterApiUser,
+		adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, respErr = testing2.HandlerTest(logger, testApp,
+		http.MethodGet,
+		appPath+"/databases",
+		nil,
+		adapter.credentials.AdapterApiUser,
+		adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	rootPath := fmt.Sprintf(dao.DefaultRouteFormat, dao.RootUrl, dbAdminV2.GetVersion())
+	resp, respErr = testing2.HandlerTest(logger, testApp,
+		http.MethodGet,
+		rootPath+"/physical_database/force_registration",
+		nil,
+		adapter.credentials.AdapterApiUser,
+		adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func Test_ProblemsOfAppCreatedByAdapter(t *testing.T) {
+	logger := utils.GetLogger(true)
+	dbAdminV2 := newTestDbAdmin(logger)
+	adapter := newTestAdapter(t)
+
+	cfg := adapter.config()
+	// app without problem error handler
+	app := fiber.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, fiber2.BuildAdapter(app, ctx, cfg, dbAdminV2, logger, fiber2.WithDbaasClient(adapter.dbaasClient)))
+
+	appPath := adapter.appPath
+	for _, target := range []string{appPath + "/databases", "/log-level"} {
+		resp, err := testing2.HandlerTest(logger, app, http.MethodGet, target, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, dao.ProblemContentType, resp.Header.Get("Content-Type"), target)
+		var problem dao.Problem
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+		assert.Equal(t, dao.UnauthorizedCode, problem.Code)
+	}
+	resp, err := testing2.HandlerTest(logger, app, http.MethodGet, appPath+"/unknown", nil, adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, dao.ProblemContentType, resp.Header.Get("Content-Type"))
+}
+
+func Test_VaultAuthMethodFromConfig(t *testing.T) {
+	logger := utils.GetLogger(true)
+	var logins []string
+	vaultServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logins = append(logins, r.URL.Path)
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"token","lease_duration":3600,"renewable":false}}`))
+	}))
+	defer vaultServer.Close()
+	secretIdFile := filepath.Join(t.TempDir(), "secret-id")
+	assert.NoError(t, os.WriteFile(secretIdFile, []byte("secret-id"), 0600))
+	adapter := newTestAdapter(t)
+
+	cfg := adapter.config()
+	cfg.Vault.Enabled = true
+	cfg.Vault.Address = vaultServer.URL
+	cfg.Vault.DbName = "postgres"
+	cfg.Vault.AuthMethod = "approle"
+	cfg.Vault.AppRoleRoleId = "role-id"
+	cfg.Vault.AppRoleSecretIdFile = secretIdFile
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, fiber2.BuildAdapter(fiber.New(), ctx, cfg, newTestDbAdmin(logger), logger, fiber2.WithDbaasClient(adapter.dbaasClient)))
+
+	assert.Equal(t, []string{"/v1/auth/approle/login"}, logins)
+}
+
+func Test_VaultMigrationRequiresVault(t *testing.T) {
+	logger := utils.GetLogger(true)
+
+	dbAdminV2 := newTestDbAdmin(logger)
+	adapter := newTestAdapter(t)
+
+	testApp := adapter.prepareServer(t, logger, dbAdminV2).App()
+
+	appPath := adapter.appPath
+	resp, respErr := testing2.HandlerTest(logger, testApp,
+		http.MethodGet,
+		appPath+"/migrate-to-vault",
+		nil,
+		adapter.credentials.AdapterApiUser,
+		adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, respErr = testing2.HandlerTest(logger, testApp,
+		http.MethodPost,
+		appPath+"/migrate-to-vault",
+		nil,
+		adapter.credentials.AdapterApiUser,
+		adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, dao.ProblemContentType, resp.Header.Get("Content-Type"))
+	var problem dao.Problem
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, dao.VaultDisabledCode, problem.Code)
+	assert.Equal(t, "urn:dbaas-adapter:problem:vault-disabled", problem.Type)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, appPath+"/migrate-to-vault", problem.Instance)
+	assert.Equal(t, resp.Header.Get("X-Request-ID"), problem.RequestId)
+	assert.NotEmpty(t, problem.RequestId)
+	assert.False(t, problem.Retryable)
+
+	// authentication failures are problem details too
+	resp, respErr = testing2.HandlerTest(logger, testApp, http.MethodGet, appPath+"/databases", nil)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+	problem = dao.Problem{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, dao.UnauthorizedCode, problem.Code)
+}
+
+func Test_RotatePassword(t *testing.T) {
+	logger := utils.GetLogger(true)
+
+	dbAdminV2 := newTestDbAdmin(logger)
+	dbAdminV2.dbs["rotated-db"] = struct {
+		user string
+		pass string
+	}{user: "rotated-user", pass: "old-password"}
+	adapter := newTestAdapter(t)
+
+	testApp := adapter.prepareServer(t, logger, dbAdminV2).App()
+
+	appPath := adapter.appPath
+	resp, respErr := testing2.HandlerTest(logger, testApp,
+		http.MethodPost,
+		appPath+"/databases/rotated-db/users/rotated-user/rotate",
+		nil,
+		adapter.credentials.AdapterApiUser,
+		adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var rotatedUser dao.CreatedUser
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&rotatedUser))
+	assert.Equal(t, "rotated-user", rotatedUser.Name)
+	assert.Equal(t, dbAdminV2.dbs["rotated-db"].pass, rotatedUser.ConnectionProperties["password"])
+	assert.NotEqual(t, "old-password", dbAdminV2.dbs["rotated-db"].pass)
+
+	resp, respErr = testing2.HandlerTest(logger, testApp,
+		http.MethodPost,
+		appPath+"/databases/rotated-db/users/unknown-user/rotate",
+		nil,
+		adapter.credentials.AdapterApiUser,
+		adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, respErr = testing2.HandlerTest(logger, testApp,
+		http.MethodGet,
+		appPath+"/databases/rotated-db/users/rotated-user/rotations",
+		nil,
+		adapter.credentials.AdapterApiUser,
+		adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var records []dao.PasswordRotationRecord
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&records))
+	if assert.Len(t, records, 1) {
+		assert.True(t, records[0].Success)
+		assert.Equal(t, dao.PasswordRotationManualTrigger, records[0].Trigger)
+	}
+}
+
+func Test_MultiplePhysicalDatabases(t *testing.T) {
+	logger := utils.GetLogger(true)
+	appName := testing2.Simplstr()
+	apiUser := testing2.Simplstr()
+	apiPass := testing2.Simplstr()
+	aggregatorUser := testing2.Simplstr()
+	aggregatorPass := testing2.Simplstr()
+
+	aggregatorServer := testing2.GetTestHttpAggregatorServer(aggregatorUser, aggregatorPass, appName, appName, false)
+	defer aggregatorServer.Close()
+
+	dbaasClient, err := dbaas.NewDbaasClient(aggregatorServer.URL, &dao.BasicAuth{Username: aggregatorUser, Password: aggregatorPass}, nil)
+	if err != nil {
+		assert.Fail(t, "Failed to create Dbaas Client", err)
+	}
+
+	physicalDatabase := func(ctx context.Context, id, routePrefix string) fiber2.PhysicalDatabaseServices {
+		dbAdmin := DbTestAdmin{
+			version: "v2",
+			logger:  logger,
+			dbs: make(map[string]struct {
+				user string
+				pass string
+			}),
+		}
+		adminService := service.NewCoreAdministrationService(testing2.Simplstr(), 8080, dbAdmin, logger, false, nil, "")
+		return fiber2.PhysicalDatabaseServices{
+			Id:           id,
+			RoutePrefix:  routePrefix,
+			AdminService: adminService,
+			PhysicalService: service.NewPhysicalRegistrationService(appName, logger, id, "adapter.svc:8080"+routePrefix,
+				dao.BasicAuth{Username: apiUser, Password: apiPass}, nil, dbaasClient, 150000, 60000, 5000, adminService, ctx),
+			Supports: dao.Supports{"users": true},
+		}
+	}
+
+	buildHandlers := func(ids ...string) func(app *fiber.App, ctx context.Context) error {
+		return func(app *fiber.App, ctx context.Context) error {
+			physicalDatabases := []fiber2.PhysicalDatabaseServices{physicalDatabase(ctx, "cluster-a", "/cluster-a")}
+			for _, id := range ids {
+				physicalDatabases = append(physicalDatabases, physicalDatabase(ctx, id, ""))
+			}
+			return fiber2.BuildFiberMultiDBaaSAdapterHandlers(fiber2.ServerFromContext(ctx), apiUser, apiPass, "/"+appName,
+				physicalDatabases, logger, false, testing2.Simplstr())
+		}
+	}
+	// ids must select physical databases unambiguously
+	cancelDuplicate, _, err := fiber2.GetFiberServer(buildHandlers("cluster-b", "cluster-b"))
+	cancelDuplicate()
+	assert.ErrorContains(t, err, "not unique")
+	cancelEmpty, _, err := fiber2.GetFiberServer(buildHandlers(""))
+	cancelEmpty()
+	assert.ErrorContains(t, err, "required")
+
+	cancelFunc, app, err := fiber2.GetFiberServer(buildHandlers("cluster-b", "cluster-c"))
+	defer cancelFunc()
+	assert.NoError(t, err)
+	defer app.Server().Shutdown()
+
+	appPath := fmt.Sprintf(dao.DefaultRouteFormat, dao.RootUrl, "v2") + "/" + appName
+
+	resp, respErr := testing2.HandlerTest(logger, app, http.MethodGet, "/cluster-a"+appPath+"/databases", nil, apiUser, apiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req := httptest.NewRequest(http.MethodGet, appPath+"/physical_database", nil)
+	req.SetBasicAuth(apiUser, apiPass)
+	req.Header.Set(fiber2.PhysicalDatabaseIdHeader, "cluster-c")
+	resp, respErr = app.Test(req)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var phyDb service.PhysicalDatabase
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&phyDb))
+	assert.Equal(t, "cluster-c", phyDb.Id)
+
+	resp, respErr = testing2.HandlerTest(logger, app, http.MethodGet, appPath+"/databases", nil, apiUser, apiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, appPath+"/databases", nil)
+	req.SetBasicAuth(apiUser, apiPass)
+	req.Header.Set(fiber2.PhysicalDatabaseIdHeader, "cluster-a")
+	resp, respErr = app.Test(req)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, respErr = testing2.HandlerTest(logger, app, http.MethodGet, "/health", nil)
+	assert.NoError(t, respErr)
+	var health dao.Health
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	assert.Len(t, health.PhysicalDatabases, 3)
+	assert.Contains(t, health.PhysicalDatabases, "cluster-a")
+}
+
+func Test_UpdateApiCredentials(t *testing.T) {
+	logger := utils.GetLogger(true)
+
+	dbAdminV2 := newTestDbAdmin(logger)
+	adapter := newTestAdapter(t)
+
+	server := adapter.prepareServer(t, logger, dbAdminV2)
+	testApp := server.App()
+
+	newUser, newPass := testing2.Simplstr(), testing2.Simplstr()
+	assert.NoError(t, server.UpdateApiCredentials(newUser, newPass))
+
+	resp, respErr := testing2.HandlerTest(logger, testApp, http.MethodPut, "/log-level", dao.LogLevel{Level: "debug"}, newUser, newPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var level dao.LogLevel
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&level))
+	assert.Equal(t, "DEBUG", level.Level)
+
+	// previous credentials are accepted until aggregator receives new ones
+	resp, respErr = testing2.HandlerTest(logger, testApp, http.MethodGet, "/log-level", nil, adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, respErr = testing2.HandlerTest(logger, testApp, http.MethodGet, "/log-level", nil, newUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, respErr = testing2.HandlerTest(logger, testApp, http.MethodPut, "/log-level", dao.LogLevel{Level: "verbose"}, newUser, newPass)
+	assert.NoError(t, respErr)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func Test_ManagementPortServesHealth(t *testing.T) {
+	logger := utils.GetLogger(true)
+
+	dbAdminV2 := DbTestAdmin{
+		version: "v2",
+		logger:  logger,
+		dbs: make(map[string]struct {
+			user string
+			pass string
+		}),
+	}
+	user, pass := testing2.Simplstr(), testing2.Simplstr()
+	aggregatorServer := testing2.GetTestHttpAggregatorServer(user, pass, "management", "management", false)
+	defer aggregatorServer.Close()
+	dbaasClient, err := dbaas.NewDbaasClient(aggregatorServer.URL, &dao.BasicAuth{Username: user, Password: pass}, nil)
+	assert.NoError(t, err)
+
+	cfg := config.Default()
+	cfg.Namespace = testing2.Simplstr()
+	cfg.Adapter.Name = "management"
+	cfg.Adapter.PhysicalDatabaseId = "management"
+	cfg.Adapter.Username = user
+	cfg.Adapter.Password = pass
+	cfg.Adapter.MetricsServiceName = testing2.Simplstr()
+	cancelFunc, server, appErr := fiber2.GetAdapterServer(func(app *fiber.App, ctx context.Context) error {
+		return fiber2.BuildAdapter(app, ctx, cfg, dbAdminV2, logger, fiber2.WithDbaasClient(dbaasClient))
+	}, fiber2.WithManagementPort(8081))
+	defer cancelFunc()
+	assert.NoError(t, appErr)
+
+	app, management := server.App(), server.ManagementApp()
+	assert.NotSame(t, app, management)
+	resp, err := testing2.HandlerTest(logger, management, http.MethodGet, "/health", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = testing2.HandlerTest(logger, app, http.MethodGet, "/health", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// management app recovers from panics like adapter app does
+	management.Get("/panic", func(c *fiber.Ctx) error { panic("management panic") })
+	resp, err = testing2.HandlerTest(logger, management, http.MethodGet, "/panic", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
+
+func Test_RunFiberServerReleasesPortsWhenListenerFails(t *testing.T) {
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer occupied.Close()
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	apiPort := free.Addr().(*net.TCPAddr).Port
+	assert.NoError(t, free.Close())
+
+	err = fiber2.RunFiberServer(apiPort, func(app *fiber.App, ctx context.Context) error { return nil },
+		fiber2.WithBindAddress("127.0.0.1"), fiber2.WithManagementPort(occupied.Addr().(*net.TCPAddr).Port))
+	assert.Error(t, err)
+	// API app does not start serving when management app can not listen
+	assert.Never(t, func() bool {
+		conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(apiPort)))
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func Test_MetricsPerApp(t *testing.T) {
+	logger := utils.GetLogger(true)
+	setUp := func(app *fiber.App, ctx context.Context) error {
+		err := fiber2.BuildFiberMultiDBaaSAdapterHandlers(fiber2.ServerFromContext(ctx), "user", "pass", "/metrics-test", nil, logger, false, "dbaas-adapter")
+		app.Get("/ping", func(c *fiber.Ctx) error { return c.SendString("pong") })
+		return err
+	}
+	registry := prometheus.NewRegistry()
+	cancelFirst, first, err := fiber2.GetFiberServer(setUp, fiber2.WithMetricsRegistry(registry, registry))
+	defer cancelFirst()
+	assert.NoError(t, err)
+	// second app with the same service name gets its own metrics
+	cancelSecond, secondServer, err := fiber2.GetAdapterServer(setUp)
+	defer cancelSecond()
+	assert.NoError(t, err)
+	second := secondServer.App()
+
+	_, err = testing2.HandlerTest(logger, first, http.MethodGet, "/ping", nil)
+	assert.NoError(t, err)
+	count, err := testutil.GatherAndCount(registry, "requests_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = testutil.GatherAndCount(secondServer.MetricsRegisterer().(prometheus.Gatherer), "requests_total")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// metrics are scraped without credentials by default
+	resp, err := testing2.HandlerTest(logger, second, http.MethodGet, "/metrics", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancelProtected, protected, err := fiber2.GetFiberServer(setUp, fiber2.WithProtectedMetrics(true))
+	defer cancelProtected()
+	assert.NoError(t, err)
+	resp, err = testing2.HandlerTest(logger, protected, http.MethodGet, "/metrics", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, err = testing2.HandlerTest(logger, protected, http.MethodGet, "/metrics", nil, "user", "pass")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = testing2.HandlerTest(logger, protected, http.MethodGet, "/swagger/index.html", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func Test_DatabaseCreationMetrics(t *testing.T) {
+	logger := utils.GetLogger(true)
+
+	dbAdmin := DbTestAdmin{
+		version: "v2",
+		logger:  logger,
+		dbs: make(map[string]struct {
+			user string
+			pass string
+		}),
+	}
+	registry := prometheus.NewRegistry()
+	metrics, err := service.NewMetrics(registry, "test")
+	assert.NoError(t, err)
+	administrationService := service.NewCoreAdministrationService("test-namespace", 8080, dbAdmin, logger, false, nil, "",
+		service.WithMetrics(metrics))
+
+	_, err = administrationService.CreateDatabase(context.Background(), dbAdmin.GetDefaultCreateRequest())
+	assert.NoError(t, err)
+	invalidPrefix := "invalid"
+	_, err = administrationService.CreateDatabase(context.Background(), dao.DbCreateRequest{NamePrefix: &invalidPrefix})
+	assert.Error(t, err)
+
+	expected := `
+# HELP dbaas_adapter_databases_created_total Number of logical database creations by result.
+# TYPE dbaas_adapter_databases_created_total counter
+dbaas_adapter_databases_created_total{engine="test",result="failure"} 1
+dbaas_adapter_databases_created_total{engine="test",result="success"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "dbaas_adapter_databases_created_total"))
+	count, err := testutil.GatherAndCount(registry, "dbaas_adapter_database_create_duration_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func Test_TraceContextIsPropagated(t *testing.T) {
+	logger := utils.GetLogger(true)
+	shutdown, err := utils.InitTracing(context.Background(), utils.TracingExporterNone, "", "test")
+	assert.NoError(t, err)
+	defer shutdown(context.Background())
+	exporter := tracetest.NewInMemoryExporter()
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(previousProvider)
+
+	dbAdminV2 := newTestDbAdmin(logger)
+	adapter := newTestAdapter(t)
+	testApp := adapter.prepareServer(t, logger, dbAdminV2).App()
+
+	appPath := adapter.appPath
+	req := httptest.NewRequest(http.MethodGet, appPath+"/databases", nil)
+	req.SetBasicAuth(adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := testApp.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID().String() == "4bf92f3577b34da6a3ce929d0e0e4736" {
+			spans[span.Name] = span
+		}
+	}
+	serverSpan, found := spans["GET "+appPath+"/databases"]
+	assert.True(t, found)
+	assert.Equal(t, "00f067aa0ba902b7", serverSpan.Parent.SpanID().String())
+	dbSpan, found := spans["DbAdministration.GetDatabases"]
+	assert.True(t, found)
+	assert.Equal(t, serverSpan.SpanContext.SpanID(), dbSpan.Parent.SpanID())
+}
+
+// panickingDbTestAdmin panics on migration to vault
+type panickingDbTestAdmin struct {
+	DbTestAdmin
+}
+
+func (d panickingDbTestAdmin) MigrateToVault(context.Context, string, string) error {
+	panic("migration to vault failed")
+}
+
+func Test_AuditOfMutatingOperations(t *testing.T) {
+	logger := utils.GetLogger(true)
+	dbAdminV2 := panickingDbTestAdmin{newTestDbAdmin(logger)}
+	adapter := newTestAdapter(t)
+
+	cfg := adapter.config()
+	var events bytes.Buffer
+	auditor := audit.NewAuditor(logger, audit.NewWriterSink(&events))
+	cancel, app, err := fiber2.GetFiberServer(func(app *fiber.App, ctx context.Context) error {
+		return fiber2.BuildAdapter(app, ctx, cfg, dbAdminV2, logger, fiber2.WithDbaasClient(adapter.dbaasClient))
+	}, fiber2.WithAuditor(auditor))
+	defer cancel()
+	assert.NoError(t, err)
+
+	appPath := adapter.appPath
+	resp, err := testing2.HandlerTest(logger, app, http.MethodPost, appPath+"/databases", dbAdminV2.GetDefaultCreateRequest(),
+		adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	// read operations are not audited
+	resp, err = testing2.HandlerTest(logger, app, http.MethodGet, appPath+"/databases", nil,
+		adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+	assert.Len(t, lines, 1)
+	var event audit.Event
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.Equal(t, "CreateDatabase", event.Operation)
+	assert.Equal(t, adapter.credentials.AdapterApiUser, event.Actor)
+	assert.Equal(t, audit.OutcomeSuccess, event.Outcome)
+	assert.Equal(t, http.StatusCreated, event.Status)
+	assert.NotEmpty(t, event.RequestId)
+	assert.Len(t, event.Resources, 2)
+	assert.NotContains(t, lines[0], adapter.credentials.AdapterApiPass)
+
+	// error returned by the handler is recorded with status of its problem
+	events.Reset()
+	resp, err = testing2.HandlerTest(logger, app, http.MethodPost, appPath+"/databases", map[string]any{"namePrefix": 1},
+		adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(events.Bytes()), &event))
+	assert.Equal(t, "CreateDatabase", event.Operation)
+	assert.Equal(t, audit.OutcomeFailure, event.Outcome)
+	assert.Equal(t, http.StatusBadRequest, event.Status)
+
+	// panic of the handler is recorded as failure
+	events.Reset()
+	resp, err = testing2.HandlerTest(logger, app, http.MethodPost, appPath+"/databases/db/migrate-to-vault/user", nil,
+		adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(events.Bytes()), &event))
+	assert.Equal(t, "MigrateToVault", event.Operation)
+	assert.Equal(t, audit.OutcomeFailure, event.Outcome)
+	assert.Equal(t, http.StatusInternalServerError, event.Status)
+}
+
+func Test_ConnectionPropertiesAreRedactedInLogs(t *testing.T) {
+	described := dao.LogicalDatabaseDescribedSingle{
+		ConnectionProperties: dao.ConnectionProperties{"username": "dbaas", "password": "qwerty"},
+	}
+	formatted := fmt.Sprintf("%+v", described)
+	assert.Contains(t, formatted, "username:dbaas")
+	assert.NotContains(t, formatted, "qwerty")
+	assert.NotContains(t, fmt.Sprintf("%v", dao.DbCreateRequest{DbName: "db", Password: "qwerty"}), "qwerty")
+
+	body, err := json.Marshal(described)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "qwerty")
+}
+
+func Test_RoleBasedAccessControl(t *testing.T) {
+	logger := utils.GetLogger(true)
+	dbAdminV2 := newTestDbAdmin(logger)
+	adapter := newTestAdapter(t)
+
+	principalsFile := filepath.Join(t.TempDir(), "principals.yaml")
+	assert.NoError(t, os.WriteFile(principalsFile, []byte(`
+- username: backup-tool
+  password: backup-pass
+  role: backup-operator
+- username: monitoring
+  password: monitoring-pass
+  role: read-only
+`), 0600))
+	cfg := adapter.config()
+	cfg.Adapter.PrincipalsFile = principalsFile
+	cancel, server, err := fiber2.GetAdapterServer(func(app *fiber.App, ctx context.Context) error {
+		return fiber2.BuildAdapter(app, ctx, cfg, dbAdminV2, logger, fiber2.WithDbaasClient(adapter.dbaasClient))
+	})
+	defer cancel()
+	assert.NoError(t, err)
+	app := server.App()
+
+	rootPath := fmt.Sprintf(dao.DefaultRouteFormat, dao.RootUrl, dbAdminV2.GetVersion())
+	appPath := rootPath + "/" + adapter.credentials.AppName
+	check := func(method, target string, body interface{}, expectedStatus int, creds ...string) {
+		resp, err := testing2.HandlerTest(logger, app, method, target, body, creds...)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedStatus, resp.StatusCode, "%s %s as %v", method, target, creds)
+	}
+
+	check(http.MethodGet, appPath+"/databases", nil, http.StatusOK, "monitoring", "monitoring-pass")
+	check(http.MethodPost, appPath+"/databases", dbAdminV2.GetDefaultCreateRequest(), http.StatusForbidden, "monitoring", "monitoring-pass")
+	check(http.MethodGet, appPath+"/databases", nil, http.StatusForbidden, "backup-tool", "backup-pass")
+	check(http.MethodPost, appPath+"/databases", dbAdminV2.GetDefaultCreateRequest(), http.StatusForbidden, "backup-tool", "backup-pass")
+	check(http.MethodGet, appPath+"/databases", nil, http.StatusUnauthorized, "backup-tool", "monitoring-pass")
+	check(http.MethodPost, appPath+"/databases", dbAdminV2.GetDefaultCreateRequest(), http.StatusCreated, adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+
+	check(http.MethodGet, rootPath+"/physical_database/force_registration", nil, http.StatusUnauthorized)
+	check(http.MethodGet, rootPath+"/physical_database/force_registration", nil, http.StatusForbidden, "monitoring", "monitoring-pass")
+	check(http.MethodGet, rootPath+"/physical_database/force_registration", nil, http.StatusAccepted, adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+
+	assert.Error(t, server.UpdateApiPrincipals([]dao.ApiPrincipal{{Username: "admin", Password: "pass", Role: "admin"}}))
+	assert.NoError(t, server.UpdateApiPrincipals(nil))
+	check(http.MethodGet, appPath+"/databases", nil, http.StatusUnauthorized, "monitoring", "monitoring-pass")
+}
+
+// staticTokens authenticates tokens by the map of token to username
+type staticTokens map[string]string
+
+func (s staticTokens) Authenticate(_ context.Context, token string) (auth.Identity, error) {
+	if username, found := s[token]; found {
+		return auth.Identity{Username: username}, nil
+	}
+	return auth.Identity{}, fmt.Errorf("unknown token")
+}
+
+func Test_BearerTokenAuthentication(t *testing.T) {
+	logger := utils.GetLogger(true)
+	dbAdminV2 := newTestDbAdmin(logger)
+	adapter := newTestAdapter(t)
+
+	cfg := adapter.config()
+	tokens := staticTokens{
+		"aggregator-token": "system:serviceaccount:dbaas:dbaas-aggregator",
+		"stranger-token":   "system:serviceaccount:default:default",
+		"impostor-token":   adapter.credentials.AdapterApiUser,
+	}
+	cancel, server, err := fiber2.GetAdapterServer(func(app *fiber.App, ctx context.Context) error {
+		return fiber2.BuildAdapter(app, ctx, cfg, dbAdminV2, logger, fiber2.WithDbaasClient(adapter.dbaasClient))
+	}, fiber2.WithTokenAuthenticator(tokens))
+	defer cancel()
+	assert.NoError(t, err)
+	app := server.App()
+	assert.NoError(t, server.UpdateApiPrincipals([]dao.ApiPrincipal{
+		{Username: "system:serviceaccount:dbaas:dbaas-aggregator", Role: dao.AggregatorRole},
+	}))
+
+	appPath := adapter.appPath
+	request := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, appPath+"/databases", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusOK, request("aggregator-token"))
+	assert.Equal(t, http.StatusForbidden, request("stranger-token"))
+	assert.Equal(t, http.StatusUnauthorized, request("forged-token"))
+	// token identity with username of aggregator credentials is not a principal
+	assert.Equal(t, http.StatusForbidden, request("impostor-token"))
+
+	// principal without password is not authenticated by basic auth
+	resp, err := testing2.HandlerTest(logger, app, http.MethodGet, appPath+"/databases", nil, "system:serviceaccount:dbaas:dbaas-aggregator", "")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp, err = testing2.HandlerTest(logger, app, http.MethodGet, appPath+"/databases", nil, adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func Test_OperationLimits(t *testing.T) {
+	logger := utils.GetLogger(true)
+	dbAdminV2 := newTestDbAdmin(logger)
+	adapter := newTestAdapter(t)
+
+	cfg := adapter.config()
+	limits, err := fiber2.ParseOperationLimits(nil, map[string]string{"read": "0.001"})
+	assert.NoError(t, err)
+	cancel, app, err := fiber2.GetFiberServer(func(app *fiber.App, ctx context.Context) error {
+		return fiber2.BuildAdapter(app, ctx, cfg, dbAdminV2, logger, fiber2.WithDbaasClient(adapter.dbaasClient))
+	}, fiber2.WithOperationLimits(limits, 0))
+	defer cancel()
+	assert.NoError(t, err)
+
+	appPath := adapter.appPath
+	resp, err := testing2.HandlerTest(logger, app, http.MethodGet, appPath+"/databases", nil, adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = testing2.HandlerTest(logger, app, http.MethodGet, appPath+"/databases", nil, adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+
+	// other classes are not limited
+	resp, err = testing2.HandlerTest(logger, app, http.MethodPost, appPath+"/databases", dbAdminV2.GetDefaultCreateRequest(), adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	_, err = fiber2.ParseOperationLimits(map[string]string{"unknown": "1"}, nil)
+	assert.Error(t, err)
+}
+
+// blockingDbTestAdmin lists databases when release is closed, started receives every listing
+type blockingDbTestAdmin struct {
+	DbTestAdmin
+	started chan struct{}
+	release chan struct{}
+}
+
+func (d blockingDbTestAdmin) GetDatabases(ctx context.Context) []string {
+	d.started <- struct{}{}
+	<-d.release
+	return d.DbTestAdmin.GetDatabases(ctx)
+}
+
+func Test_ConcurrencyLimit(t *testing.T) {
+	logger := utils.GetLogger(true)
+	dbAdminV2 := blockingDbTestAdmin{
+		DbTestAdmin: newTestDbAdmin(logger),
+		started:     make(chan struct{}, 2),
+		release:     make(chan struct{}),
+	}
+	adapter := newTestAdapter(t)
+
+	cfg := adapter.config()
+	limits, err := fiber2.ParseOperationLimits(map[string]string{"read": "1"}, nil)
+	assert.NoError(t, err)
+	registry := prometheus.NewRegistry()
+	cancel, server, err := fiber2.GetAdapterServer(func(app *fiber.App, ctx context.Context) error {
+		return fiber2.BuildAdapter(app, ctx, cfg, dbAdminV2, logger, fiber2.WithDbaasClient(adapter.dbaasClient))
+	}, fiber2.WithOperationLimits(limits, 500*time.Millisecond), fiber2.WithMetricsRegistry(registry, registry))
+	defer cancel()
+	assert.NoError(t, err)
+
+	appPath := adapter.appPath
+	listDatabases := func() *http.Response {
+		req := httptest.NewRequest(http.MethodGet, appPath+"/databases", nil)
+		req.SetBasicAuth(adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+		resp, err := server.App().Test(req, -1)
+		assert.NoError(t, err)
+		return resp
+	}
+	first := make(chan int, 1)
+	go func() { first <- listDatabases().StatusCode }()
+	<-dbAdminV2.started
+
+	resp := listDatabases()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "request is rejected when slot is not freed in time")
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+
+	queued := make(chan int, 1)
+	go func() { queued <- listDatabases().StatusCode }()
+	expected := `
+# HELP dbaas_adapter_operations_queued Number of adapter API requests waiting for concurrency or rate limit by operation class.
+# TYPE dbaas_adapter_operations_queued gauge
+dbaas_adapter_operations_queued{class="read",service="dbaas-adapter"} 1
+`
+	assert.Eventually(t, func() bool {
+		return testutil.GatherAndCompare(registry, strings.NewReader(expected), "dbaas_adapter_operations_queued") == nil
+	}, 400*time.Millisecond, 5*time.Millisecond)
+
+	// draining waits for the queued request
+	ctx, cancelDrain := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelDrain()
+	drained := make(chan struct{})
+	go func() {
+		server.Drain(ctx)
+		close(drained)
+	}()
+	assert.Never(t, func() bool {
+		select {
+		case <-drained:
+			return true
+		default:
+			return false
+		}
+	}, 50*time.Millisecond, 5*time.Millisecond)
+
+	close(dbAdminV2.release)
+	assert.Equal(t, http.StatusOK, <-first)
+	assert.Equal(t, http.StatusOK, <-queued)
+	<-drained
+}
+
+func Test_RequestValidation(t *testing.T) {
+	logger := utils.GetLogger(true)
+	dbAdminV2 := newTestDbAdmin(logger)
+	adapter := newTestAdapter(t)
+	app := adapter.prepareServer(t, logger, dbAdminV2).App()
+
+	appPath := adapter.appPath
+	violations := func(method, target string, body interface{}) []string {
+		resp, err := testing2.HandlerTest(logger, app, method, target, body, adapter.credentials.AdapterApiUser, adapter.credentials.AdapterApiPass)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var problem dao.Problem
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+		assert.Equal(t, dao.ValidationFailedCode, problem.Code)
+		return problem.Errors
+	}
+
+	invalidPrefix := "invalid prefix!"
+	assert.ElementsMatch(t, []string{
+		"Field 'metadata' failed validation: classifier",
+		"Field 'namePrefix' failed validation: nameprefix",
+		"Field 'role' failed validation: dbrole",
+	}, violations(http.MethodPost, appPath+"/databases", dao.DbCreateRequest{
+		Metadata:   map[string]interface{}{"classifier": nil, "microserviceName": "service"},
+		NamePrefix: &invalidPrefix,
+		Role:       "rw; drop",
+	}))
+	assert.Equal(t, []string{"Field '[1].kind' failed validation: required"},
+		violations(http.MethodPost, appPath+"/resources/bulk-drop", []dao.DbResource{{Kind: "database", Name: "db"}, {Name: "user"}}))
+	assert.Equal(t, []string{"Field 'databases[0].name' failed validation: required"},
+		violations(http.MethodPost, appPath+"/backups/"+testing2.Simplstr()+"/restoration", dao.RestorationRequest{Databases: []dao.DbInfo{{}}}))
+	assert.Equal(t, []string{"Field 'usernamePrefix' failed validation: nameprefix"},
+		violations(http.MethodPut, appPath+"/users", dao.UserCreateRequest{UsernamePrefix: "-user"}))
+	assert.Len(t, violations(http.MethodPut, appPath+"/databases/db/metadata", map[string]interface{}{"classifier": "namespace"}), 1)
+}
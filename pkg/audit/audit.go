@@ -0,0 +1,82 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit records mutating operations of adapter API: who did what with which resources and how it ended.
+// Events never contain passwords, request bodies or connection properties.
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Event is one audit record, sinks write it as a single JSON line
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Operation is the name of adapter API operation, e.g. CreateDatabase
+	Operation string `json:"operation"`
+	// Actor is the basic auth user of the request
+	Actor     string `json:"actor"`
+	RequestId string `json:"requestId,omitempty"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	// Resources are targets of the operation, e.g. "database/db-1" or "backupId=123"
+	Resources  []string `json:"resources,omitempty"`
+	Outcome    string   `json:"outcome"`
+	Status     int      `json:"status"`
+	DurationMs int64    `json:"durationMs"`
+}
+
+// Sink writes audit events to an append-only destination
+type Sink interface {
+	Write(event Event) error
+	Close(ctx context.Context) error
+}
+
+// Auditor sends every event to all the sinks
+type Auditor struct {
+	sinks  []Sink
+	logger *zap.Logger
+}
+
+func NewAuditor(logger *zap.Logger, sinks ...Sink) *Auditor {
+	return &Auditor{sinks: sinks, logger: logger}
+}
+
+// Record writes event to all the sinks, failures of sinks are logged and do not fail the operation
+func (a *Auditor) Record(event Event) {
+	for _, sink := range a.sinks {
+		if err := sink.Write(event); err != nil {
+			a.logger.Error(fmt.Sprintf("Failed to write audit event of %s operation: %v", event.Operation, err))
+		}
+	}
+}
+
+// Close flushes and closes all the sinks
+func (a *Auditor) Close(ctx context.Context) error {
+	var errs []error
+	for _, sink := range a.sinks {
+		errs = append(errs, sink.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
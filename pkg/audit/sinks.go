@@ -0,0 +1,214 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	webhookQueueSize = 1024
+	webhookRetries   = 3
+)
+
+// webhookRetryDelay is multiplied by the number of the failed attempt
+var webhookRetryDelay = time.Second
+
+// writerSink writes events as JSON lines
+type writerSink struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	closer io.Closer
+}
+
+// NewWriterSink writes events to the writer, e.g. os.Stdout
+func NewWriterSink(writer io.Writer) Sink {
+	return &writerSink{writer: writer}
+}
+
+// NewFileSink appends events to the file, the file is created if it does not exist
+func NewFileSink(fileName string) (Sink, error) {
+	file, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open audit file: %w", err)
+	}
+	return &writerSink{writer: file, closer: file}, nil
+}
+
+func (s *writerSink) Write(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err = s.writer.Write(line)
+	return err
+}
+
+func (s *writerSink) Close(context.Context) error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// webhookDelivery is the event which is sent again at due time after attempts failed
+type webhookDelivery struct {
+	event    Event
+	attempts int
+	due      time.Time
+}
+
+// webhookSink posts events to the webhook in background, so slow webhook does not delay API requests
+type webhookSink struct {
+	url           string
+	authorization string
+	client        utils.HttpClient
+	logger        *zap.Logger
+	events        chan Event
+	done          chan struct{}
+	// mutex guards events channel from writes after close
+	mutex  sync.RWMutex
+	closed bool
+}
+
+// NewWebhookSink posts every event as JSON to the url with Authorization header if it is not empty.
+// Failed requests are retried later while next events are sent, events are dropped with error log
+// when the queue is full.
+func NewWebhookSink(url, authorization string, client utils.HttpClient, logger *zap.Logger) Sink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	sink := &webhookSink{
+		url:           url,
+		authorization: authorization,
+		client:        client,
+		logger:        logger,
+		events:        make(chan Event, webhookQueueSize),
+		done:          make(chan struct{}),
+	}
+	go sink.run()
+	return sink
+}
+
+func (s *webhookSink) Write(event Event) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.closed {
+		return fmt.Errorf("audit webhook sink is closed")
+	}
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return fmt.Errorf("audit webhook queue is full")
+	}
+}
+
+// run sends queued events and retries failed ones when they are due, until the sink is closed and retries are done
+func (s *webhookSink) run() {
+	defer close(s.done)
+	events := s.events
+	var retries []webhookDelivery
+	for events != nil || len(retries) > 0 {
+		var due <-chan time.Time
+		if len(retries) > 0 {
+			due = time.After(time.Until(retries[0].due))
+		}
+		select {
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			retries = s.deliver(webhookDelivery{event: event}, retries)
+		case <-due:
+			delivery := retries[0]
+			retries = s.deliver(delivery, retries[1:])
+		}
+	}
+}
+
+// deliver posts the event and returns retries with the event added if it has to be sent again
+func (s *webhookSink) deliver(delivery webhookDelivery, retries []webhookDelivery) []webhookDelivery {
+	err := s.post(delivery.event)
+	if err == nil {
+		return retries
+	}
+	delivery.attempts++
+	if delivery.attempts >= webhookRetries || len(retries) >= webhookQueueSize {
+		s.logger.Error(fmt.Sprintf("Failed to send audit event of %s operation to webhook: %v", delivery.event.Operation, err))
+		return retries
+	}
+	delivery.due = time.Now().Add(time.Duration(delivery.attempts) * webhookRetryDelay)
+	retries = append(retries, delivery)
+	slices.SortStableFunc(retries, func(a, b webhookDelivery) int { return a.due.Compare(b.due) })
+	return retries
+}
+
+func (s *webhookSink) post(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+// Close sends queued events and retries failed ones until the context is done
+func (s *webhookSink) Close(ctx context.Context) error {
+	s.mutex.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+	s.mutex.Unlock()
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d audit events are not sent to webhook: %w", len(s.events), ctx.Err())
+	}
+}
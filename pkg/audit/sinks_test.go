@@ -0,0 +1,62 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSink_RetryDoesNotBlockQueue(t *testing.T) {
+	previousDelay := webhookRetryDelay
+	webhookRetryDelay = 50 * time.Millisecond
+	defer func() { webhookRetryDelay = previousDelay }()
+
+	var mutex sync.Mutex
+	var received []string
+	failed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		mutex.Lock()
+		defer mutex.Unlock()
+		received = append(received, event.Operation)
+		if event.Operation == "CreateDatabase" && !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, "", nil, utils.GetLogger())
+	assert.NoError(t, sink.Write(Event{Operation: "CreateDatabase"}))
+	assert.NoError(t, sink.Write(Event{Operation: "DropResources"}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, sink.Close(ctx))
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []string{"CreateDatabase", "DropResources", "CreateDatabase"}, received,
+		"next event is sent before the failed one is retried")
+}
@@ -0,0 +1,57 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth authenticates bearer tokens of adapter API requests, e.g. Kubernetes service account tokens.
+// Authenticated identities are mapped to adapter API principals by username.
+package auth
+
+import (
+	"context"
+	"strings"
+)
+
+// ServiceAccountUsernamePrefix starts usernames of Kubernetes service accounts: system:serviceaccount:<namespace>:<name>
+const ServiceAccountUsernamePrefix = "system:serviceaccount:"
+
+// Identity is the caller proven by the token
+type Identity struct {
+	// Username is matched with usernames of adapter API principals
+	Username string
+	// Namespace and ServiceAccount are set if the caller is a Kubernetes service account
+	Namespace      string
+	ServiceAccount string
+}
+
+// TokenAuthenticator validates bearer token and returns identity of its owner
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (Identity, error)
+}
+
+// ServiceAccountIdentity returns identity of Kubernetes service account
+func ServiceAccountIdentity(namespace, name string) Identity {
+	return Identity{
+		Username:       ServiceAccountUsernamePrefix + namespace + ":" + name,
+		Namespace:      namespace,
+		ServiceAccount: name,
+	}
+}
+
+// identityOfUsername fills namespace and service account of Kubernetes service account usernames
+func identityOfUsername(username string) Identity {
+	if namespace, name, found := strings.Cut(strings.TrimPrefix(username, ServiceAccountUsernamePrefix), ":"); found &&
+		strings.HasPrefix(username, ServiceAccountUsernamePrefix) {
+		return ServiceAccountIdentity(namespace, name)
+	}
+	return Identity{Username: username}
+}
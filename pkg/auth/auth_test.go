@@ -0,0 +1,141 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signToken(t *testing.T, key *rsa.PrivateKey, keyId string, claims ...interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", keyId))
+	require.NoError(t, err)
+	builder := jwt.Signed(signer)
+	for _, claim := range claims {
+		builder = builder.Claims(claim)
+	}
+	token, err := builder.Serialize()
+	require.NoError(t, err)
+	return token
+}
+
+func TestJwksAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "key-1", Algorithm: "RS256", Use: "sig"}}})
+	require.NoError(t, err)
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, jwks, 0600))
+	authenticator := NewJwksFileAuthenticator(jwksFile, "https://kubernetes.default.svc", "dbaas-adapter")
+
+	claims := jwt.Claims{
+		Issuer:   "https://kubernetes.default.svc",
+		Subject:  "system:serviceaccount:backup:backup-tool",
+		Audience: jwt.Audience{"dbaas-adapter"},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+	k8sClaims := map[string]interface{}{
+		"kubernetes.io": map[string]interface{}{
+			"namespace":      "backup",
+			"serviceaccount": map[string]interface{}{"name": "backup-tool"},
+		},
+	}
+
+	t.Run("ServiceAccount", func(t *testing.T) {
+		identity, err := authenticator.Authenticate(context.Background(), signToken(t, key, "key-1", claims, k8sClaims))
+		assert.NoError(t, err)
+		assert.Equal(t, ServiceAccountIdentity("backup", "backup-tool"), identity)
+	})
+	t.Run("Subject", func(t *testing.T) {
+		identity, err := authenticator.Authenticate(context.Background(), signToken(t, key, "key-1", claims))
+		assert.NoError(t, err)
+		assert.Equal(t, "system:serviceaccount:backup:backup-tool", identity.Username)
+		assert.Equal(t, "backup", identity.Namespace)
+	})
+	t.Run("WrongKey", func(t *testing.T) {
+		_, err := authenticator.Authenticate(context.Background(), signToken(t, otherKey, "key-1", claims))
+		assert.Error(t, err)
+	})
+	t.Run("WrongAudience", func(t *testing.T) {
+		wrongAudience := claims
+		wrongAudience.Audience = jwt.Audience{"vault"}
+		_, err := authenticator.Authenticate(context.Background(), signToken(t, key, "key-1", wrongAudience))
+		assert.Error(t, err)
+	})
+	t.Run("Expired", func(t *testing.T) {
+		expired := claims
+		expired.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+		_, err := authenticator.Authenticate(context.Background(), signToken(t, key, "key-1", expired))
+		assert.Error(t, err)
+	})
+	t.Run("NotJwt", func(t *testing.T) {
+		_, err := authenticator.Authenticate(context.Background(), "token")
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenReviewAuthenticator(t *testing.T) {
+	var reviews atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reviews.Add(1)
+		assert.Equal(t, "Bearer adapter-token", r.Header.Get("Authorization"))
+		var review tokenReview
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		assert.Equal(t, []string{"dbaas-adapter"}, review.Spec.Audiences)
+		review.Status = &tokenReviewStatus{Authenticated: review.Spec.Token == "valid"}
+		if review.Status.Authenticated {
+			review.Status.User.Username = "system:serviceaccount:dbaas:dbaas-aggregator"
+		} else {
+			review.Status.Error = "token is expired"
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(review)
+	}))
+	defer server.Close()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("adapter-token\n"), 0600))
+	authenticator, err := NewTokenReviewAuthenticator(server.URL, tokenFile, "", "dbaas-adapter", server.Client())
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		identity, err := authenticator.Authenticate(context.Background(), "valid")
+		assert.NoError(t, err)
+		assert.Equal(t, ServiceAccountIdentity("dbaas", "dbaas-aggregator"), identity)
+	}
+	assert.Equal(t, int32(1), reviews.Load(), "review of the same token is cached")
+
+	for i := 0; i < 2; i++ {
+		_, err = authenticator.Authenticate(context.Background(), "invalid")
+		assert.ErrorContains(t, err, "token is expired")
+	}
+	assert.Equal(t, int32(2), reviews.Load(), "rejection of the same token is cached")
+}
@@ -0,0 +1,213 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+const (
+	// jwksRefreshInterval is the age of keys after which they are loaded again
+	jwksRefreshInterval = 5 * time.Minute
+	// jwksMinRefreshInterval limits loading of keys when tokens are signed by unknown keys
+	jwksMinRefreshInterval = 30 * time.Second
+	// clockSkew is the leeway of expiration and not before claims
+	clockSkew = time.Minute
+)
+
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512, jose.ES256, jose.ES384, jose.ES512, jose.EdDSA,
+}
+
+// kubernetesClaims are claims of projected service account tokens
+type kubernetesClaims struct {
+	Kubernetes *struct {
+		Namespace      string `json:"namespace"`
+		ServiceAccount struct {
+			Name string `json:"name"`
+		} `json:"serviceaccount"`
+	} `json:"kubernetes.io,omitempty"`
+}
+
+// jwksAuthenticator validates signature of JWT locally with keys of JSON Web Key Set
+type jwksAuthenticator struct {
+	load     func(ctx context.Context) (*jose.JSONWebKeySet, error)
+	issuer   string
+	audience string
+
+	// loading is held while keys are loaded, so mutex is not held during the request
+	loading  sync.Mutex
+	mutex    sync.Mutex
+	keys     *jose.JSONWebKeySet
+	loadedAt time.Time
+}
+
+// NewJwksFileAuthenticator validates JWT with keys of JWKS file, e.g. mounted config map with keys of Kubernetes API server.
+// Issuer and audience are not checked if they are empty.
+func NewJwksFileAuthenticator(fileName, issuer, audience string) TokenAuthenticator {
+	return &jwksAuthenticator{
+		load: func(context.Context) (*jose.JSONWebKeySet, error) {
+			content, err := os.ReadFile(fileName)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read JWKS: %w", err)
+			}
+			return parseJwks(content)
+		},
+		issuer:   issuer,
+		audience: audience,
+	}
+}
+
+// NewJwksUrlAuthenticator validates JWT with keys served by JWKS endpoint, e.g. /openid/v1/jwks of Kubernetes API server.
+// Keys are reloaded every few minutes and when token is signed by unknown key.
+func NewJwksUrlAuthenticator(url, issuer, audience string, client utils.HttpClient) TokenAuthenticator {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &jwksAuthenticator{
+		load: func(ctx context.Context) (*jose.JSONWebKeySet, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := utils.DoTraced(client, req)
+			if err != nil {
+				return nil, fmt.Errorf("cannot get JWKS: %w", err)
+			}
+			defer resp.Body.Close()
+			content, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read JWKS: %w", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("JWKS endpoint responded with status %s", resp.Status)
+			}
+			return parseJwks(content)
+		},
+		issuer:   issuer,
+		audience: audience,
+	}
+}
+
+func parseJwks(content []byte) (*jose.JSONWebKeySet, error) {
+	var keys jose.JSONWebKeySet
+	if err := json.Unmarshal(content, &keys); err != nil {
+		return nil, fmt.Errorf("cannot parse JWKS: %w", err)
+	}
+	return &keys, nil
+}
+
+// keySet returns cached keys, they are loaded again if they are old or the key is not found in them.
+// Keys are loaded by one request at a time, other requests use previous keys meanwhile.
+func (a *jwksAuthenticator) keySet(ctx context.Context, keyId string) (*jose.JSONWebKeySet, error) {
+	keys, fresh := a.cachedKeys(keyId)
+	if fresh {
+		return keys, nil
+	}
+	if keys != nil {
+		if !a.loading.TryLock() {
+			return keys, nil
+		}
+	} else {
+		a.loading.Lock()
+	}
+	defer a.loading.Unlock()
+	// keys may be loaded by another request while waiting
+	if keys, fresh = a.cachedKeys(keyId); fresh {
+		return keys, nil
+	}
+
+	loaded, err := a.load(ctx)
+	if err != nil {
+		if keys == nil {
+			return nil, err
+		}
+		// previous keys are used until keys are loaded
+		return keys, nil
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.keys = loaded
+	a.loadedAt = time.Now()
+	return loaded, nil
+}
+
+// cachedKeys returns cached keys and whether they need not be loaded again
+func (a *jwksAuthenticator) cachedKeys(keyId string) (*jose.JSONWebKeySet, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	age := time.Since(a.loadedAt)
+	unknownKey := a.keys != nil && len(a.keys.Key(keyId)) == 0
+	stale := a.keys == nil || age > jwksRefreshInterval || (unknownKey && age > jwksMinRefreshInterval)
+	return a.keys, !stale
+}
+
+func (a *jwksAuthenticator) Authenticate(ctx context.Context, token string) (Identity, error) {
+	parsed, err := jwt.ParseSigned(token, signatureAlgorithms)
+	if err != nil {
+		return Identity{}, fmt.Errorf("invalid token: %w", err)
+	}
+	var keyId string
+	for _, header := range parsed.Headers {
+		if header.KeyID != "" {
+			keyId = header.KeyID
+			break
+		}
+	}
+	keys, err := a.keySet(ctx, keyId)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	// key is selected from the set by key id, token without key id can be verified only by single key
+	var key interface{} = keys
+	if keyId == "" && len(keys.Keys) == 1 {
+		key = keys.Keys[0].Key
+	}
+	var claims jwt.Claims
+	var k8sClaims kubernetesClaims
+	if err = parsed.Claims(key, &claims, &k8sClaims); err != nil {
+		return Identity{}, fmt.Errorf("invalid token signature: %w", err)
+	}
+	expected := jwt.Expected{Issuer: a.issuer}
+	if a.audience != "" {
+		expected.AnyAudience = jwt.Audience{a.audience}
+	}
+	if err = claims.ValidateWithLeeway(expected, clockSkew); err != nil {
+		return Identity{}, fmt.Errorf("invalid token claims: %w", err)
+	}
+	if claims.Expiry == nil {
+		return Identity{}, fmt.Errorf("token does not expire")
+	}
+
+	if k8sClaims.Kubernetes != nil && k8sClaims.Kubernetes.Namespace != "" && k8sClaims.Kubernetes.ServiceAccount.Name != "" {
+		return ServiceAccountIdentity(k8sClaims.Kubernetes.Namespace, k8sClaims.Kubernetes.ServiceAccount.Name), nil
+	}
+	if claims.Subject == "" {
+		return Identity{}, fmt.Errorf("token has no subject")
+	}
+	return identityOfUsername(claims.Subject), nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+)
+
+const (
+	DefaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	DefaultServiceAccountCAFile    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+	// DefaultTokenReviewUrl is TokenReview API of Kubernetes API server of the cluster the adapter is deployed to
+	DefaultTokenReviewUrl = "https://kubernetes.default.svc/apis/authentication.k8s.io/v1/tokenreviews"
+
+	// tokenReviewCacheTTL is the time result of token review is reused, so every request does not call the API
+	tokenReviewCacheTTL = time.Minute
+	// tokenReviewNegativeCacheTTL is the time rejection of token is reused, so invalid tokens do not flood the API
+	tokenReviewNegativeCacheTTL = 10 * time.Second
+)
+
+type tokenReview struct {
+	ApiVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Spec       tokenReviewSpec    `json:"spec"`
+	Status     *tokenReviewStatus `json:"status,omitempty"`
+}
+
+type tokenReviewSpec struct {
+	Token     string   `json:"token"`
+	Audiences []string `json:"audiences,omitempty"`
+}
+
+type tokenReviewStatus struct {
+	Authenticated bool     `json:"authenticated"`
+	Audiences     []string `json:"audiences,omitempty"`
+	Error         string   `json:"error,omitempty"`
+	User          struct {
+		Username string `json:"username"`
+	} `json:"user"`
+}
+
+// reviewedToken is identity of valid token or error of rejected one
+type reviewedToken struct {
+	identity Identity
+	err      error
+	until    time.Time
+}
+
+// tokenReviewAuthenticator asks TokenReview API whether the token is valid
+type tokenReviewAuthenticator struct {
+	url       string
+	tokenFile string
+	audience  string
+	client    utils.HttpClient
+
+	mutex sync.Mutex
+	// reviewed keeps identities of valid tokens and errors of rejected ones by token hash
+	reviewed map[[sha256.Size]byte]reviewedToken
+}
+
+// NewTokenReviewAuthenticator validates tokens with Kubernetes TokenReview compatible endpoint.
+// Requests to the endpoint are authenticated by the token from tokenFile, e.g. service account token of the adapter,
+// which needs permission to create tokenreviews. The token must be issued for the audience if it is not empty.
+// Client trusts caFile if client is nil.
+func NewTokenReviewAuthenticator(url, tokenFile, caFile, audience string, client utils.HttpClient) (TokenAuthenticator, error) {
+	if client == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if caFile != "" {
+			caCertificates, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read TokenReview CA: %w", err)
+			}
+			rootCAs := x509.NewCertPool()
+			if !rootCAs.AppendCertsFromPEM(caCertificates) {
+				return nil, fmt.Errorf("no certificates found in %s", caFile)
+			}
+			transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+		}
+		client = &http.Client{Transport: transport, Timeout: 10 * time.Second}
+	}
+	return &tokenReviewAuthenticator{
+		url:       url,
+		tokenFile: tokenFile,
+		audience:  audience,
+		client:    client,
+		reviewed:  make(map[[sha256.Size]byte]reviewedToken),
+	}, nil
+}
+
+func (a *tokenReviewAuthenticator) Authenticate(ctx context.Context, token string) (Identity, error) {
+	hash := sha256.Sum256([]byte(token))
+	a.mutex.Lock()
+	cached, found := a.reviewed[hash]
+	a.mutex.Unlock()
+	if found && time.Now().Before(cached.until) {
+		return cached.identity, cached.err
+	}
+
+	// failed review is not cached, the token may be valid
+	status, err := a.review(ctx, token)
+	if err != nil {
+		return Identity{}, err
+	}
+	if !status.Authenticated {
+		err = fmt.Errorf("token is not authenticated")
+		if status.Error != "" {
+			err = fmt.Errorf("token is not authenticated: %s", status.Error)
+		}
+		a.cache(hash, reviewedToken{err: err}, tokenReviewNegativeCacheTTL)
+		return Identity{}, err
+	}
+	identity := identityOfUsername(status.User.Username)
+	a.cache(hash, reviewedToken{identity: identity}, tokenReviewCacheTTL)
+	return identity, nil
+}
+
+// cache keeps result of token review for ttl and deletes expired results
+func (a *tokenReviewAuthenticator) cache(hash [sha256.Size]byte, reviewed reviewedToken, ttl time.Duration) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	now := time.Now()
+	for key, cached := range a.reviewed {
+		if now.After(cached.until) {
+			delete(a.reviewed, key)
+		}
+	}
+	reviewed.until = now.Add(ttl)
+	a.reviewed[hash] = reviewed
+}
+
+func (a *tokenReviewAuthenticator) review(ctx context.Context, token string) (*tokenReviewStatus, error) {
+	request := tokenReview{
+		ApiVersion: "authentication.k8s.io/v1",
+		Kind:       "TokenReview",
+		Spec:       tokenReviewSpec{Token: token},
+	}
+	if a.audience != "" {
+		request.Spec.Audiences = []string{a.audience}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.tokenFile != "" {
+		// projected service account token is rotated by kubelet, so it is read every time
+		reviewerToken, err := os.ReadFile(a.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read token of TokenReview requests: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(reviewerToken)))
+	}
+	resp, err := utils.DoTraced(a.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot review token: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read token review: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("TokenReview endpoint responded with status %s: %s", resp.Status, utils.RedactSecrets(string(respBody)))
+	}
+	var review tokenReview
+	if err = json.Unmarshal(respBody, &review); err != nil {
+		return nil, fmt.Errorf("cannot parse token review: %w", err)
+	}
+	if review.Status == nil {
+		return nil, fmt.Errorf("token review has no status")
+	}
+	return review.Status, nil
+}
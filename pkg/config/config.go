@@ -0,0 +1,216 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package config contains typed adapter configuration. Every key can be set in YAML file, environment variable
+// or command line flag, see docs/configuration.md for the list of keys.
+package config
+
+// AdapterConfig is the configuration of dbaas adapter. Values are taken from defaults, YAML file,
+// environment variables and command line flags, the latter source wins.
+type AdapterConfig struct {
+	// Namespace where adapter is deployed
+	Namespace string `yaml:"namespace" env:"CLOUD_NAMESPACE" validate:"required"`
+	// CloudPublicHost is used in vault role names
+	CloudPublicHost  string                  `yaml:"cloudPublicHost" env:"CLOUD_PUBLIC_HOST"`
+	Adapter          AdapterSection          `yaml:"adapter"`
+	Server           ServerSection           `yaml:"server"`
+	Aggregator       AggregatorSection       `yaml:"aggregator"`
+	Registration     RegistrationSection     `yaml:"registration"`
+	Backup           BackupSection           `yaml:"backup"`
+	Vault            VaultSection            `yaml:"vault"`
+	PasswordRotation PasswordRotationSection `yaml:"passwordRotation"`
+	Log              LogSection              `yaml:"log"`
+	Tracing          TracingSection          `yaml:"tracing"`
+	Audit            AuditSection            `yaml:"audit"`
+	Auth             AuthSection             `yaml:"auth"`
+	Limits           LimitsSection           `yaml:"limits"`
+}
+
+type AdapterSection struct {
+	// Name of the adapter API, e.g. "postgresql", used as application path and physical database type
+	Name string `yaml:"name" env:"ADAPTER_NAME" validate:"required"`
+	// Address of the adapter registered in dbaas aggregator
+	Address  string `yaml:"address" env:"ADAPTER_ADDRESS" validate:"required"`
+	Port     int    `yaml:"port" env:"ADAPTER_PORT" default:"8080" validate:"min=1,max=65535"`
+	Username string `yaml:"username" env:"ADAPTER_USERNAME" validate:"required_without=CredentialsDir"`
+	Password string `yaml:"password" env:"ADAPTER_PASSWORD" validate:"required_without=CredentialsDir"`
+	// CredentialsDir is the mounted secret with username and password of adapter API, they are reloaded on change
+	CredentialsDir string `yaml:"credentialsDir" env:"ADAPTER_CREDENTIALS_DIR"`
+	// PrincipalsFile is YAML list of additional users of adapter API with username, password and role:
+	// aggregator, backup-operator or read-only. It is reloaded on change, e.g. mounted secret.
+	PrincipalsFile string `yaml:"principalsFile" env:"ADAPTER_PRINCIPALS_FILE"`
+	// PhysicalDatabaseId is the id of physical database registered in dbaas aggregator
+	PhysicalDatabaseId string `yaml:"physicalDatabaseId" env:"PHYSICAL_DATABASE_ID" validate:"required"`
+	// Labels of physical database registered in dbaas aggregator
+	Labels map[string]string `yaml:"labels" env:"ADAPTER_LABELS"`
+	// RoHost is the host of read only replicas
+	RoHost             string `yaml:"roHost" env:"RO_HOST"`
+	Profiler           bool   `yaml:"profiler" env:"PROFILER_ENABLED"`
+	MetricsServiceName string `yaml:"metricsServiceName" env:"METRICS_SERVICE_NAME" default:"dbaas-adapter"`
+	// InternalTlsEnabled serves adapter API with TLS
+	InternalTlsEnabled bool `yaml:"internalTlsEnabled" env:"INTERNAL_TLS_ENABLED"`
+	// GracefulShutdownTimeoutSec limits the whole shutdown sequence
+	GracefulShutdownTimeoutSec int `yaml:"gracefulShutdownTimeoutSec" env:"GRACEFUL_SHUTDOWN_TIMEOUT_SEC" default:"25" validate:"min=0"`
+	// ReloadIntervalSec is the interval of checks of mounted credentials, certificates and log level file
+	ReloadIntervalSec int `yaml:"reloadIntervalSec" env:"RELOAD_INTERVAL_SEC" default:"30" validate:"min=1"`
+}
+
+// ServerSection configures listeners of adapter API and management endpoints
+type ServerSection struct {
+	// BindAddress is the interface address listeners are bound to, all interfaces if it is empty
+	BindAddress string `yaml:"bindAddress" env:"SERVER_BIND_ADDRESS" validate:"omitempty,ip"`
+	// ManagementPort serves health, metrics, pprof and log level endpoints, they are served on API port if it is 0
+	ManagementPort  int `yaml:"managementPort" env:"MANAGEMENT_PORT" validate:"min=0,max=65535"`
+	ReadTimeoutSec  int `yaml:"readTimeoutSec" env:"SERVER_READ_TIMEOUT_SEC" default:"60" validate:"min=0"`
+	WriteTimeoutSec int `yaml:"writeTimeoutSec" env:"SERVER_WRITE_TIMEOUT_SEC" default:"60" validate:"min=0"`
+	IdleTimeoutSec  int `yaml:"idleTimeoutSec" env:"SERVER_IDLE_TIMEOUT_SEC" default:"120" validate:"min=0"`
+	// BodyLimitBytes limits size of request body
+	BodyLimitBytes int    `yaml:"bodyLimitBytes" env:"SERVER_BODY_LIMIT_BYTES" default:"4194304" validate:"min=1"`
+	TlsCertFile    string `yaml:"tlsCertFile" env:"TLS_CERT_FILE" default:"/certs/tls.crt"`
+	TlsKeyFile     string `yaml:"tlsKeyFile" env:"TLS_KEY_FILE" default:"/certs/tls.key"`
+	TlsMinVersion  string `yaml:"tlsMinVersion" env:"TLS_MIN_VERSION" default:"1.2" validate:"oneof=1.2 1.3"`
+	// TlsCipherSuites is comma separated list of TLS 1.2 cipher suites, Go defaults are used if it is empty
+	TlsCipherSuites string `yaml:"tlsCipherSuites" env:"TLS_CIPHER_SUITES"`
+	// ClientCAFile enables verification of client certificates by the CA
+	ClientCAFile       string `yaml:"clientCAFile" env:"TLS_CLIENT_CA_FILE" validate:"required_if=ClientCertRequired true"`
+	ClientCertRequired bool   `yaml:"clientCertRequired" env:"TLS_CLIENT_CERT_REQUIRED"`
+	// ProtectMetrics requires read-only role for metrics and swagger endpoints
+	ProtectMetrics bool `yaml:"protectMetrics" env:"SERVER_PROTECT_METRICS"`
+}
+
+type AggregatorSection struct {
+	Address  string `yaml:"address" env:"DBAAS_AGGREGATOR_ADDRESS" validate:"required,url"`
+	Username string `yaml:"username" env:"DBAAS_AGGREGATOR_USERNAME" validate:"required_without=CredentialsDir"`
+	Password string `yaml:"password" env:"DBAAS_AGGREGATOR_PASSWORD" validate:"required_without=CredentialsDir"`
+	// CredentialsDir is the mounted secret with username and password of dbaas aggregator, they are reloaded on change
+	CredentialsDir string `yaml:"credentialsDir" env:"DBAAS_AGGREGATOR_CREDENTIALS_DIR"`
+}
+
+type RegistrationSection struct {
+	FixedDelayMs int `yaml:"fixedDelayMs" env:"REGISTRATION_FIXED_DELAY_MS" default:"150000" validate:"min=1"`
+	RetryTimeMs  int `yaml:"retryTimeMs" env:"REGISTRATION_RETRY_TIME_MS" default:"60000" validate:"min=0"`
+	RetryDelayMs int `yaml:"retryDelayMs" env:"REGISTRATION_RETRY_DELAY_MS" default:"5000" validate:"min=1"`
+	// AdditionalRolesConcurrency limits the number of additional roles being created at the same time
+	AdditionalRolesConcurrency int `yaml:"additionalRolesConcurrency" env:"ADDITIONAL_ROLES_CONCURRENCY" default:"4" validate:"min=1"`
+	// AdditionalRolesCheckpointDir keeps progress of additional roles creation between restarts
+	AdditionalRolesCheckpointDir string `yaml:"additionalRolesCheckpointDir" env:"ADDITIONAL_ROLES_CHECKPOINT_DIR"`
+}
+
+type BackupSection struct {
+	// Address of backup daemon, backup API is not served if it is empty
+	Address         string `yaml:"address" env:"BACKUP_DAEMON_ADDRESS" validate:"omitempty,url"`
+	Username        string `yaml:"username" env:"BACKUP_DAEMON_USERNAME"`
+	Password        string `yaml:"password" env:"BACKUP_DAEMON_PASSWORD"`
+	FullRestore     bool   `yaml:"fullRestore" env:"BACKUP_FULL_RESTORE"`
+	DbNameMaxLength int    `yaml:"dbNameMaxLength" env:"BACKUP_DB_NAME_MAX_LENGTH" default:"64" validate:"min=1"`
+}
+
+type VaultSection struct {
+	Enabled bool   `yaml:"enabled" env:"VAULT_ENABLED"`
+	Address string `yaml:"address" env:"VAULT_ADDR" validate:"required_if=Enabled true,omitempty,url"`
+	// Role of kubernetes auth method or certificate role of cert auth method
+	Role string `yaml:"role" env:"VAULT_ROLE"`
+	// AuthMethod is kubernetes, approle, token-file or cert. Other values are mount paths of kubernetes
+	// auth method for compatibility, use AuthMountPath instead.
+	AuthMethod string `yaml:"authMethod" env:"VAULT_AUTH_METHOD" default:"kubernetes"`
+	// AuthMountPath is the mount path of the auth method, the method name is used if it is empty
+	AuthMountPath       string `yaml:"authMountPath" env:"VAULT_AUTH_MOUNT_PATH"`
+	KubernetesTokenPath string `yaml:"kubernetesTokenPath" env:"VAULT_KUBERNETES_TOKEN_PATH"`
+	AppRoleRoleId       string `yaml:"appRoleRoleId" env:"VAULT_APPROLE_ROLE_ID" validate:"required_if=AuthMethod approle"`
+	// AppRoleSecretIdFile is read on every login, so the mounted secret id can be replaced
+	AppRoleSecretIdFile string `yaml:"appRoleSecretIdFile" env:"VAULT_APPROLE_SECRET_ID_FILE" validate:"required_if=AuthMethod approle"`
+	TokenFile           string `yaml:"tokenFile" env:"VAULT_TOKEN_FILE" validate:"required_if=AuthMethod token-file"`
+	ClientCert          string `yaml:"clientCert" env:"VAULT_CLIENT_CERT" validate:"required_if=AuthMethod cert"`
+	ClientKey           string `yaml:"clientKey" env:"VAULT_CLIENT_KEY" validate:"required_if=AuthMethod cert"`
+	CaCert              string `yaml:"caCert" env:"VAULT_CACERT"`
+	// RotationPeriod of static roles passwords
+	RotationPeriod string `yaml:"rotationPeriod" env:"VAULT_ROTATION_PERIOD" default:"24h"`
+	// DbName is the name of database connection in vault database secrets engine
+	DbName string `yaml:"dbName" env:"VAULT_DB_NAME" validate:"required_if=Enabled true"`
+	// KvVersion pins version of KV secrets engine, version is detected if it is 0
+	KvVersion int `yaml:"kvVersion" env:"VAULT_KV_VERSION" validate:"oneof=0 1 2"`
+	// KvMount is the mount path of KV secrets engine, the first segment of the secret path is used if it is empty
+	KvMount           string `yaml:"kvMount" env:"VAULT_KV_MOUNT"`
+	CredentialsMode   string `yaml:"credentialsMode" env:"VAULT_CREDENTIALS_MODE" default:"static" validate:"oneof=static dynamic"`
+	DynamicDefaultTTL string `yaml:"dynamicDefaultTTL" env:"VAULT_DYNAMIC_DEFAULT_TTL"`
+	DynamicMaxTTL     string `yaml:"dynamicMaxTTL" env:"VAULT_DYNAMIC_MAX_TTL"`
+	// MigrationRate limits the number of users migrated to vault per second by bulk migration
+	MigrationRate int `yaml:"migrationRate" env:"VAULT_MIGRATION_RATE" default:"5" validate:"min=1,max=1000"`
+	// MigrationCheckpointDir keeps progress of bulk migration to vault between restarts
+	MigrationCheckpointDir string `yaml:"migrationCheckpointDir" env:"VAULT_MIGRATION_CHECKPOINT_DIR"`
+	// RoleNameMaxLength limits length of vault role names, longer names are shortened
+	RoleNameMaxLength int `yaml:"roleNameMaxLength" env:"VAULT_ROLE_NAME_MAX_LENGTH" default:"128" validate:"min=18"`
+}
+
+type PasswordRotationSection struct {
+	// CheckIntervalMin is the interval of password rotation policy checks in minutes, policy is disabled if it is 0
+	CheckIntervalMin int `yaml:"checkIntervalMin" env:"PASSWORD_ROTATION_CHECK_INTERVAL_MIN" validate:"min=0"`
+}
+
+type LogSection struct {
+	// Level of adapter logs: OFF, FATAL, ERROR, WARN, INFO, DEBUG or TRACE
+	Level string `yaml:"level" env:"LOG_LEVEL" default:"INFO" validate:"oneof=OFF FATAL ERROR WARN INFO DEBUG TRACE"`
+	// LevelFile is the file with log level, e.g. mounted config map, level is changed when the file is changed
+	LevelFile string `yaml:"levelFile" env:"LOG_LEVEL_FILE"`
+	// Format of adapter logs: text or json
+	Format string `yaml:"format" env:"LOG_FORMAT" default:"text" validate:"oneof=text json"`
+}
+
+type TracingSection struct {
+	// Exporter of spans: none, otlp or stdout. Incoming trace context is propagated with any exporter.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER" default:"none" validate:"oneof=none otlp stdout"`
+	// OtlpEndpoint is URL of OTLP HTTP traces endpoint, standard OTEL_EXPORTER_OTLP_* variables are used when it is empty
+	OtlpEndpoint string `yaml:"otlpEndpoint" env:"TRACING_OTLP_ENDPOINT" validate:"omitempty,url"`
+}
+
+type AuditSection struct {
+	// File is appended with audit events as JSON lines
+	File string `yaml:"file" env:"AUDIT_FILE"`
+	// Stdout prints audit events as JSON lines to stdout
+	Stdout bool `yaml:"stdout" env:"AUDIT_STDOUT" default:"false"`
+	// WebhookUrl receives every audit event in POST request
+	WebhookUrl string `yaml:"webhookUrl" env:"AUDIT_WEBHOOK_URL" validate:"omitempty,url"`
+	// WebhookAuthorization is the value of Authorization header of webhook requests
+	WebhookAuthorization string `yaml:"webhookAuthorization" env:"AUDIT_WEBHOOK_AUTHORIZATION"`
+}
+
+// AuthSection enables bearer token authentication of adapter API besides basic auth,
+// tokens are verified by JWKS or by TokenReview endpoint
+type AuthSection struct {
+	// JwksFile is JSON Web Key Set verifying signatures of tokens, e.g. mounted keys of Kubernetes API server
+	JwksFile string `yaml:"jwksFile" env:"AUTH_JWKS_FILE"`
+	// JwksUrl serves JSON Web Key Set verifying signatures of tokens, keys are reloaded every few minutes
+	JwksUrl string `yaml:"jwksUrl" env:"AUTH_JWKS_URL" validate:"omitempty,url,excluded_with=JwksFile"`
+	// Issuer of tokens verified by JWKS, it is not checked if it is empty
+	Issuer string `yaml:"issuer" env:"AUTH_JWT_ISSUER"`
+	// Audience tokens must be issued for, it is required if tokens are verified, so tokens of other services are rejected
+	Audience string `yaml:"audience" env:"AUTH_TOKEN_AUDIENCE" validate:"required_with=JwksFile JwksUrl TokenReviewUrl"`
+	// TokenReviewUrl is Kubernetes TokenReview compatible endpoint validating tokens
+	TokenReviewUrl string `yaml:"tokenReviewUrl" env:"AUTH_TOKEN_REVIEW_URL" validate:"omitempty,url,excluded_with=JwksFile JwksUrl"`
+	// TokenReviewTokenFile authenticates adapter in TokenReview endpoint
+	TokenReviewTokenFile string `yaml:"tokenReviewTokenFile" env:"AUTH_TOKEN_REVIEW_TOKEN_FILE" default:"/var/run/secrets/kubernetes.io/serviceaccount/token"`
+	// TokenReviewCAFile verifies certificate of TokenReview endpoint, system CAs are used if it is empty
+	TokenReviewCAFile string `yaml:"tokenReviewCAFile" env:"AUTH_TOKEN_REVIEW_CA_FILE" default:"/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"`
+}
+
+// LimitsSection limits concurrency and rate of adapter API requests by operation class:
+// create, update, drop, backup, restore and read
+type LimitsSection struct {
+	// Concurrency is the number of requests of the class processed at the same time, e.g. "create=10,restore=2"
+	Concurrency map[string]string `yaml:"concurrency" env:"LIMITS_CONCURRENCY"`
+	// Rate is the number of requests of the class started per second, e.g. "create=5,backup=0.5"
+	Rate map[string]string `yaml:"rate" env:"LIMITS_RATE"`
+	// QueueTimeoutSec is the time requests wait for the limits before they are rejected with 429
+	QueueTimeoutSec int `yaml:"queueTimeoutSec" env:"LIMITS_QUEUE_TIMEOUT_SEC" default:"30" validate:"min=0"`
+}
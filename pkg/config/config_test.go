@@ -0,0 +1,89 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setRequired(t *testing.T) {
+	t.Setenv("CLOUD_NAMESPACE", "test-namespace")
+	t.Setenv("ADAPTER_NAME", "postgresql")
+	t.Setenv("ADAPTER_ADDRESS", "http://adapter:8080")
+	t.Setenv("ADAPTER_USERNAME", "user")
+	t.Setenv("ADAPTER_PASSWORD", "pass")
+	t.Setenv("PHYSICAL_DATABASE_ID", "pg")
+	t.Setenv("DBAAS_AGGREGATOR_ADDRESS", "http://aggregator:8080")
+	t.Setenv("DBAAS_AGGREGATOR_USERNAME", "aggregator")
+	t.Setenv("DBAAS_AGGREGATOR_PASSWORD", "pass")
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	setRequired(t)
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	content := "adapter:\n  port: 9000\n  metricsServiceName: from-file\n  labels:\n    clusterName: file\nregistration:\n  retryDelayMs: 100\n"
+	require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+	t.Setenv(FileEnv, file)
+	t.Setenv("ADAPTER_PORT", "9001")
+	t.Setenv("ADAPTER_LABELS", "clusterName=env, zone=a")
+
+	cfg, err := Load([]string{"--adapter.port=9002", "--vault.enabled", "--vault.address=http://vault:8200", "--vault.dbName=pg"})
+	require.NoError(t, err)
+	assert.Equal(t, 9002, cfg.Adapter.Port)
+	assert.Equal(t, "from-file", cfg.Adapter.MetricsServiceName)
+	assert.Equal(t, map[string]string{"clusterName": "env", "zone": "a"}, cfg.Adapter.Labels)
+	assert.Equal(t, 100, cfg.Registration.RetryDelayMs)
+	assert.Equal(t, 150000, cfg.Registration.FixedDelayMs)
+	assert.True(t, cfg.Vault.Enabled)
+	assert.Equal(t, "static", cfg.Vault.CredentialsMode)
+}
+
+func TestLoadValidation(t *testing.T) {
+	setRequired(t)
+	t.Setenv("CLOUD_NAMESPACE", "")
+	t.Setenv("VAULT_ENABLED", "true")
+	t.Setenv("VAULT_CREDENTIALS_MODE", "random")
+	t.Setenv("VAULT_AUTH_METHOD", "approle")
+	t.Setenv("MANAGEMENT_PORT", "8080")
+	t.Setenv("AUTH_JWKS_URL", "https://kubernetes.default.svc/openid/v1/jwks")
+
+	_, err := Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "namespace (CLOUD_NAMESPACE) does not satisfy 'required' rule")
+	assert.Contains(t, err.Error(), "vault.address (VAULT_ADDR)")
+	assert.Contains(t, err.Error(), "vault.credentialsMode (VAULT_CREDENTIALS_MODE) does not satisfy 'oneof=static dynamic' rule")
+	assert.Contains(t, err.Error(), "vault.appRoleRoleId (VAULT_APPROLE_ROLE_ID) does not satisfy 'required_if")
+	assert.Contains(t, err.Error(), "server.managementPort (MANAGEMENT_PORT) must differ from adapter.port")
+	assert.Contains(t, err.Error(), "auth.audience (AUTH_TOKEN_AUDIENCE) does not satisfy 'required_with")
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("adapter:\n  unknown: 1\n"), 0600))
+	_, err = Load([]string{"--config", file})
+	assert.ErrorContains(t, err, "cannot parse configuration file")
+}
+
+func TestKeysAreDocumented(t *testing.T) {
+	docs, err := os.ReadFile("../../docs/configuration.md")
+	require.NoError(t, err)
+	for _, env := range EnvKeys() {
+		assert.True(t, strings.Contains(string(docs), "`"+env+"`"), "%s is not documented", env)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv is the environment variable with path of YAML configuration file, "--config" flag overrides it
+const FileEnv = "ADAPTER_CONFIG_FILE"
+
+var validate = validator.New()
+
+// key is a configuration value which can be set from every source
+type key struct {
+	// path is YAML path of the value, it is used as command line flag name, e.g. "adapter.port"
+	path  string
+	env   string
+	value reflect.Value
+	def   string
+}
+
+// Load reads configuration from defaults, YAML file, environment variables and command line flags
+// in this order, so the latter source overrides the former one. Loaded configuration is validated.
+func Load(args []string) (*AdapterConfig, error) {
+	cfg := Default()
+	keys := collectKeys(reflect.ValueOf(cfg).Elem(), "")
+
+	flags := flag.NewFlagSet("adapter", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv(FileEnv), "path of YAML configuration file")
+	flagValues := make(map[string]string)
+	for _, k := range keys {
+		path := k.path
+		usage := fmt.Sprintf("overrides %s environment variable", k.env)
+		if k.value.Kind() == reflect.Bool {
+			flags.BoolFunc(path, usage, func(value string) error {
+				flagValues[path] = value
+				return nil
+			})
+		} else {
+			flags.Func(path, usage, func(value string) error {
+				flagValues[path] = value
+				return nil
+			})
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		if err := loadFile(cfg, *configFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, k := range keys {
+		if value, ok := os.LookupEnv(k.env); ok && k.env != "" {
+			if err := setValue(k.value, value); err != nil {
+				return nil, fmt.Errorf("invalid value of %s environment variable: %w", k.env, err)
+			}
+		}
+	}
+	for _, k := range keys {
+		if value, ok := flagValues[k.path]; ok {
+			if err := setValue(k.value, value); err != nil {
+				return nil, fmt.Errorf("invalid value of --%s flag: %w", k.path, err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Default returns configuration with default values only, it is not validated
+func Default() *AdapterConfig {
+	cfg := &AdapterConfig{}
+	for _, k := range collectKeys(reflect.ValueOf(cfg).Elem(), "") {
+		if k.def == "" {
+			continue
+		}
+		if err := setValue(k.value, k.def); err != nil {
+			panic(fmt.Sprintf("invalid default of %s: %v", k.path, err))
+		}
+	}
+	return cfg
+}
+
+// Validate checks that required values are set and values are in allowed ranges
+func (cfg *AdapterConfig) Validate() error {
+	err := validate.Struct(cfg)
+	var validationErrs validator.ValidationErrors
+	if err != nil && !errors.As(err, &validationErrs) {
+		return err
+	}
+	paths := make(map[string]string)
+	for _, k := range collectKeys(reflect.ValueOf(cfg).Elem(), "") {
+		paths[k.path] = k.env
+	}
+	var messages []string
+	for _, fieldErr := range validationErrs {
+		path := yamlPath(reflect.TypeOf(*cfg), strings.TrimPrefix(fieldErr.StructNamespace(), "AdapterConfig."))
+		messages = append(messages, fmt.Sprintf("%s (%s) does not satisfy '%s' rule", path, paths[path], ruleOf(fieldErr)))
+	}
+	// rules between sections are not supported by struct tags
+	if cfg.Server.ManagementPort != 0 && cfg.Server.ManagementPort == cfg.Adapter.Port {
+		messages = append(messages, fmt.Sprintf("server.managementPort (%s) must differ from adapter.port", paths["server.managementPort"]))
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid adapter configuration: %s", strings.Join(messages, "; "))
+}
+
+// EnvKeys returns environment variables of all configuration keys
+func EnvKeys() []string {
+	var result []string
+	for _, k := range collectKeys(reflect.ValueOf(&AdapterConfig{}).Elem(), "") {
+		result = append(result, k.env)
+	}
+	return result
+}
+
+func loadFile(cfg *AdapterConfig, fileName string) error {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("cannot read configuration file: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("cannot parse configuration file %s: %w", fileName, err)
+	}
+	return nil
+}
+
+func collectKeys(value reflect.Value, prefix string) []key {
+	var keys []key
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		path := prefix + field.Tag.Get("yaml")
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, collectKeys(value.Field(i), path+".")...)
+			continue
+		}
+		keys = append(keys, key{
+			path:  path,
+			env:   field.Tag.Get("env"),
+			value: value.Field(i),
+			def:   field.Tag.Get("default"),
+		})
+	}
+	return keys
+}
+
+// setValue parses string value of env, flag or default,
+// maps are comma separated key=value pairs.
+func setValue(value reflect.Value, raw string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int:
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(parsed))
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(parsed)
+	case reflect.Map:
+		parsed := make(map[string]string)
+		for _, pair := range strings.Split(raw, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			k, v, found := strings.Cut(pair, "=")
+			if !found {
+				return fmt.Errorf("%q is not key=value pair", pair)
+			}
+			parsed[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+		value.Set(reflect.ValueOf(parsed))
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// yamlPath converts struct namespace of the field, e.g. "Adapter.Port", to YAML path "adapter.port"
+func yamlPath(t reflect.Type, namespace string) string {
+	var parts []string
+	for _, name := range strings.Split(namespace, ".") {
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return namespace
+		}
+		parts = append(parts, field.Tag.Get("yaml"))
+		t = field.Type
+	}
+	return strings.Join(parts, ".")
+}
+
+func ruleOf(fieldErr validator.FieldError) string {
+	if fieldErr.Param() != "" {
+		return fieldErr.Tag() + "=" + fieldErr.Param()
+	}
+	return fieldErr.Tag()
+}
@@ -140,13 +140,13 @@ type RestoreRequest struct {
 }
 
 type RestorationRequest struct {
-	Databases       []DbInfo `json:"databases"`
+	Databases       []DbInfo `json:"databases" validate:"dive"`
 	RegenerateNames bool     `json:"regenerateNames"`
 }
 
 type DbInfo struct {
-	Name         string  `json:"name"`
+	Name         string  `json:"name" validate:"required"`
 	Microservice string  `json:"microservice"`
 	Namespace    string  `json:"namespace"`
-	Prefix       *string `json:"prefix,omitempty"`
+	Prefix       *string `json:"prefix,omitempty" validate:"omitempty,nameprefix"`
 }
@@ -106,12 +106,16 @@ type RestoreResponse struct {
 }
 
 // BadRequestResponse represents a 400 Bad Request error response
+//
+// Deprecated: errors are responded as Problem
 type BadRequestResponse struct {
 	Error   string   `json:"error"`
 	Details []string `json:"details,omitempty"`
 }
 
 // ServerErrorResponse represents a 500 Internal Server Error response
+//
+// Deprecated: errors are responded as Problem
 type ServerErrorResponse struct {
 	Error     string `json:"error"`
 	RequestId string `json:"requestId"`
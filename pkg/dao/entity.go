@@ -14,6 +14,12 @@
 
 package dao
 
+import (
+	"fmt"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+)
+
 const (
 	RootUrl         = "/api"
 	MajorAPIVersion = 2
@@ -24,6 +30,7 @@ const DefaultRouteFormat = "%s/%s/dbaas/adapter"
 
 const StatusRunning Status = "running"
 const StatusRun Status = "run"
+const StatusDraining Status = "draining"
 
 var SupportedMajorsVersions = []int{2}
 
@@ -67,17 +74,32 @@ type AdditionalRole struct {
 }
 
 type DbCreateRequest struct {
-	Metadata   map[string]interface{} `json:"metadata,omitempty"`
-	NamePrefix *string                `json:"namePrefix,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty" validate:"omitempty,classifier"`
+	NamePrefix *string                `json:"namePrefix,omitempty" validate:"omitempty,nameprefix"`
 	Password   string                 `json:"password,omitempty"`
 	DbName     string                 `json:"dbName,omitempty"`
 	Settings   map[string]interface{} `json:"settings,omitempty"`
 	Username   string                 `json:"username,omitempty"`
-	Role       string                 `json:"role,omitempty"`
+	Role       string                 `json:"role,omitempty" validate:"omitempty,dbrole"`
+}
+
+// String masks the password and secrets of settings
+func (r DbCreateRequest) String() string {
+	type plain DbCreateRequest
+	if r.Password != "" {
+		r.Password = utils.RedactedValue
+	}
+	r.Settings = utils.RedactMap(r.Settings)
+	return fmt.Sprintf("%+v", plain(r))
 }
 
 type ConnectionProperties map[string]interface{}
 
+// String masks passwords and other secrets, so connection properties can be logged
+func (p ConnectionProperties) String() string {
+	return fmt.Sprint(utils.RedactMap(p))
+}
+
 type DbCreateResponse struct {
 	Name                 string               `json:"name,omitempty"`
 	ConnectionProperties ConnectionProperties `json:"connectionProperties,omitempty"`
@@ -92,8 +114,8 @@ type DbCreateResponseMultiUser struct {
 }
 
 type DbResource struct {
-	Kind         string             `json:"kind,omitempty"`
-	Name         string             `json:"name,omitempty"`
+	Kind         string             `json:"kind,omitempty" validate:"required"`
+	Name         string             `json:"name,omitempty" validate:"required"`
 	ErrorMessage string             `json:"errorMessage,omitempty"`
 	Status       DropResourceStatus `json:"status,omitempty"`
 }
@@ -141,9 +163,96 @@ type PhysicalDatabaseRegistrationHealth struct {
 	Status string `json:"status"`
 }
 
+type RegistrationTrackStatus string
+
+const (
+	RegistrationProceedingStatus = RegistrationTrackStatus("PROCEEDING")
+	RegistrationSuccessStatus    = RegistrationTrackStatus("SUCCESS")
+	RegistrationFailStatus       = RegistrationTrackStatus("FAIL")
+)
+
+// PhysicalDatabaseRegistrationTrack describes the state of forced physical database registration
+type PhysicalDatabaseRegistrationTrack struct {
+	TrackId        string                  `json:"trackId"`
+	Status         RegistrationTrackStatus `json:"status"`
+	ErrorMessage   string                  `json:"errorMessage,omitempty"`
+	CreationTime   string                  `json:"creationTime"`
+	CompletionTime *string                 `json:"completionTime,omitempty"`
+}
+
+type VaultMigrationStatus string
+
+const (
+	VaultMigrationProceedingStatus = VaultMigrationStatus("PROCEEDING")
+	VaultMigrationSuccessStatus    = VaultMigrationStatus("SUCCESS")
+	VaultMigrationFailStatus       = VaultMigrationStatus("FAIL")
+)
+
+// VaultMigrationRequest starts migration of all users which passwords are not stored in vault yet
+type VaultMigrationRequest struct {
+	// DryRun only reports users which would be migrated
+	DryRun bool `json:"dryRun"`
+	// RatePerSecond limits the number of migrated users per second, adapter default is used if it is 0
+	RatePerSecond float64 `json:"ratePerSecond,omitempty" validate:"min=0,max=1000"`
+	// Databases limits migration to the listed logical databases, all databases are migrated if it is empty
+	Databases []string `json:"databases,omitempty" validate:"dive,required"`
+}
+
+// VaultMigrationUser describes a user which is migrated or would be migrated in dry run
+type VaultMigrationUser struct {
+	DbName    string `json:"dbName"`
+	UserName  string `json:"userName"`
+	VaultRole string `json:"vaultRole,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// VaultMigrationJob describes the state of bulk migration to vault
+type VaultMigrationJob struct {
+	JobId              string               `json:"jobId"`
+	Status             VaultMigrationStatus `json:"status"`
+	DryRun             bool                 `json:"dryRun"`
+	ErrorMessage       string               `json:"errorMessage,omitempty"`
+	CreationTime       string               `json:"creationTime"`
+	CompletionTime     *string              `json:"completionTime,omitempty"`
+	TotalDatabases     int                  `json:"totalDatabases"`
+	ProcessedDatabases int                  `json:"processedDatabases"`
+	MigratedUsers      int                  `json:"migratedUsers"`
+	AlreadyMigrated    int                  `json:"alreadyMigratedUsers"`
+	// Pending contains users which would be migrated, it is filled in dry run only
+	Pending []VaultMigrationUser `json:"pending,omitempty"`
+	Failed  []VaultMigrationUser `json:"failed,omitempty"`
+}
+
+type PasswordRotationTrigger string
+
+const (
+	PasswordRotationManualTrigger = PasswordRotationTrigger("MANUAL")
+	PasswordRotationPolicyTrigger = PasswordRotationTrigger("POLICY")
+)
+
+// PasswordRotationRecord is an audit record of user password rotation
+type PasswordRotationRecord struct {
+	DbName       string                  `json:"dbName"`
+	UserName     string                  `json:"userName"`
+	Trigger      PasswordRotationTrigger `json:"trigger"`
+	Time         string                  `json:"time"`
+	Success      bool                    `json:"success"`
+	ErrorMessage string                  `json:"errorMessage,omitempty"`
+}
+
+// UpdateConnectionPropertiesRequest sends connection properties of the logical database changed by adapter,
+// e.g. rotated password, to dbaas aggregator
+type UpdateConnectionPropertiesRequest struct {
+	Classifier           map[string]interface{} `json:"classifier"`
+	ConnectionProperties ConnectionProperties   `json:"connectionProperties"`
+	PhysicalDatabaseId   string                 `json:"physicalDatabaseId,omitempty"`
+}
+
 type Health struct {
 	Status                       string                              `json:"status"`
 	PhysicalDatabaseRegistration *PhysicalDatabaseRegistrationHealth `json:"physicalDatabaseRegistration"`
+	// PhysicalDatabases contains registration health of every physical database if adapter serves several of them
+	PhysicalDatabases map[string]*PhysicalDatabaseRegistrationHealth `json:"physicalDatabases,omitempty"`
 }
 
 type BasicAuth struct {
@@ -151,6 +260,37 @@ type BasicAuth struct {
 	Password string `json:"password"`
 }
 
+// String masks the password
+func (a BasicAuth) String() string {
+	return fmt.Sprintf("{Username:%s Password:%s}", a.Username, utils.RedactedValue)
+}
+
+// ApiRole limits adapter API routes available to the principal
+type ApiRole string
+
+const (
+	// AggregatorRole has access to all the routes, adapter API credentials registered in dbaas aggregator have it
+	AggregatorRole = ApiRole("aggregator")
+	// BackupOperatorRole has access to backup routes only
+	BackupOperatorRole = ApiRole("backup-operator")
+	// ReadOnlyRole lists and describes databases, users, migrations and backups
+	ReadOnlyRole = ApiRole("read-only")
+)
+
+var ApiRoles = []ApiRole{AggregatorRole, BackupOperatorRole, ReadOnlyRole}
+
+// ApiPrincipal is an additional user of adapter API
+type ApiPrincipal struct {
+	Username string  `json:"username"`
+	Password string  `json:"password"`
+	Role     ApiRole `json:"role"`
+}
+
+// String masks the password
+func (p ApiPrincipal) String() string {
+	return fmt.Sprintf("{Username:%s Password:%s Role:%s}", p.Username, utils.RedactedValue, p.Role)
+}
+
 type DbaasAggregatorVersion struct {
 	Major           int   `json:"major"`
 	Minor           int   `json:"minor"`
@@ -167,7 +307,9 @@ type PhysicalDatabaseRegistrationRequest struct {
 
 type PhysicalDatabaseRoleRequest struct {
 	Success []Success `json:"success,omitempty"`
-	Failure *Failure  `json:"failure,omitempty"`
+	// Failure is the first of Failures, it is kept for the aggregators which expect only one failure per request
+	Failure  *Failure  `json:"failure,omitempty"`
+	Failures []Failure `json:"failures,omitempty"`
 }
 
 type Success struct {
@@ -185,8 +327,17 @@ type Failure struct {
 type UserCreateRequest struct {
 	DbName         string `json:"dbName,omitempty"`
 	Password       string `json:"password,omitempty"`
-	Role           string `json:"role,omitempty"`
-	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+	Role           string `json:"role,omitempty" validate:"omitempty,dbrole"`
+	UsernamePrefix string `json:"usernamePrefix,omitempty" validate:"omitempty,nameprefix"`
+}
+
+// String masks the password
+func (r UserCreateRequest) String() string {
+	type plain UserCreateRequest
+	if r.Password != "" {
+		r.Password = utils.RedactedValue
+	}
+	return fmt.Sprintf("%+v", plain(r))
 }
 
 type CreatedUser struct {
@@ -195,3 +346,8 @@ type CreatedUser struct {
 	Name                 string               `json:"name,omitempty"`
 	Role                 string               `json:"role,omitempty"`
 }
+
+type LogLevel struct {
+	// Level is one of OFF, FATAL, ERROR, WARN, INFO, DEBUG, TRACE
+	Level string `json:"level"`
+}
@@ -0,0 +1,71 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+// ProblemContentType is the media type of error responses
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix prefixes error code in type of problem
+const ProblemTypePrefix = "urn:dbaas-adapter:problem:"
+
+// ErrorCode identifies the kind of error in responses, codes are stable and clients may rely on them
+type ErrorCode string
+
+const (
+	InvalidRequestCode           = ErrorCode("invalid-request")
+	ValidationFailedCode         = ErrorCode("validation-failed")
+	UnauthorizedCode             = ErrorCode("unauthorized")
+	ForbiddenCode                = ErrorCode("forbidden")
+	NotFoundCode                 = ErrorCode("not-found")
+	MethodNotAllowedCode         = ErrorCode("method-not-allowed")
+	ConflictCode                 = ErrorCode("conflict")
+	PayloadTooLargeCode          = ErrorCode("payload-too-large")
+	TooManyRequestsCode          = ErrorCode("too-many-requests")
+	InternalErrorCode            = ErrorCode("internal-error")
+	NotSupportedCode             = ErrorCode("not-supported")
+	UnavailableCode              = ErrorCode("unavailable")
+	TimeoutCode                  = ErrorCode("timeout")
+	CreationFailedCode           = ErrorCode("creation-failed")
+	DropFailedCode               = ErrorCode("drop-failed")
+	VaultDisabledCode            = ErrorCode("vault-disabled")
+	UserNotInVaultCode           = ErrorCode("user-not-in-vault")
+	PasswordManagedByVaultCode   = ErrorCode("password-managed-by-vault")
+	VaultMigrationInProgressCode = ErrorCode("vault-migration-in-progress")
+	RestoreRequiresDatabasesCode = ErrorCode("restore-requires-databases")
+)
+
+// Problem is the body of every error response of adapter API, see RFC 7807
+type Problem struct {
+	// Type is URN of the error code
+	Type string `json:"type" example:"urn:dbaas-adapter:problem:not-found"`
+	// Title is the status text
+	Title string `json:"title" example:"Not Found"`
+	// Status is the status code of the response
+	Status int `json:"status" example:"404"`
+	// Detail explains this occurrence of the problem
+	Detail string `json:"detail,omitempty" example:"Backup not found"`
+	// Instance is the path of the request
+	Instance string `json:"instance,omitempty" example:"/api/v2/dbaas/adapter/postgresql/backups/backup/1"`
+	// Code is the stable error code
+	Code ErrorCode `json:"code" example:"not-found"`
+	// RequestId is X-Request-ID of the request or generated id responded in X-Request-ID header
+	RequestId string `json:"requestId,omitempty"`
+	// Retryable is true if the same request may succeed later
+	Retryable bool `json:"retryable"`
+	// Errors are failed validations of the request
+	Errors []string `json:"errors,omitempty"`
+	// Resources are results of partially failed drop of resources
+	Resources []DbResource `json:"resources,omitempty"`
+}
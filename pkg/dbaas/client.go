@@ -21,6 +21,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
@@ -31,6 +32,16 @@ type Client struct {
 	Credentials *dao.BasicAuth
 	Client      *http.Client
 	version     string
+
+	// credentialsMutex guards Credentials which can be replaced by SetCredentials at runtime
+	credentialsMutex sync.RWMutex
+}
+
+// SetCredentials replaces credentials of dbaas aggregator, e.g. when mounted secret is changed
+func (d *Client) SetCredentials(credentials *dao.BasicAuth) {
+	d.credentialsMutex.Lock()
+	defer d.credentialsMutex.Unlock()
+	d.Credentials = credentials
 }
 
 // creates new dbaas.Client. (client *http.Client) parameter can be nil.
@@ -97,12 +108,14 @@ func (d *Client) sendRequest(method, url string, payload io.Reader) (int, []byte
 		return http.StatusInternalServerError, nil, err
 	}
 
+	d.credentialsMutex.RLock()
 	if d.Credentials != nil {
 		req.SetBasicAuth(d.Credentials.Username, d.Credentials.Password)
 	}
+	d.credentialsMutex.RUnlock()
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := d.Client.Do(req)
+	resp, err := utils.DoTraced(d.Client, req)
 	if err != nil {
 		if resp != nil {
 			return resp.StatusCode, nil, err
@@ -139,7 +152,7 @@ func (d *Client) PhysicalDatabaseRegistration(dbName, physicalDatabaseId string,
 		code: %d
 		method: %s
 		response: %s
-		error: %v`, url, statusCode, method, body, err)
+		error: %v`, url, statusCode, method, utils.RedactSecrets(string(body)), err)
 
 	}
 
@@ -150,7 +163,7 @@ func (d *Client) PhysicalDatabaseRegistration(dbName, physicalDatabaseId string,
 			return nil, fmt.Errorf(`failed to Unmarshal PhysicalDatabaseRegistrationResponse,
 				statusCode: %d,
 				raw response: %s,
-				err: %v`, statusCode, body, err)
+				err: %v`, statusCode, utils.RedactSecrets(string(body)), err)
 		}
 	}
 
@@ -175,10 +188,10 @@ func (d *Client) AdditionalRoles(physicalDatabaseId, dbName string, data dao.Phy
 
 	statusCode, body, err := d.sendRequest(http.MethodPost, url, payload)
 	if err != nil || (statusCode != 200 && statusCode != 202) {
-		return nil, fmt.Errorf(errMsgPattern, "Failed to request additional roles", url, statusCode, body, err)
+		return nil, fmt.Errorf(errMsgPattern, "Failed to request additional roles", url, statusCode, utils.RedactSecrets(string(body)), err)
 	} else if statusCode == 202 {
 		if err = json.Unmarshal(body, &response); err != nil {
-			return nil, fmt.Errorf(errMsgPattern, "Failed to Unmarshal []entity.AdditionalRole", url, statusCode, body, err)
+			return nil, fmt.Errorf(errMsgPattern, "Failed to Unmarshal []entity.AdditionalRole", url, statusCode, utils.RedactSecrets(string(body)), err)
 		}
 		return response, nil
 	}
@@ -187,6 +200,25 @@ func (d *Client) AdditionalRoles(physicalDatabaseId, dbName string, data dao.Phy
 
 }
 
+// UpdateConnectionProperties replaces connection properties of the logical database of the namespace in dbaas aggregator
+func (d *Client) UpdateConnectionProperties(namespace, dbType string, data dao.UpdateConnectionPropertiesRequest) error {
+	url := fmt.Sprintf("%s/api/%s/dbaas/%s/databases/update-connection/%s", d.URL, d.version, namespace, dbType)
+	codedBody, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal UpdateConnectionPropertiesRequest body: %v", err)
+	}
+
+	statusCode, body, err := d.sendRequest(http.MethodPut, url, strings.NewReader(string(codedBody)))
+	if err != nil || statusCode < 200 || statusCode > 299 {
+		return fmt.Errorf(`failed to update connection properties:
+		url: %s,
+		status code: %d,
+		raw response: %s,
+		err: %v`, url, statusCode, utils.RedactSecrets(string(body)), err)
+	}
+	return nil
+}
+
 func (d *Client) Health() bool {
 	url := fmt.Sprintf("%s/health", d.URL)
 	statusCode, _, err := d.sendRequest(http.MethodGet, url, nil)
@@ -136,3 +136,39 @@ func TestClient_PhysicalDatabaseRegistrationRoles(t *testing.T) {
 	})
 
 }
+
+func TestClient_UpdateConnectionProperties(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	aggAddress := "http://testdbbaasaggr.com"
+
+	responder, err := httpmock.NewJsonResponder(200, dao.DbaasAggregatorVersion{SupportedMajors: []int{3}})
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api-version", aggAddress), responder)
+
+	dbaasClient, err := NewDbaasClient(aggAddress, &dao.BasicAuth{Username: "foo", Password: "bar"}, nil)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	request := dao.UpdateConnectionPropertiesRequest{
+		Classifier:           map[string]interface{}{"namespace": "ns"},
+		ConnectionProperties: dao.ConnectionProperties{"password": "new-password"},
+	}
+
+	t.Run("Update ok", func(t *testing.T) {
+		httpmock.RegisterResponder("PUT", fmt.Sprintf("%s/api/v3/dbaas/ns/databases/update-connection/postgresql", aggAddress),
+			httpmock.NewStringResponder(http.StatusOK, ""))
+
+		assert.NoError(t, dbaasClient.UpdateConnectionProperties("ns", "postgresql", request))
+	})
+
+	t.Run("Update rejected", func(t *testing.T) {
+		httpmock.RegisterResponder("PUT", fmt.Sprintf("%s/api/v3/dbaas/ns/databases/update-connection/postgresql", aggAddress),
+			httpmock.NewStringResponder(http.StatusNotFound, ""))
+
+		assert.Error(t, dbaasClient.UpdateConnectionProperties("ns", "postgresql", request))
+	})
+}
@@ -14,14 +14,27 @@
 
 package helper
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrExecutorShutdown is returned by Submit after Shutdown
+	ErrExecutorShutdown = errors.New("executor is shut down")
+	// ErrQueueFull is returned by Submit when the queue has no space for the task
+	ErrQueueFull = errors.New("executor queue is full")
+)
 
 // BackgroundExecutor runs tasks in a single goroutine. Tasks are submitted in queue and being executed according
 // to the FIFO rule. Always use constructor NewBackgroundExecutor() to create new instance of the BackgroundExecutor.
 // BackgroundExecutor can be shutdown by calling Shutdown() function.
 type BackgroundExecutor struct {
 	// queue contains submitted tasks that need to be run
-	queue  chan func()
+	queue chan func()
+	// done is closed when all the tasks are finished after Shutdown
+	done   chan struct{}
 	active bool
 	mutex  sync.Mutex
 	once   sync.Once
@@ -31,17 +44,26 @@ type BackgroundExecutor struct {
 func NewBackgroundExecutor() *BackgroundExecutor {
 	executor := BackgroundExecutor{
 		queue:  make(chan func(), 5),
+		done:   make(chan struct{}),
 		active: true,
 	}
 	executor.start()
 	return &executor
 }
 
-func (executor *BackgroundExecutor) Submit(work func()) {
-	defer executor.mutex.Unlock()
+// Submit puts work in the queue without waiting for space in it. The work is rejected with ErrExecutorShutdown
+// after Shutdown and with ErrQueueFull if the queue is full, so Shutdown is never blocked by Submit.
+func (executor *BackgroundExecutor) Submit(work func()) error {
 	executor.mutex.Lock()
-	if executor.active {
-		executor.queue <- work
+	defer executor.mutex.Unlock()
+	if !executor.active {
+		return ErrExecutorShutdown
+	}
+	select {
+	case executor.queue <- work:
+		return nil
+	default:
+		return ErrQueueFull
 	}
 }
 
@@ -58,9 +80,21 @@ func (executor *BackgroundExecutor) Shutdown() {
 	})
 }
 
+// AwaitTermination blocks until all the submitted works are finished after Shutdown or ctx is done.
+// Returns true if all the works have been finished.
+func (executor *BackgroundExecutor) AwaitTermination(ctx context.Context) bool {
+	select {
+	case <-executor.done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 // Starts taking work from queue and running it until the task channel is closed.
 func (executor *BackgroundExecutor) start() {
 	go func() {
+		defer close(executor.done)
 		for {
 			work, more := <-executor.queue
 			if more {
@@ -0,0 +1,48 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundExecutor_RejectsWork(t *testing.T) {
+	executor := NewBackgroundExecutor()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	assert.NoError(t, executor.Submit(func() {
+		close(started)
+		<-release
+	}))
+	<-started
+	for i := 0; i < cap(executor.queue); i++ {
+		assert.NoError(t, executor.Submit(func() {}))
+	}
+
+	assert.ErrorIs(t, executor.Submit(func() {}), ErrQueueFull)
+
+	executor.Shutdown()
+	assert.ErrorIs(t, executor.Submit(func() {}), ErrExecutorShutdown)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, executor.AwaitTermination(ctx), "running work is not finished")
+
+	close(release)
+	assert.True(t, executor.AwaitTermination(context.Background()))
+}
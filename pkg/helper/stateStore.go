@@ -0,0 +1,140 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// StateStore keeps small pieces of adapter state, e.g. progress of long operations, so they can be resumed.
+// Values are stored as JSON.
+type StateStore interface {
+	// Load reads the state saved with key into value. Returns false if there is no state for the key.
+	Load(key string, value interface{}) (bool, error)
+	Save(key string, value interface{}) error
+	Delete(key string) error
+}
+
+var unsafeKeySymbols = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// FileStateStore saves every key to a separate JSON file in the directory, so the state survives adapter restarts
+// if the directory is mounted to a persistent volume.
+type FileStateStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewFileStateStore creates the directory if it does not exist and returns FileStateStore working in it.
+func NewFileStateStore(dir string) (*FileStateStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileStateStore{dir: dir}, nil
+}
+
+func (s *FileStateStore) Load(key string, value interface{}) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	content, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(content, value)
+}
+
+// Save writes the value to a temporary file and renames it, so the state file is never partially written.
+func (s *FileStateStore) Save(key string, value interface{}) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	tmp, err := os.CreateTemp(s.dir, ".state-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+func (s *FileStateStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStateStore) path(key string) string {
+	return filepath.Join(s.dir, unsafeKeySymbols.ReplaceAllString(key, "_")+".json")
+}
+
+// InMemoryStateStore keeps the state only during adapter lifetime
+type InMemoryStateStore struct {
+	values map[string][]byte
+	mutex  sync.Mutex
+}
+
+func NewInMemoryStateStore() *InMemoryStateStore {
+	return &InMemoryStateStore{values: make(map[string][]byte)}
+}
+
+func (s *InMemoryStateStore) Load(key string, value interface{}) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	content, found := s.values[key]
+	if !found {
+		return false, nil
+	}
+	return true, json.Unmarshal(content, value)
+}
+
+func (s *InMemoryStateStore) Save(key string, value interface{}) error {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.values[key] = content
+	return nil
+}
+
+func (s *InMemoryStateStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+var _ StateStore = &FileStateStore{}
+var _ StateStore = &InMemoryStateStore{}
@@ -0,0 +1,52 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testState struct {
+	Id    string   `json:"id"`
+	Items []string `json:"items"`
+}
+
+func TestStateStores(t *testing.T) {
+	fileStore, err := NewFileStateStore(t.TempDir())
+	assert.NoError(t, err)
+
+	for name, store := range map[string]StateStore{"File": fileStore, "InMemory": NewInMemoryStateStore()} {
+		t.Run(name, func(t *testing.T) {
+			var state testState
+			found, err := store.Load("roles/instruction:1", &state)
+			assert.NoError(t, err)
+			assert.False(t, found)
+
+			assert.NoError(t, store.Save("roles/instruction:1", testState{Id: "1", Items: []string{"a", "b"}}))
+			found, err = store.Load("roles/instruction:1", &state)
+			assert.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, testState{Id: "1", Items: []string{"a", "b"}}, state)
+
+			assert.NoError(t, store.Delete("roles/instruction:1"))
+			assert.NoError(t, store.Delete("roles/instruction:1"))
+			found, err = store.Load("roles/instruction:1", &state)
+			assert.NoError(t, err)
+			assert.False(t, found)
+		})
+	}
+}
@@ -0,0 +1,386 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/audit"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/auth"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/config"
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dbaas"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/service"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type adapterOptions struct {
+	dbaasClient         *dbaas.Client
+	vaultClient         *utils.VaultClient
+	backupService       service.BackupAdministrationService
+	backupHttpClient    utils.HttpClient
+	backupSpecialSymbol []string
+	supports            dto.SupportsBase
+}
+
+// AdapterOption provides adapter dependencies which are not described by configuration
+type AdapterOption func(*adapterOptions)
+
+// WithDbaasClient sets client of dbaas aggregator, by default it is created from aggregator configuration
+func WithDbaasClient(client *dbaas.Client) AdapterOption {
+	return func(o *adapterOptions) { o.dbaasClient = client }
+}
+
+// WithVaultClient sets vault client, by default it is created from vault configuration if vault is enabled
+func WithVaultClient(client *utils.VaultClient) AdapterOption {
+	return func(o *adapterOptions) { o.vaultClient = client }
+}
+
+// WithBackupService sets backup service, by default it is created if backup daemon address is configured
+func WithBackupService(backupService service.BackupAdministrationService) AdapterOption {
+	return func(o *adapterOptions) { o.backupService = backupService }
+}
+
+// WithBackupHttpClient sets http client of default backup service
+func WithBackupHttpClient(client utils.HttpClient) AdapterOption {
+	return func(o *adapterOptions) { o.backupHttpClient = client }
+}
+
+// WithBackupSpecialSymbols sets special symbols of database names for default backup service
+func WithBackupSpecialSymbols(specialSymbols []string) AdapterOption {
+	return func(o *adapterOptions) { o.backupSpecialSymbol = specialSymbols }
+}
+
+// WithSupports sets features supported by the adapter
+func WithSupports(supports dto.SupportsBase) AdapterOption {
+	return func(o *adapterOptions) { o.supports = supports }
+}
+
+// BuildAdapter creates adapter services from configuration and registers adapter API in the app.
+// It replaces manual wiring of NewCoreAdministrationService, NewPhysicalRegistrationService,
+// DefaultBackupAdministrationService and BuildFiberDBaaSAdapterHandlers.
+func BuildAdapter(app *fiber.App, ctx context.Context, cfg *config.AdapterConfig, dbAdmin service.DbAdministration,
+	logger *zap.Logger, opts ...AdapterOption) error {
+	options := adapterOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	reloadInterval := time.Duration(cfg.Adapter.ReloadIntervalSec) * time.Second
+	apiCredentials := dto.BasicAuth{Username: cfg.Adapter.Username, Password: cfg.Adapter.Password}
+	if cfg.Adapter.CredentialsDir != "" {
+		username, password, err := utils.ReadCredentialsFromDir(cfg.Adapter.CredentialsDir)
+		if err != nil {
+			return fmt.Errorf("cannot read adapter API credentials: %w", err)
+		}
+		apiCredentials = dto.BasicAuth{Username: username, Password: password}
+	}
+
+	if options.dbaasClient == nil {
+		aggregatorCredentials := dto.BasicAuth{Username: cfg.Aggregator.Username, Password: cfg.Aggregator.Password}
+		if cfg.Aggregator.CredentialsDir != "" {
+			username, password, err := utils.ReadCredentialsFromDir(cfg.Aggregator.CredentialsDir)
+			if err != nil {
+				return fmt.Errorf("cannot read dbaas aggregator credentials: %w", err)
+			}
+			aggregatorCredentials = dto.BasicAuth{Username: username, Password: password}
+		}
+		client, err := dbaas.NewDbaasClient(cfg.Aggregator.Address, &aggregatorCredentials, nil)
+		if err != nil {
+			return fmt.Errorf("cannot create dbaas aggregator client: %w", err)
+		}
+		options.dbaasClient = client
+	}
+	if cfg.Aggregator.CredentialsDir != "" {
+		client := options.dbaasClient
+		utils.WatchCredentials(ctx, cfg.Aggregator.CredentialsDir, reloadInterval, logger, func(username, password string) {
+			client.SetCredentials(&dto.BasicAuth{Username: username, Password: password})
+		})
+	}
+	server := ServerFromContext(ctx)
+	if server == nil {
+		server = NewAdapterServer(app)
+	}
+	if options.vaultClient == nil && cfg.Vault.Enabled {
+		client, err := utils.NewVaultClient(utils.VaultConfig{
+			IsVaultEnabled:    true,
+			Address:           cfg.Vault.Address,
+			VaultRole:         cfg.Vault.Role,
+			VaultRotPeriod:    cfg.Vault.RotationPeriod,
+			VaultAuthMethod:   cfg.Vault.AuthMethod,
+			Authenticator:     vaultAuthenticator(cfg.Vault, logger),
+			VaultDBName:       cfg.Vault.DbName,
+			KvVersion:         cfg.Vault.KvVersion,
+			KvMount:           cfg.Vault.KvMount,
+			CredentialsMode:   utils.VaultCredentialsMode(cfg.Vault.CredentialsMode),
+			DynamicDefaultTTL: cfg.Vault.DynamicDefaultTTL,
+			DynamicMaxTTL:     cfg.Vault.DynamicMaxTTL,
+			MetricsRegisterer: server.MetricsRegisterer(),
+			RoleNameMaxLength: cfg.Vault.RoleNameMaxLength,
+		})
+		if err != nil {
+			return err
+		}
+		options.vaultClient = client
+	}
+	if options.backupService == nil && cfg.Backup.Address != "" {
+		options.backupService = service.DefaultBackupAdministrationService(
+			logger,
+			cfg.Backup.Address,
+			cfg.Backup.Username,
+			cfg.Backup.Password,
+			cfg.Backup.FullRestore,
+			options.backupHttpClient,
+			cfg.Backup.DbNameMaxLength,
+			options.backupSpecialSymbol)
+	}
+
+	metrics, err := service.NewMetrics(server.MetricsRegisterer(), cfg.Adapter.Name)
+	if err != nil {
+		return err
+	}
+	if options.backupService != nil {
+		options.backupService = service.InstrumentBackupService(options.backupService, metrics)
+	}
+	administrationService := service.NewCoreAdministrationService(
+		cfg.Namespace,
+		cfg.Adapter.Port,
+		dbAdmin,
+		logger,
+		cfg.Vault.Enabled,
+		options.vaultClient,
+		cfg.Adapter.RoHost,
+		service.WithCloudPublicHost(cfg.CloudPublicHost),
+		service.WithRolesConcurrency(cfg.Registration.AdditionalRolesConcurrency),
+		service.WithVaultMigrationRate(cfg.Vault.MigrationRate),
+		service.WithVaultMigrationCheckpointDir(cfg.Vault.MigrationCheckpointDir),
+		service.WithPasswordRotationCheckInterval(time.Duration(cfg.PasswordRotation.CheckIntervalMin)*time.Minute),
+		service.WithMetrics(metrics),
+		service.WithLoopContext(ctx),
+		service.WithConnectionPropertiesPublisher(options.dbaasClient, cfg.Adapter.Name, cfg.Adapter.PhysicalDatabaseId),
+	)
+	physicalService := service.NewPhysicalRegistrationService(
+		cfg.Adapter.Name,
+		logger,
+		cfg.Adapter.PhysicalDatabaseId,
+		cfg.Adapter.Address,
+		apiCredentials,
+		cfg.Adapter.Labels,
+		options.dbaasClient,
+		cfg.Registration.FixedDelayMs,
+		cfg.Registration.RetryTimeMs,
+		cfg.Registration.RetryDelayMs,
+		administrationService,
+		ctx,
+		service.WithRolesCheckpointDir(cfg.Registration.AdditionalRolesCheckpointDir),
+		service.WithRegistrationMetrics(metrics),
+	)
+
+	err = BuildFiberMultiDBaaSAdapterHandlers(
+		server,
+		apiCredentials.Username,
+		apiCredentials.Password,
+		"/"+cfg.Adapter.Name,
+		[]PhysicalDatabaseServices{{
+			AdminService:    administrationService,
+			PhysicalService: physicalService,
+			BackupService:   options.backupService,
+			Supports:        options.supports.ToMap(),
+		}},
+		logger,
+		cfg.Adapter.Profiler,
+		cfg.Adapter.MetricsServiceName)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Adapter.CredentialsDir != "" {
+		server.WatchApiCredentials(ctx, cfg.Adapter.CredentialsDir, reloadInterval, logger)
+	}
+	if cfg.Adapter.PrincipalsFile != "" {
+		principals, err := ReadApiPrincipals(cfg.Adapter.PrincipalsFile)
+		if err != nil {
+			return err
+		}
+		if err = server.UpdateApiPrincipals(principals); err != nil {
+			return err
+		}
+		server.WatchApiPrincipals(ctx, cfg.Adapter.PrincipalsFile, reloadInterval, logger)
+	}
+	if err := utils.SetLogLevel(cfg.Log.Level); err != nil {
+		return err
+	}
+	if err := utils.SetLogFormat(cfg.Log.Format); err != nil {
+		return err
+	}
+	if cfg.Log.LevelFile != "" {
+		utils.WatchLogLevel(ctx, cfg.Log.LevelFile, reloadInterval, logger)
+	}
+	return nil
+}
+
+// RunAdapter builds adapter with BuildAdapter and runs it until SIGTERM, see RunFiberServer
+func RunAdapter(cfg *config.AdapterConfig, dbAdmin service.DbAdministration, logger *zap.Logger, opts ...AdapterOption) error {
+	serverOpts, err := ServerOptionsFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	shutdownTracing, err := utils.InitTracing(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.OtlpEndpoint, cfg.Adapter.Name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to flush spans: %v", err))
+		}
+	}()
+	auditor, err := AuditorFromConfig(cfg, logger)
+	if err != nil {
+		return err
+	}
+	if auditor != nil {
+		serverOpts = append(serverOpts, WithAuditor(auditor))
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Adapter.GracefulShutdownTimeoutSec)*time.Second)
+			defer cancel()
+			if err := auditor.Close(ctx); err != nil {
+				logger.Error(fmt.Sprintf("Failed to close audit sinks: %v", err))
+			}
+		}()
+	}
+	tokenAuthenticator, err := TokenAuthenticatorFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if tokenAuthenticator != nil {
+		serverOpts = append(serverOpts, WithTokenAuthenticator(tokenAuthenticator))
+	}
+	return RunFiberServer(cfg.Adapter.Port, func(app *fiber.App, ctx context.Context) error {
+		return BuildAdapter(app, ctx, cfg, dbAdmin, logger, opts...)
+	}, serverOpts...)
+}
+
+// AuditorFromConfig creates auditor with sinks of audit section, it returns nil if no sink is configured
+func AuditorFromConfig(cfg *config.AdapterConfig, logger *zap.Logger) (*audit.Auditor, error) {
+	var sinks []audit.Sink
+	if cfg.Audit.File != "" {
+		fileSink, err := audit.NewFileSink(cfg.Audit.File)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, fileSink)
+	}
+	if cfg.Audit.Stdout {
+		sinks = append(sinks, audit.NewWriterSink(os.Stdout))
+	}
+	if cfg.Audit.WebhookUrl != "" {
+		sinks = append(sinks, audit.NewWebhookSink(cfg.Audit.WebhookUrl, cfg.Audit.WebhookAuthorization, nil, logger))
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	return audit.NewAuditor(logger, sinks...), nil
+}
+
+// TokenAuthenticatorFromConfig creates bearer token authenticator of auth section,
+// it returns nil if neither JWKS nor TokenReview endpoint is configured
+func TokenAuthenticatorFromConfig(cfg *config.AdapterConfig) (auth.TokenAuthenticator, error) {
+	switch {
+	case cfg.Auth.JwksFile != "":
+		return auth.NewJwksFileAuthenticator(cfg.Auth.JwksFile, cfg.Auth.Issuer, cfg.Auth.Audience), nil
+	case cfg.Auth.JwksUrl != "":
+		return auth.NewJwksUrlAuthenticator(cfg.Auth.JwksUrl, cfg.Auth.Issuer, cfg.Auth.Audience, nil), nil
+	case cfg.Auth.TokenReviewUrl != "":
+		return auth.NewTokenReviewAuthenticator(cfg.Auth.TokenReviewUrl, cfg.Auth.TokenReviewTokenFile,
+			cfg.Auth.TokenReviewCAFile, cfg.Auth.Audience, nil)
+	default:
+		return nil, nil
+	}
+}
+
+// ServerOptionsFromConfig returns listener options described by server section of configuration
+func ServerOptionsFromConfig(cfg *config.AdapterConfig) ([]ServerOption, error) {
+	tlsMinVersion, err := ParseTLSVersion(cfg.Server.TlsMinVersion)
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := ParseCipherSuites(cfg.Server.TlsCipherSuites)
+	if err != nil {
+		return nil, err
+	}
+	second := func(value int) time.Duration { return time.Duration(value) * time.Second }
+	opts := []ServerOption{
+		WithBindAddress(cfg.Server.BindAddress),
+		WithManagementPort(cfg.Server.ManagementPort),
+		WithTimeouts(second(cfg.Server.ReadTimeoutSec), second(cfg.Server.WriteTimeoutSec), second(cfg.Server.IdleTimeoutSec)),
+		WithBodyLimit(cfg.Server.BodyLimitBytes),
+		WithShutdownTimeout(second(cfg.Adapter.GracefulShutdownTimeoutSec)),
+		WithReloadInterval(second(cfg.Adapter.ReloadIntervalSec)),
+		WithTLS(cfg.Adapter.InternalTlsEnabled),
+		WithTLSFiles(cfg.Server.TlsCertFile, cfg.Server.TlsKeyFile),
+		WithTLSMinVersion(tlsMinVersion),
+		WithTLSCipherSuites(cipherSuites),
+		WithProtectedMetrics(cfg.Server.ProtectMetrics),
+	}
+	if cfg.Server.ClientCAFile != "" {
+		opts = append(opts, WithClientCertificates(cfg.Server.ClientCAFile, cfg.Server.ClientCertRequired))
+	}
+	limits, err := ParseOperationLimits(cfg.Limits.Concurrency, cfg.Limits.Rate)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, WithOperationLimits(limits, second(cfg.Limits.QueueTimeoutSec)))
+	return opts, nil
+}
+
+// vaultAuthenticator returns authenticator of the configured vault auth method
+func vaultAuthenticator(cfg config.VaultSection, logger *zap.Logger) utils.Authenticator {
+	switch cfg.AuthMethod {
+	case "approle":
+		return &utils.AppRoleAuthenticator{
+			MountPath:    cfg.AuthMountPath,
+			RoleID:       cfg.AppRoleRoleId,
+			SecretIDFile: cfg.AppRoleSecretIdFile,
+		}
+	case "token-file":
+		return &utils.TokenFileAuthenticator{Path: cfg.TokenFile}
+	case "cert":
+		return &utils.CertAuthenticator{
+			MountPath:  cfg.AuthMountPath,
+			Name:       cfg.Role,
+			ClientCert: cfg.ClientCert,
+			ClientKey:  cfg.ClientKey,
+			CACert:     cfg.CaCert,
+		}
+	case "", "kubernetes":
+		return &utils.KubernetesAuthenticator{
+			MountPath: cfg.AuthMountPath,
+			Role:      cfg.Role,
+			TokenPath: cfg.KubernetesTokenPath,
+		}
+	default:
+		logger.Warn(fmt.Sprintf("Vault auth method %q is used as mount path of kubernetes auth method, it is deprecated, set vault.authMountPath instead", cfg.AuthMethod))
+		return &utils.KubernetesAuthenticator{
+			MountPath: cfg.AuthMethod,
+			Role:      cfg.Role,
+			TokenPath: cfg.KubernetesTokenPath,
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/audit"
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/gofiber/fiber/v2"
+)
+
+// auditResourcesKey is the key of fiber locals with targets of audited operation set by handler
+const auditResourcesKey = "auditResources"
+
+// WithAuditor records mutating operations of adapter API served by the app, they are not recorded by default
+func WithAuditor(auditor *audit.Auditor) ServerOption {
+	return func(o *serverOptions) { o.auditor = auditor }
+}
+
+// audited records audit event of the operation after the request is handled.
+// Targets of the operation are set by handler with setAuditResources, otherwise path parameters are used.
+// Panic of the handler is recorded as failure with 500 status and passed on to recover middleware.
+func (s *AdapterServer) audited(operation string) fiber.Handler {
+	auditor := s.options.auditor
+	return func(c *fiber.Ctx) (err error) {
+		if auditor == nil {
+			return c.Next()
+		}
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				recordAuditEvent(c, auditor, operation, start, http.StatusInternalServerError)
+				panic(r)
+			}
+			status := c.Response().StatusCode()
+			if err != nil {
+				// the error is not written to response yet, its status is the one of problem responded for it
+				status = problemOf(err).Status
+			}
+			recordAuditEvent(c, auditor, operation, start, status)
+		}()
+		return c.Next()
+	}
+}
+
+// recordAuditEvent records the event of the finished request with its status
+func recordAuditEvent(c *fiber.Ctx, auditor *audit.Auditor, operation string, start time.Time, status int) {
+	outcome := audit.OutcomeSuccess
+	if status >= http.StatusBadRequest {
+		outcome = audit.OutcomeFailure
+	}
+	actor, _ := c.Locals("username").(string)
+	requestId := c.GetRespHeader("X-Request-ID", c.Get("X-Request-ID"))
+
+	// values of fiber context refer to request buffers, they are copied because sinks may write events later
+	auditor.Record(audit.Event{
+		Timestamp:  start.UTC(),
+		Operation:  operation,
+		Actor:      strings.Clone(actor),
+		RequestId:  strings.Clone(requestId),
+		Method:     strings.Clone(c.Method()),
+		Path:       strings.Clone(c.Path()),
+		Resources:  auditResources(c),
+		Outcome:    outcome,
+		Status:     status,
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+}
+
+func auditResources(c *fiber.Ctx) []string {
+	var resources []string
+	if fromHandler, ok := c.Locals(auditResourcesKey).([]string); ok {
+		resources = fromHandler
+	} else {
+		for _, param := range c.Route().Params {
+			resources = append(resources, param+"="+c.Params(param))
+		}
+	}
+	for i := range resources {
+		resources[i] = strings.Clone(resources[i])
+	}
+	return resources
+}
+
+// setAuditResources sets targets of the audited operation, e.g. names of created or dropped resources
+func setAuditResources(c *fiber.Ctx, resources ...string) {
+	c.Locals(auditResourcesKey, resources)
+}
+
+// dbResourceNames returns resources as "kind/name"
+func dbResourceNames(resources []dto.DbResource) []string {
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, resource.Kind+"/"+resource.Name)
+	}
+	return names
+}
+
+// databaseResourceNames returns logical databases as "database/name"
+func databaseResourceNames(databases []string) []string {
+	names := make([]string, 0, len(databases))
+	for _, database := range databases {
+		names = append(names, "database/"+database)
+	}
+	return names
+}
@@ -0,0 +1,57 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// WithTokenAuthenticator accepts bearer tokens in adapter API requests besides basic auth.
+// Identity of the token must be a principal, see UpdateApiPrincipals.
+func WithTokenAuthenticator(authenticator auth.TokenAuthenticator) ServerOption {
+	return func(o *serverOptions) { o.tokenAuthenticator = authenticator }
+}
+
+// tokenAuthenticatedKey marks requests authenticated by bearer tokens in locals
+const tokenAuthenticatedKey = "tokenAuthenticated"
+
+// bearerToken returns token of "Authorization: Bearer <token>" header
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	scheme, token, found := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// authenticateToken sets username of the principal the token is issued to, like basic auth middleware does
+func (c *reloadableCredentials) authenticateToken(ctx *fiber.Ctx, token string) error {
+	identity, err := c.tokens.Authenticate(ctx.UserContext(), token)
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return fiber.NewError(fiber.StatusUnauthorized, fmt.Sprintf("Bearer token is rejected: %v", err))
+	}
+	if _, found := c.roleOf(identity.Username, true); !found {
+		return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("%s is not a principal of adapter API", identity.Username))
+	}
+	ctx.Locals("username", identity.Username)
+	ctx.Locals(tokenAuthenticatedKey, true)
+	return ctx.Next()
+}
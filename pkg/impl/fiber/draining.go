@@ -0,0 +1,76 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+const inFlightPollInterval = 100 * time.Millisecond
+
+// requestTracker counts requests which are being processed by adapter handlers,
+// requests waiting for operation limits are counted too, so draining waits for them
+type requestTracker struct {
+	inFlight atomic.Int64
+}
+
+func (t *requestTracker) middleware(c *fiber.Ctx) error {
+	t.inFlight.Add(1)
+	defer t.inFlight.Add(-1)
+	return c.Next()
+}
+
+// wait blocks until there are no in-flight requests or ctx is done
+func (t *requestTracker) wait(ctx context.Context) error {
+	ticker := time.NewTicker(inFlightPollInterval)
+	defer ticker.Stop()
+	for t.inFlight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d requests are still in progress: %w", t.inFlight.Load(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// rejectWhenDraining responds with 503 if adapter is shutting down
+func (h *DbaasAdapterHandler) rejectWhenDraining(c *fiber.Ctx) error {
+	if h.draining.Load() {
+		h.logger.Info(fmt.Sprintf("Request %s %s is rejected, adapter is draining", c.Method(), c.Path()))
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Adapter is shutting down")
+	}
+	return c.Next()
+}
+
+// drain stops accepting database creation, moves physical database registration to draining state and
+// stops background jobs of administration service, in-flight requests are awaited by shutdown hook of the app
+func (h *DbaasAdapterHandler) drain(ctx context.Context) {
+	h.draining.Store(true)
+	if err := h.physicalService.Drain(ctx); err != nil {
+		h.logger.Warn(fmt.Sprintf("Physical database registration draining has failed: %v", err))
+	}
+	if drainer, ok := h.adminService.(service.Drainer); ok {
+		if err := drainer.Drain(ctx); err != nil {
+			h.logger.Warn(fmt.Sprintf("Background jobs of administration service are not stopped: %v", err))
+		}
+	}
+}
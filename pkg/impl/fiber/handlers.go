@@ -18,21 +18,20 @@ package fiber
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime/debug"
+	"slices"
 	"strconv"
-
-	"github.com/google/uuid"
-	"github.com/prometheus/client_golang/prometheus"
+	"sync/atomic"
 
 	"sync"
 
 	_ "github.com/Netcracker/qubership-dbaas-adapter-core/docs"
 	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/service"
-	"github.com/ansrivas/fiberprometheus/v2"
+	utilsCore "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/basicauth"
 	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	utils "github.com/gofiber/fiber/v2/utils"
@@ -52,25 +51,74 @@ func checkIfParamExistsOrDefault(c *fiber.Ctx, param string, emptyValue string,
 }
 
 type DbaasAdapterHandler struct {
+	// id of physical database served by the handler
+	id              string
+	rootPath        string
 	backupPath      string
 	adminService    service.CoreAdministrationServiceIface
 	physicalService *service.PhysicalDatabaseRegistrationService
 	backupService   service.BackupAdministrationService
+	supports        dto.Supports
 	logger          *zap.Logger
+	draining        atomic.Bool
+
+	// vaultMigrationPath is the full path of bulk migration to vault used in Location header
+	vaultMigrationPath string
 }
 
-// GetDatabases godoc
+// ForceRegistration godoc
 // @Tags Common dbaas adapter operations
 // @Summary Force physical database registration
 // @Description Force this adapter to immediately register itself in dbaas-aggregator.
 // @Description Adapter initiates background task that tries to register physical database in dbaas-aggregator,
 // @Description and responds with status 202 before the background task finishes.
+// @Description Returned track can be used to get the registration result.
+// @Produce  json
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
-// @Success 202 {string} Token "if physical database registration process has been started successfully."
+// @Success 202 {object} dto.PhysicalDatabaseRegistrationTrack "if physical database registration process has been started successfully."
+// @Failure 401 {object} dto.Problem "Authentication is required and has failed or has not been provided"
+// @Failure 403 {object} dto.Problem "Only dbaas aggregator can force registration"
+// @Failure 503 {object} dto.Problem "Adapter is draining or too many registrations are queued"
 // @Router /physical_database/force_registration [get]
 func (h *DbaasAdapterHandler) ForceRegistration(c *fiber.Ctx) error {
-	h.physicalService.ForceRegistration()
-	return c.SendStatus(fiber.StatusAccepted)
+	track, err := h.physicalService.ForceRegistration()
+	if err != nil {
+		return err
+	}
+	c.Location(h.rootPath + forceRegistrationPath + "/" + track.TrackId)
+	return c.Status(fiber.StatusAccepted).JSON(track)
+}
+
+// TrackForceRegistration godoc
+// @Tags Common dbaas adapter operations
+// @Summary Track forced physical database registration
+// @Description Returns status of the physical database registration initiated by force registration request
+// @Produce  json
+// @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
+// @Param trackId path string true "trackId"
+// @Success 200 {object} dto.PhysicalDatabaseRegistrationTrack
+// @Failure 401 {object} dto.Problem "Authentication is required and has failed or has not been provided"
+// @Failure 404 {object} dto.Problem "Registration track not found"
+// @Router /physical_database/force_registration/{trackId} [get]
+func (h *DbaasAdapterHandler) TrackForceRegistration(c *fiber.Ctx) error {
+	track, found := h.physicalService.GetRegistrationTrack(c.Params("trackId"))
+	if !found {
+		return fiber.NewError(fiber.StatusNotFound, "Registration track not found")
+	}
+	return c.JSON(track)
+}
+
+// GetSupports godoc
+// @Tags Database administration
+// @Summary Supported features
+// @Description Returns features supported by the adapter
+// @Produce  json
+// @Param appName path string true "Application name" Enums(postgresql, arangodb, clickhouse, mongodb, cassandra) default(postgresql)
+// @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
+// @Success 200 {object} dto.Supports
+// @Router /{appName}/supports [get]
+func (h *DbaasAdapterHandler) GetSupports(c *fiber.Ctx) error {
+	return c.JSON(h.supports)
 }
 
 // GetDatabases godoc
@@ -79,7 +127,7 @@ func (h *DbaasAdapterHandler) ForceRegistration(c *fiber.Ctx) error {
 // @Description Returns list with names of databases
 // @Produce  json
 // @Success 200 {object} []string "Databases listed"
-// @Failure 500 {string} Token "Error occurred while databases listing."
+// @Failure 500 {object} dto.Problem "Error occurred while databases listing."
 // @Param appName path string true "Application name" Enums(postgresql, arangodb, clickhouse, mongodb, cassandra) default(postgresql)
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Router /{appName}/databases [get]
@@ -100,7 +148,8 @@ func (h *DbaasAdapterHandler) GetDatabases(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param body body dto.DbCreateRequest true "Create DB body request"
 // @Success 201 {object} dto.DbCreateResponseMultiUser
-// @Failure 400 {string} Token "Provided parameters does not meet the requirements"
+// @Failure 400 {object} dto.Problem "Provided parameters does not meet the requirements"
+// @Failure 503 {object} dto.Problem "Adapter is shutting down"
 // @Router /{appName}/databases [post]
 func (h *DbaasAdapterHandler) CreateDatabase(c *fiber.Ctx) error {
 	//Create database
@@ -112,10 +161,19 @@ func (h *DbaasAdapterHandler) CreateDatabase(c *fiber.Ctx) error {
 		}
 	}
 	ctx := getRequestContext(c)
+	if err := validate.StructCtx(withSupportedRoles(ctx, h.adminService.GetSupportedRoles()), requestDb); err != nil {
+		return err
+	}
 	response, createErr := h.adminService.CreateDatabase(ctx, requestDb)
 	if createErr != nil {
-		h.logger.Info(fmt.Sprintf("Coud not create database: %s", createErr))
-		return c.Status(fiber.StatusBadRequest).SendString(createErr.Error())
+		h.logger.Info(fmt.Sprintf("Could not create database: %s", createErr))
+		return service.WrapError(createErr, service.InvalidArgument, dto.CreationFailedCode)
+	}
+	switch created := response.(type) {
+	case dto.DbCreateResponse:
+		setAuditResources(c, dbResourceNames(created.Resources)...)
+	case dto.DbCreateResponseMultiUser:
+		setAuditResources(c, dbResourceNames(created.Resources)...)
 	}
 	return c.Status(fiber.StatusCreated).JSON(&response)
 }
@@ -130,6 +188,8 @@ func (h *DbaasAdapterHandler) CreateDatabase(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param body body []dto.DbResource true "List of resources to drop"
 // @Success 200 {object} []dto.DbResource "Drop successful"
+// @Failure 500 {object} dto.Problem "Some resources are not dropped, results of all the resources are in resources field instead of the response body of previous versions"
+// @Failure 400 {object} dto.Problem "Request body is not valid, violations are listed in errors field"
 // @Router /{appName}/resources/bulk-drop [post]
 func (h *DbaasAdapterHandler) BulkDrop(c *fiber.Ctx) error {
 	//Delete resources
@@ -138,10 +198,14 @@ func (h *DbaasAdapterHandler) BulkDrop(c *fiber.Ctx) error {
 	if parserErr != nil {
 		return parserErr
 	}
+	if err := validate.Var(resources, "dive"); err != nil {
+		return err
+	}
+	setAuditResources(c, dbResourceNames(resources)...)
 	ctx := getRequestContext(c)
 	response, dropErr := h.adminService.DropResources(ctx, resources)
 	if dropErr {
-		c.Status(fiber.StatusInternalServerError)
+		return &dropFailedError{resources: *response}
 	}
 	return c.JSON(&response)
 }
@@ -158,7 +222,8 @@ func (h *DbaasAdapterHandler) BulkDrop(c *fiber.Ctx) error {
 // @Param connectionProperties query bool false "If present - should return resources of each requested database"
 // @Param body body []string true "List of names of databases to describe"
 // @Success 200 {object} map[string]dto.LogicalDatabaseDescribed
-// @Failure 500 {string} Token "Error occurred while databases describe."
+// @Failure 500 {object} dto.Problem "Error occurred while databases describe."
+// @Failure 400 {object} dto.Problem "Request body is not valid, violations are listed in errors field"
 // @Router /{appName}/describe/databases [post]
 func (h *DbaasAdapterHandler) DescribeDatabases(c *fiber.Ctx) error {
 	var databases []string
@@ -168,6 +233,9 @@ func (h *DbaasAdapterHandler) DescribeDatabases(c *fiber.Ctx) error {
 			return parserErr
 		}
 	}
+	if err := validate.Var(databases, "dive,required"); err != nil {
+		return err
+	}
 	ctx := getRequestContext(c)
 	showResources, _ := strconv.ParseBool(checkIfParamExistsOrDefault(c, "resources", "true", "false"))
 	showConnectionProperties, _ := strconv.ParseBool(checkIfParamExistsOrDefault(c, "connectionProperties", "true", "false"))
@@ -186,7 +254,8 @@ func (h *DbaasAdapterHandler) DescribeDatabases(c *fiber.Ctx) error {
 // @Param dbName path string true "Databases to update"
 // @Param body body map[string]interface{} true "New metadata"
 // @Success 200 {string} Token "Update metadata was successful"
-// @Failure 500 {string} Token "Error was occurred during update metadata."
+// @Failure 500 {object} dto.Problem "Error was occurred during update metadata."
+// @Failure 400 {object} dto.Problem "Request body is not valid, violations are listed in errors field"
 // @Router /{appName}/databases/{dbName}/metadata [put]
 func (h *DbaasAdapterHandler) UpdateMetadata(c *fiber.Ctx) error {
 	// Update DB Meta
@@ -195,6 +264,9 @@ func (h *DbaasAdapterHandler) UpdateMetadata(c *fiber.Ctx) error {
 	if parseErr != nil {
 		return parseErr
 	}
+	if err := validate.Var(newMetadata, "required,classifier"); err != nil {
+		return err
+	}
 	dbName := c.Params("dbName")
 	ctx := getRequestContext(c)
 	h.adminService.UpdateMetadata(ctx, newMetadata, dbName)
@@ -210,9 +282,15 @@ func createUser(userName string, adminService service.CoreAdministrationServiceI
 		}
 	}
 	ctx := getRequestContext(c)
+	if err := validate.StructCtx(withSupportedRoles(ctx, adminService.GetSupportedRoles()), createUserRequest); err != nil {
+		return err
+	}
 	createdUser, createErr := adminService.CreateUser(ctx, userName, createUserRequest)
 	if createErr != nil {
-		return c.Status(fiber.StatusBadRequest).SendString(createErr.Error())
+		return service.WrapError(createErr, service.InvalidArgument, dto.CreationFailedCode)
+	}
+	if createdUser != nil {
+		setAuditResources(c, dbResourceNames(createdUser.Resources)...)
 	}
 	return c.Status(fiber.StatusCreated).JSON(&createdUser)
 }
@@ -228,7 +306,7 @@ func createUser(userName string, adminService service.CoreAdministrationServiceI
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param body body dto.UserCreateRequest true "Info about user to create"
 // @Success 201 {object} dto.CreatedUser "User created"
-// @Failure 500 {string} Token "Error occurred while user creation."
+// @Failure 400 {object} dto.Problem "Error occurred while user creation."
 // @Router /{appName}/users [put]
 func (h *DbaasAdapterHandler) CreateNewUser(c *fiber.Ctx) error {
 	return createUser("", h.adminService, c)
@@ -246,13 +324,53 @@ func (h *DbaasAdapterHandler) CreateNewUser(c *fiber.Ctx) error {
 // @Param name path string true "The username for creating user to database"
 // @Param body body dto.UserCreateRequest true "Info about user to ensure"
 // @Success 201 {object} dto.CreatedUser
-// @Failure 500 {string} Token "Error occurred while user creation."
+// @Failure 400 {object} dto.Problem "Error occurred while user creation."
 // @Router /{appName}/users/{name} [put]
 func (h *DbaasAdapterHandler) CreateUser(c *fiber.Ctx) error {
 	userName := c.Params("name")
 	return createUser(userName, h.adminService, c)
 }
 
+// Rotate user password godoc
+// @Tags Database administration
+// @Summary Rotate user password
+// @Description Sets new generated password of the user and returns connection properties with the new password.
+// @Description Passwords stored in vault are not rotated by this operation.
+// @Produce  json
+// @Param appName path string true "Application name" Enums(postgresql, arangodb, clickhouse, mongodb, cassandra) default(postgresql)
+// @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
+// @Param dbName path string true "Database of the user"
+// @Param name path string true "The username whose password should be rotated"
+// @Success 200 {object} dto.CreatedUser
+// @Failure 400 {object} dto.Problem "Password of the user is managed by vault"
+// @Failure 404 {object} dto.Problem "User is not found in the database"
+// @Failure 500 {object} dto.Problem "Error occurred while password rotation."
+// @Failure 501 {object} dto.Problem "Operation is not supported by the adapter"
+// @Router /{appName}/databases/{dbName}/users/{name}/rotate [post]
+func (h *DbaasAdapterHandler) RotatePassword(c *fiber.Ctx) error {
+	ctx := getRequestContext(c)
+	rotatedUser, err := h.adminService.RotatePassword(ctx, c.Params("dbName"), c.Params("name"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(rotatedUser)
+}
+
+// Password rotation records godoc
+// @Tags Database administration
+// @Summary Password rotation audit
+// @Description Returns the latest password rotations of the user, performed on demand or by rotation policy
+// @Produce  json
+// @Param appName path string true "Application name" Enums(postgresql, arangodb, clickhouse, mongodb, cassandra) default(postgresql)
+// @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
+// @Param dbName path string true "Database of the user"
+// @Param name path string true "The username"
+// @Success 200 {object} []dto.PasswordRotationRecord
+// @Router /{appName}/databases/{dbName}/users/{name}/rotations [get]
+func (h *DbaasAdapterHandler) GetPasswordRotations(c *fiber.Ctx) error {
+	return c.JSON(h.adminService.GetPasswordRotationRecords(c.Params("dbName"), c.Params("name")))
+}
+
 // Physical database registration user godoc
 // @Tags Database administration
 // @Summary Physical database information
@@ -262,12 +380,12 @@ func (h *DbaasAdapterHandler) CreateUser(c *fiber.Ctx) error {
 // @Param appName path string true "Application name" Enums(postgresql, arangodb, clickhouse, mongodb, cassandra) default(postgresql)
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Success 200 {object} service.PhysicalDatabase "Own physical database information"
-// @Failure 404 {string} Token "Identifier is not specified"
+// @Failure 404 {object} dto.Problem "Identifier is not specified"
 // @Router /{appName}/physical_database [get]
 func (h *DbaasAdapterHandler) PhysicalRegistration(c *fiber.Ctx) error { //TODO Check in dbaas adapter
 	phydb := h.physicalService.GetPhysicalDatabase()
 	if phydb == nil {
-		return c.Status(fiber.StatusNotFound).SendString("Physical database identifier not specified")
+		return fiber.NewError(fiber.StatusNotFound, "Physical database identifier not specified")
 	}
 	return c.JSON(&phydb)
 
@@ -283,7 +401,8 @@ func (h *DbaasAdapterHandler) PhysicalRegistration(c *fiber.Ctx) error { //TODO
 // @Param appName path string true "Application name" Enums(postgresql, arangodb, clickhouse, mongodb, cassandra) default(postgresql)
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Success 202 {object} dto.DatabaseAdapterBaseTrack
-// @Failure 500 {string} Token "Unknown error"
+// @Failure 500 {object} dto.Problem "Unknown error"
+// @Failure 400 {object} dto.Problem "Request body is not valid, violations are listed in errors field"
 // @Router /{appName}/backups/collect [post]
 func (h *DbaasAdapterHandler) Collect(c *fiber.Ctx) error {
 	var databases []string
@@ -291,6 +410,10 @@ func (h *DbaasAdapterHandler) Collect(c *fiber.Ctx) error {
 	if parserErr != nil {
 		return parserErr
 	}
+	if err := validate.Var(databases, "dive,required"); err != nil {
+		return err
+	}
+	setAuditResources(c, databaseResourceNames(databases)...)
 	ctx := getRequestContext(c)
 	allowEviction, _ := strconv.ParseBool(checkIfParamExistsOrDefault(c, "allowEviction", "true", "true"))
 	keepFromRequest := checkIfParamExistsOrDefault(c, "keep", "", "")
@@ -310,7 +433,7 @@ func (h *DbaasAdapterHandler) Collect(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param trackId path string true "trackId"
 // @Success 200 {object} dto.DatabaseAdapterBaseTrack
-// @Failure 500 {string} Token "Unknown error"
+// @Failure 500 {object} dto.Problem "Unknown error"
 // @Router /{appName}/backups/track/backup/{trackId} [get]
 func (h *DbaasAdapterHandler) TrackBackup(c *fiber.Ctx) error {
 	trackId := c.Params("trackId")
@@ -318,7 +441,7 @@ func (h *DbaasAdapterHandler) TrackBackup(c *fiber.Ctx) error {
 	track, found := h.backupService.TrackBackup(ctx, trackId)
 	h.logger.Debug(fmt.Sprintf("Track: %+v", track))
 	if !found {
-		return c.Status(fiber.StatusNotFound).SendString("Backup process not found")
+		return fiber.NewError(fiber.StatusNotFound, "Backup process not found")
 	}
 	return c.JSON(track)
 }
@@ -332,7 +455,7 @@ func (h *DbaasAdapterHandler) TrackBackup(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param trackId path string true "trackId"
 // @Success 200 {object} dto.DatabaseAdapterBaseTrack
-// @Failure 500 {string} Token "Unknown error"
+// @Failure 500 {object} dto.Problem "Unknown error"
 // @Router /{appName}/backups/track/restore/{trackId} [get]
 func (h *DbaasAdapterHandler) TrackRestore(c *fiber.Ctx) error {
 	trackId := c.Params("trackId")
@@ -340,7 +463,7 @@ func (h *DbaasAdapterHandler) TrackRestore(c *fiber.Ctx) error {
 	track, found := h.backupService.TrackRestore(ctx, trackId)
 	h.logger.Debug(fmt.Sprintf("Track: %+v", track))
 	if !found {
-		return c.Status(fiber.StatusNotFound).SendString("Restore process not found")
+		return fiber.NewError(fiber.StatusNotFound, "Restore process not found")
 	}
 	return c.JSON(track)
 }
@@ -356,8 +479,9 @@ func (h *DbaasAdapterHandler) TrackRestore(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param backupId path string true "Backup identifier"
 // @Success 202 {object} dto.DatabaseAdapterRestoreTrack "Restore requested"
-// @Failure 500 {string} Token "Unknown error"
-// @Failure 501 {string} Token "Cannot restore backup without explicitly specified list of databases in it"
+// @Failure 500 {object} dto.Problem "Unknown error"
+// @Failure 501 {object} dto.Problem "Cannot restore backup without explicitly specified list of databases in it"
+// @Failure 400 {object} dto.Problem "Request body is not valid, violations are listed in errors field"
 // @Router /{appName}/backups/{backupId}/restoration [post]
 func (h *DbaasAdapterHandler) Restoration(c *fiber.Ctx) error {
 	var request dto.RestorationRequest
@@ -365,20 +489,15 @@ func (h *DbaasAdapterHandler) Restoration(c *fiber.Ctx) error {
 	if parserErr != nil {
 		return parserErr
 	}
+	if err := validate.Struct(request); err != nil {
+		return err
+	}
 	backupId := c.Params("backupId")
 	ctx := getRequestContext(c)
 	h.logger.Debug(fmt.Sprintf("Backup %v requested to be restored with %v databases specified, names regeneration = %v", backupId, len(request.Databases), request.RegenerateNames))
 	actionTrack, trackErr := h.backupService.RestoreBackup(ctx, backupId, request.Databases, request.RegenerateNames, false)
 	if trackErr != nil {
-		if _, ok := trackErr.(*dto.BackupRestoresOnlySpecifiedDBsError); ok {
-			return c.
-				Status(fiber.StatusNotImplemented).
-				SendString(trackErr.Error())
-		} else {
-			return c.
-				Status(fiber.StatusInternalServerError).
-				SendString(trackErr.Error())
-		}
+		return trackErr
 	}
 	c.Location(locationPath(h.backupPath, "/track/restore/", actionTrack.TrackId))
 	h.logger.Debug(fmt.Sprintf("Track: %+v", actionTrack))
@@ -397,8 +516,9 @@ func (h *DbaasAdapterHandler) Restoration(c *fiber.Ctx) error {
 // @Param backupId path string true "Backup identifier"
 // @Param regenerateNames query bool false "If this parameter has value true then restored databases will have new names and will be passed through associative array changedNameDb in response object"
 // @Success 202 {object} dto.DatabaseAdapterRestoreTrack
-// @Failure 500 {string} Token "Unknown error"
-// @Failure 501 {string} Token "Cannot restore backup without explicitly specified list of databases in it"
+// @Failure 500 {object} dto.Problem "Unknown error"
+// @Failure 501 {object} dto.Problem "Cannot restore backup without explicitly specified list of databases in it"
+// @Failure 400 {object} dto.Problem "Request body is not valid, violations are listed in errors field"
 // @Router /{appName}/backups/{backupId}/restore [post]
 // @Deprecated
 func (h *DbaasAdapterHandler) Restore(c *fiber.Ctx) error {
@@ -407,6 +527,9 @@ func (h *DbaasAdapterHandler) Restore(c *fiber.Ctx) error {
 	if parserErr != nil {
 		return parserErr
 	}
+	if err := validate.Var(databases, "dive,required"); err != nil {
+		return err
+	}
 	backupId := c.Params("backupId")
 	ctx := getRequestContext(c)
 	regenerateNames, _ := strconv.ParseBool(checkIfParamExistsOrDefault(c, "regenerateNames", "false", "false"))
@@ -418,15 +541,7 @@ func (h *DbaasAdapterHandler) Restore(c *fiber.Ctx) error {
 
 	actionTrack, trackErr := h.backupService.RestoreBackup(ctx, backupId, dbInfos, regenerateNames, true)
 	if trackErr != nil {
-		if _, ok := trackErr.(*dto.BackupRestoresOnlySpecifiedDBsError); ok {
-			return c.
-				Status(fiber.StatusNotImplemented).
-				SendString(trackErr.Error())
-		} else {
-			return c.
-				Status(fiber.StatusInternalServerError).
-				SendString(trackErr.Error())
-		}
+		return trackErr
 	}
 	c.Location(locationPath(h.backupPath, "/track/restore/", actionTrack.TrackId))
 	h.logger.Debug(fmt.Sprintf("Track: %+v", actionTrack))
@@ -443,7 +558,7 @@ func (h *DbaasAdapterHandler) Restore(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param backupId path string true "trackId"
 // @Success 200 {string} Token "Succesfull delete"
-// @Failure 500 {string} Token "Unknown error"
+// @Failure 500 {object} dto.Problem "Unknown error"
 // @Router /{appName}/backups/{backupId} [delete]
 func (h *DbaasAdapterHandler) DeleteBackup(c *fiber.Ctx) error {
 	backupId := c.Params("backupId")
@@ -451,7 +566,7 @@ func (h *DbaasAdapterHandler) DeleteBackup(c *fiber.Ctx) error {
 	backup, found := h.backupService.EvictBackup(ctx, backupId)
 	h.logger.Debug(fmt.Sprintf("backup: %s", backup))
 	if !found {
-		return c.Status(fiber.StatusNotFound).SendString("Backup not found")
+		return fiber.NewError(fiber.StatusNotFound, "Backup not found")
 	}
 	return c.SendString(backup)
 }
@@ -467,7 +582,7 @@ func (h *DbaasAdapterHandler) DeleteBackup(c *fiber.Ctx) error {
 // @Param dbName path string true "Databases to update"
 // @Param userName path string true "User whose password should be migrated"
 // @Success 200 {string} string "Vault role name"
-// @Failure 500 {string} Token "Unknown error"
+// @Failure 500 {object} dto.Problem "Unknown error"
 // @Router /{appName}/databases/{dbName}/migrate-to-vault/{userName} [post]
 func (h *DbaasAdapterHandler) MigrateToVault(c *fiber.Ctx) error {
 	ctx := getRequestContext(c)
@@ -478,6 +593,89 @@ func (h *DbaasAdapterHandler) MigrateToVault(c *fiber.Ctx) error {
 	return c.SendString(roleName)
 }
 
+// Migrate DB password from vault godoc
+// @Tags Database administration
+// @Summary Migrate DB password from vault
+// @Description Sets new generated password of the user, deletes its vault role and returns the new password
+// @Produce  plain
+// @Param appName path string true "Application name" Enums(postgresql, arangodb, clickhouse, mongodb, cassandra) default(postgresql)
+// @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
+// @Param dbName path string true "Database of the user"
+// @Param userName path string true "User whose password should be migrated from vault"
+// @Success 200 {string} string "New password of the user, Warning header is set if vault role cannot be deleted and the migration should be repeated"
+// @Failure 400 {object} dto.Problem "Vault integration is disabled or password of the user is not stored in vault"
+// @Failure 500 {object} dto.Problem "Unknown error"
+// @Failure 501 {object} dto.Problem "Operation is not supported by the adapter"
+// @Router /{appName}/databases/{dbName}/migrate-from-vault/{userName} [post]
+func (h *DbaasAdapterHandler) MigrateFromVault(c *fiber.Ctx) error {
+	ctx := getRequestContext(c)
+	password, err := h.adminService.MigrateFromVault(ctx, c.Params("dbName"), c.Params("userName"))
+	if errors.Is(err, service.ErrVaultRoleNotDeleted) && password != "" {
+		// password is changed already, so it is responded, otherwise the user is not accessible
+		c.Set(fiber.HeaderWarning, fmt.Sprintf("199 - %q", err.Error()))
+		return c.SendString(password)
+	}
+	if err != nil {
+		return err
+	}
+	return c.SendString(password)
+}
+
+// StartVaultMigration godoc
+// @Tags Database administration
+// @Summary Migrate all users to vault
+// @Description Starts background migration to vault of all users which passwords are not stored in vault yet.
+// @Description Dry run only reports users which would be migrated. Interrupted migration is resumed by the next request, parameters of the next request are used and databases processed already are skipped.
+// @Accept   json
+// @Produce  json
+// @Param appName path string true "Application name" Enums(postgresql, arangodb, clickhouse, mongodb, cassandra) default(postgresql)
+// @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
+// @Param body body dto.VaultMigrationRequest false "Migration parameters"
+// @Success 202 {object} dto.VaultMigrationJob "if migration has been started successfully"
+// @Failure 400 {object} dto.Problem "Vault integration is disabled"
+// @Failure 409 {object} dto.Problem "Migration to vault is already in progress"
+// @Router /{appName}/migrate-to-vault [post]
+func (h *DbaasAdapterHandler) StartVaultMigration(c *fiber.Ctx) error {
+	var request dto.VaultMigrationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return err
+		}
+	}
+	if err := validate.Struct(request); err != nil {
+		return err
+	}
+	setAuditResources(c, databaseResourceNames(request.Databases)...)
+	job, err := h.adminService.StartVaultMigration(getRequestContext(c), request)
+	if err != nil {
+		return err
+	}
+	c.Location(h.vaultMigrationPath)
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// GetVaultMigration godoc
+// @Tags Database administration
+// @Summary Status of migration to vault
+// @Description Returns status of the last migration of all users to vault
+// @Produce  json
+// @Param appName path string true "Application name" Enums(postgresql, arangodb, clickhouse, mongodb, cassandra) default(postgresql)
+// @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
+// @Success 200 {object} dto.VaultMigrationJob
+// @Failure 404 {object} dto.Problem "Migration to vault has not been started"
+// @Router /{appName}/migrate-to-vault [get]
+func (h *DbaasAdapterHandler) GetVaultMigration(c *fiber.Ctx) error {
+	job := h.adminService.GetVaultMigrationJob()
+	if job == nil {
+		return fiber.NewError(fiber.StatusNotFound, "Migration to vault has not been started")
+	}
+	return c.JSON(job)
+}
+
+const forceRegistrationPath = "/physical_database/force_registration"
+
+const vaultMigrationPath = "/migrate-to-vault"
+
 func locationPath(rootPath string, trackPath string, taskId string) string {
 	return rootPath + trackPath + taskId
 }
@@ -487,22 +685,39 @@ func rootPath(version dto.ApiVersion) string {
 }
 
 func getRequestContext(c *fiber.Ctx) context.Context {
-	requestId := c.Request().Header.Peek("X-Request-ID")
-	if len(requestId) == 0 {
-		id := uuid.New().String()
-		c.Set("X-Request-ID", id)
-		requestId = []byte(id)
-	}
-
-	bg := context.Background()
-	ctx := context.WithValue(bg, "request_id", requestId)
+	// user context keeps span of the request, see tracingMiddleware
+	ctx := context.WithValue(c.UserContext(), "request_id", []byte(requestId(c)))
 	return ctx
 }
 
-var once sync.Once
+const backupsPath = "/backups"
+
+// PhysicalDatabaseIdHeader selects physical database when several physical databases are served without route prefix
+const PhysicalDatabaseIdHeader = "X-Physical-Database-Id"
+
+// PhysicalDatabaseServices describes one physical database served by the adapter
+type PhysicalDatabaseServices struct {
+	// Id identifies physical database in header based routing and in health report
+	Id string
+	// RoutePrefix is prepended to all the adapter routes of physical database, e.g. "/cluster-a".
+	// Physical database must be registered in DBaaS with adapter address containing the same prefix.
+	// Physical databases without RoutePrefix are selected by PhysicalDatabaseIdHeader.
+	RoutePrefix     string
+	AdminService    service.CoreAdministrationServiceIface
+	PhysicalService *service.PhysicalDatabaseRegistrationService
+	BackupService   service.BackupAdministrationService
+	Supports        dto.Supports
+}
 
 // @title Dbaas adapter API
+// @description Errors are responded as application/problem+json problem details (RFC 7807) with stable error code,
+// @description request id and retryable flag, see dao.Problem definition.
 // @BasePath /api/{apiVersion}/dbaas/adapter
+
+// BuildFiberDBaaSAdapterHandlers registers adapter API in the app created by adapter and returns state of the app,
+// which is used to drain it on shutdown, or error of BuildFiberMultiDBaaSAdapterHandlers. Options set metrics registerer, auditor, bearer token authentication
+// and limits, options of listeners are ignored. Use BuildFiberMultiDBaaSAdapterHandlers with ServerFromContext
+// in setUp of GetFiberServer and RunFiberServer, so the app is drained by RunFiberServer.
 func BuildFiberDBaaSAdapterHandlers(
 	app *fiber.App,
 	user string,
@@ -514,38 +729,82 @@ func BuildFiberDBaaSAdapterHandlers(
 	supports dto.Supports,
 	logger *zap.Logger,
 	profiler bool,
-	serviceName string) { //TODO changes - added for running multiple tests
+	serviceName string,
+	opts ...ServerOption) (*AdapterServer, error) {
+
+	server := NewAdapterServer(app, opts...)
+	err := BuildFiberMultiDBaaSAdapterHandlers(server, user, pass, appPath, []PhysicalDatabaseServices{
+		{
+			AdminService:    coreAdminService,
+			PhysicalService: physicalService,
+			BackupService:   backupService,
+			Supports:        supports,
+		},
+	}, logger, profiler, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// BuildFiberMultiDBaaSAdapterHandlers registers adapter API for several physical databases served by one adapter.
+// Every physical database has its own administration services and registration loop.
+// Settings and state of the app are kept by server, see GetAdapterServer and NewAdapterServer.
+// Function fails if ids of physical databases are empty or not unique, so requests are not routed to wrong database.
+func BuildFiberMultiDBaaSAdapterHandlers(
+	server *AdapterServer,
+	user string,
+	pass string,
+	appPath string,
+	physicalDatabases []PhysicalDatabaseServices,
+	logger *zap.Logger,
+	profiler bool,
+	serviceName string) error {
 
+	if err := validatePhysicalDatabases(physicalDatabases); err != nil {
+		return err
+	}
 	if serviceName == "" {
 		serviceName = "dbaas-adapter"
 	}
-	backupsPath := "/backups"
 
-	supportCopy := make(map[string]bool)
-	for key, val := range supports {
-		supportCopy[key] = val
+	recoverConfig := recover.ConfigDefault
+	recoverConfig.EnableStackTrace = true
+	recoverConfig.StackTraceHandler = func(c *fiber.Ctx, e interface{}) {
+		logger.Error(fmt.Sprintf("Panic: %s\nStacktrace:\n%s", utilsCore.RedactSecrets(fmt.Sprintf("%+v", e)), string(debug.Stack())))
 	}
 
+	app := server.app
+	// health, metrics, pprof and log level are served on separate port if it is configured
+	management := server.management
+	if management != app {
+		management.Use(recover.New(recoverConfig))
+	}
 	if profiler {
-		app.Use(pprof.New())
+		management.Use(pprof.New())
 		logger.Debug("Profiling is activated")
 	}
 
-	//this fails fiber restart
-	once.Do(
-		func() {
-			prometheus := fiberprometheus.NewWithRegistry(prometheus.DefaultRegisterer, serviceName, "", "", nil)
-			prometheus.RegisterAt(app, "/metrics")
-			app.Use(prometheus.Middleware)
-		},
-	)
-
-	recoverConfig := recover.ConfigDefault
-	recoverConfig.EnableStackTrace = true
-	recoverConfig.StackTraceHandler = func(c *fiber.Ctx, e interface{}) {
-		logger.Error(fmt.Sprintf("Panic: %+v\nStacktrace:\n%s", e, string(debug.Stack())))
+	physicalServices := make([]*service.PhysicalDatabaseRegistrationService, 0, len(physicalDatabases))
+	for _, physicalDatabase := range physicalDatabases {
+		physicalServices = append(physicalServices, physicalDatabase.PhysicalService)
 	}
+	server.credentials = newApiCredentials(user, pass, server.options.tokenAuthenticator, physicalServices)
+	credentials := server.credentials
+	basicAuth := credentials.basicAuth()
+	readers := credentials.allow(dto.ReadOnlyRole)
+	// metrics and swagger are served without authentication unless they are protected by WithProtectedMetrics
+	var metricsAuth []fiber.Handler
+	if server.options.protectMetrics {
+		metricsAuth = []fiber.Handler{basicAuth, readers}
+	}
+
+	server.registerMetrics(serviceName, metricsAuth...)
+	server.limiters = newOperationLimiters(server.options.operationLimits, server.metricsRegisterer, serviceName)
+	app.Use(tracingMiddleware)
 	app.Use(recover.New(recoverConfig))
+	// draining waits for all the requests of the app
+	app.Use(server.requests.middleware)
 	app.Use(func(c *fiber.Ctx) error {
 		// Setting defaults for existed handlers
 		c.Request().Header.SetContentType(utils.GetMIME("json"))
@@ -553,102 +812,256 @@ func BuildFiberDBaaSAdapterHandlers(
 		return c.Next()
 	})
 
-	general := app.Group(rootPath(coreAdminService.GetVersion()), func(c *fiber.Ctx) error {
-		//Common API Handler
-		return c.Next()
-	})
-
-	app.Get("/swagger/*", swagger.New(swagger.Config{ // custom
+	app.Get("/swagger/*", append(metricsAuth, swagger.New(swagger.Config{ // custom
 		URL:          "/swagger/doc.json",
 		DeepLinking:  false,
 		ValidatorUrl: "none",
-	}))
+	}))...)
+
+	handlers := make([]*DbaasAdapterHandler, 0, len(physicalDatabases))
+	// handlers without route prefix grouped by API version
+	headerRouted := make(map[dto.ApiVersion]map[string]*DbaasAdapterHandler)
+	for _, physicalDatabase := range physicalDatabases {
+		version := physicalDatabase.AdminService.GetVersion()
+		adapterHandler := &DbaasAdapterHandler{
+			id:              physicalDatabase.Id,
+			adminService:    physicalDatabase.AdminService,
+			backupService:   physicalDatabase.BackupService,
+			physicalService: physicalDatabase.PhysicalService,
+			supports:        copySupports(physicalDatabase.Supports),
+			logger:          logger,
+			rootPath:        physicalDatabase.RoutePrefix + rootPath(version),
+		}
+		adapterHandler.backupPath = adapterHandler.rootPath + appPath + backupsPath
+		adapterHandler.vaultMigrationPath = adapterHandler.rootPath + appPath + vaultMigrationPath
+		handlers = append(handlers, adapterHandler)
+
+		if physicalDatabase.RoutePrefix != "" {
+			server.registerAdapterRoutes(app.Group(adapterHandler.rootPath), appPath, fixedHandler(adapterHandler))
+			management.Get(physicalDatabase.RoutePrefix+"/health", func(c *fiber.Ctx) error {
+				return c.JSON(buildHealth([]*DbaasAdapterHandler{adapterHandler}))
+			})
+		} else {
+			if headerRouted[version] == nil {
+				headerRouted[version] = make(map[string]*DbaasAdapterHandler)
+			}
+			headerRouted[version][physicalDatabase.Id] = adapterHandler
+		}
+	}
+	for version, versionHandlers := range headerRouted {
+		general := app.Group(rootPath(version), func(c *fiber.Ctx) error {
+			//Common API Handler
+			return c.Next()
+		})
+		server.registerAdapterRoutes(general, appPath, headerHandler(versionHandlers))
+	}
+
+	server.RegisterShutdownHook(func(ctx context.Context) {
+		logger.Info("Draining of dbaas adapter is started")
+		var wg sync.WaitGroup
+		for _, adapterHandler := range handlers {
+			wg.Add(1)
+			go func(h *DbaasAdapterHandler) {
+				defer wg.Done()
+				h.drain(ctx)
+			}(adapterHandler)
+		}
+		wg.Wait()
+		if err := server.requests.wait(ctx); err != nil {
+			logger.Warn(fmt.Sprintf("Waiting for in-flight requests has failed: %v", err))
+		}
+		logger.Info("Draining of dbaas adapter is finished")
+	})
+
+	for _, adapterHandler := range handlers {
+		adapterHandler.adminService.PreStart()
+		adapterHandler.physicalService.StartRegister()
+	}
+	management.Get("/health", func(c *fiber.Ctx) error {
+		return c.JSON(buildHealth(handlers))
+	})
+	management.Get("/log-level", respondProblems, basicAuth, credentials.allow(dto.ReadOnlyRole, dto.BackupOperatorRole), getLogLevel)
+	management.Put("/log-level", respondProblems, basicAuth, credentials.allow(), setLogLevel)
+	return nil
+}
+
+// validatePhysicalDatabases checks that ids identify physical databases unambiguously,
+// id may be omitted if there is the only physical database
+func validatePhysicalDatabases(physicalDatabases []PhysicalDatabaseServices) error {
+	ids := make(map[string]bool, len(physicalDatabases))
+	for _, physicalDatabase := range physicalDatabases {
+		if physicalDatabase.Id == "" && len(physicalDatabases) > 1 {
+			return fmt.Errorf("id of physical database is required when several physical databases are served")
+		}
+		if ids[physicalDatabase.Id] {
+			return fmt.Errorf("id %s of physical database is not unique", physicalDatabase.Id)
+		}
+		ids[physicalDatabase.Id] = true
+	}
+	return nil
+}
+
+// handlerResolver returns adapter handler of physical database the request is addressed to
+type handlerResolver func(c *fiber.Ctx) (*DbaasAdapterHandler, error)
+
+// handle binds handler method to the physical database resolved for the request
+func (resolve handlerResolver) handle(method func(h *DbaasAdapterHandler, c *fiber.Ctx) error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		h, err := resolve(c)
+		if err != nil {
+			return err
+		}
+		return method(h, c)
+	}
+}
+
+func fixedHandler(h *DbaasAdapterHandler) handlerResolver {
+	return func(c *fiber.Ctx) (*DbaasAdapterHandler, error) {
+		return h, nil
+	}
+}
+
+// headerHandler resolves physical database by PhysicalDatabaseIdHeader.
+// Header may be omitted if there is only one physical database.
+func headerHandler(handlers map[string]*DbaasAdapterHandler) handlerResolver {
+	var single *DbaasAdapterHandler
+	if len(handlers) == 1 {
+		for _, h := range handlers {
+			single = h
+		}
+	}
+	return func(c *fiber.Ctx) (*DbaasAdapterHandler, error) {
+		id := c.Get(PhysicalDatabaseIdHeader)
+		if id == "" {
+			if single != nil {
+				return single, nil
+			}
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Header %s is required", PhysicalDatabaseIdHeader))
+		}
+		if h, ok := handlers[id]; ok {
+			return h, nil
+		}
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Physical database %s is not served by this adapter", id))
+	}
+}
+
+// registerAdapterRoutes registers adapter API routes, every route except supports requires authentication.
+// Mutating routes are allowed to aggregator only, backup routes to backup operators and read routes to read-only users too.
+// Routes are limited by operation class, see WithOperationLimits.
+func (s *AdapterServer) registerAdapterRoutes(general fiber.Router, appPath string, resolve handlerResolver) {
+	credentials, limiters, audited := s.credentials, s.limiters, s.audited
+	basicAuth := credentials.basicAuth()
+	aggregatorOnly := credentials.allow()
+	readers := credentials.allow(dto.ReadOnlyRole)
+	backupOperators := credentials.allow(dto.BackupOperatorRole)
+	backupReaders := credentials.allow(dto.BackupOperatorRole, dto.ReadOnlyRole)
 
 	// /redis /cassandra etc
-	database := general.Group(appPath, func(c *fiber.Ctx) error {
+	database := general.Group(appPath, respondProblems, func(c *fiber.Ctx) error {
 		//DB API Handler
 		return c.Next()
 	})
 
-	database.Get("/supports", func(c *fiber.Ctx) error {
-		return c.JSON(supportCopy)
-	})
+	database.Get("/supports", resolve.handle((*DbaasAdapterHandler).GetSupports))
 
-	database.Use(basicauth.New(basicauth.Config{
-		Realm: "This API is for using by dbaas aggregator only",
-		Users: map[string]string{
-			user: pass,
-		},
-	}))
+	database.Use(basicAuth)
 
-	adapterHandler := &DbaasAdapterHandler{
-		adminService:    coreAdminService,
-		backupService:   backupService,
-		physicalService: physicalService,
-		logger:          logger,
-		backupPath:      rootPath(coreAdminService.GetVersion()) + appPath + backupsPath,
-	}
+	database.Post("/databases", audited("CreateDatabase"), aggregatorOnly, limiters.limit(CreateOperations), resolve.handle((*DbaasAdapterHandler).rejectWhenDraining), resolve.handle((*DbaasAdapterHandler).CreateDatabase))
+
+	database.Get("/databases", readers, limiters.limit(ReadOperations), resolve.handle((*DbaasAdapterHandler).GetDatabases))
 
-	database.Post("/databases", adapterHandler.CreateDatabase)
+	database.Put("/databases/:dbName/metadata", audited("UpdateMetadata"), aggregatorOnly, limiters.limit(UpdateOperations), resolve.handle((*DbaasAdapterHandler).UpdateMetadata))
 
-	database.Get("/databases", adapterHandler.GetDatabases)
+	database.Post("/databases/:dbName/migrate-to-vault/:userName", audited("MigrateToVault"), aggregatorOnly, limiters.limit(UpdateOperations), resolve.handle((*DbaasAdapterHandler).MigrateToVault))
 
-	database.Put("/databases/:dbName/metadata", adapterHandler.UpdateMetadata)
+	database.Post("/databases/:dbName/migrate-from-vault/:userName", audited("MigrateFromVault"), aggregatorOnly, limiters.limit(UpdateOperations), resolve.handle((*DbaasAdapterHandler).MigrateFromVault))
 
-	database.Post("/databases/:dbName/migrate-to-vault/:userName", adapterHandler.MigrateToVault)
+	database.Post(vaultMigrationPath, audited("StartVaultMigration"), aggregatorOnly, limiters.limit(UpdateOperations), resolve.handle((*DbaasAdapterHandler).StartVaultMigration))
 
-	database.Post("/resources/bulk-drop", adapterHandler.BulkDrop)
+	database.Get(vaultMigrationPath, readers, limiters.limit(ReadOperations), resolve.handle((*DbaasAdapterHandler).GetVaultMigration))
 
-	database.Post("/describe/databases", adapterHandler.DescribeDatabases)
+	database.Post("/resources/bulk-drop", audited("DropResources"), aggregatorOnly, limiters.limit(DropOperations), resolve.handle((*DbaasAdapterHandler).BulkDrop))
 
-	database.Get("/physical_database", adapterHandler.PhysicalRegistration) //TODO check in dbaas adapter
+	database.Post("/describe/databases", readers, limiters.limit(ReadOperations), resolve.handle((*DbaasAdapterHandler).DescribeDatabases))
 
-	database.Put("/users", adapterHandler.CreateNewUser)
+	database.Get("/physical_database", readers, limiters.limit(ReadOperations), resolve.handle((*DbaasAdapterHandler).PhysicalRegistration)) //TODO check in dbaas adapter
 
-	database.Put("/users/:name", adapterHandler.CreateUser)
+	database.Put("/users", audited("CreateUser"), aggregatorOnly, limiters.limit(CreateOperations), resolve.handle((*DbaasAdapterHandler).CreateNewUser))
+
+	database.Put("/users/:name", audited("CreateUser"), aggregatorOnly, limiters.limit(CreateOperations), resolve.handle((*DbaasAdapterHandler).CreateUser))
+
+	database.Post("/databases/:dbName/users/:name/rotate", audited("RotatePassword"), aggregatorOnly, limiters.limit(UpdateOperations), resolve.handle((*DbaasAdapterHandler).RotatePassword))
+
+	database.Get("/databases/:dbName/users/:name/rotations", readers, limiters.limit(ReadOperations), resolve.handle((*DbaasAdapterHandler).GetPasswordRotations))
 
 	//Backups
 	trackBackupPath := "/track/backup/"
 	trackRestorePath := "/track/restore/"
-	backups := database.Group(backupsPath, func(c *fiber.Ctx) error {
-		if backupService == nil {
-			panic("Operation not supported")
+	backups := database.Group(backupsPath, resolve.handle(func(h *DbaasAdapterHandler, c *fiber.Ctx) error {
+		if h.backupService == nil {
+			return service.ErrNotSupported
 		}
 		return c.Next()
-	})
+	}))
 
-	backups.Post("/collect", adapterHandler.Collect)
+	backups.Post("/collect", audited("CollectBackup"), backupOperators, limiters.limit(BackupOperations), resolve.handle((*DbaasAdapterHandler).Collect))
 
-	backups.Get(trackBackupPath+":trackId", adapterHandler.TrackBackup)
+	backups.Get(trackBackupPath+":trackId", backupReaders, limiters.limit(ReadOperations), resolve.handle((*DbaasAdapterHandler).TrackBackup))
 
-	backups.Post(":backupId/restore", adapterHandler.Restore)
+	backups.Post(":backupId/restore", audited("RestoreBackup"), backupOperators, limiters.limit(RestoreOperations), resolve.handle((*DbaasAdapterHandler).Restore))
 
-	backups.Post(":backupId/restoration", adapterHandler.Restoration)
+	backups.Post(":backupId/restoration", audited("RestoreBackup"), backupOperators, limiters.limit(RestoreOperations), resolve.handle((*DbaasAdapterHandler).Restoration))
 
-	backups.Get(trackRestorePath+":trackId", adapterHandler.TrackRestore)
+	backups.Get(trackRestorePath+":trackId", backupReaders, limiters.limit(ReadOperations), resolve.handle((*DbaasAdapterHandler).TrackRestore))
 
-	backups.Delete(":backupId", adapterHandler.DeleteBackup)
+	backups.Delete(":backupId", audited("EvictBackup"), backupOperators, limiters.limit(BackupOperations), resolve.handle((*DbaasAdapterHandler).DeleteBackup))
 
 	// New backup API
-	backups.Post("/backup", adapterHandler.CollectBackupV2)
-	backups.Get("/backup/:backupId", adapterHandler.TrackBackupV2)
-	backups.Post("/backup/:backupId/restore", adapterHandler.RestoreBackupV2)
-	backups.Get("/restore/:restoreId", adapterHandler.TrackRestoreV2)
-	backups.Delete("/backup/:backupId", adapterHandler.DeleteBackupV2)
-	backups.Delete("/restore/:restoreId", adapterHandler.DeleteRestoreV2)
+	backups.Post("/backup", audited("CollectBackup"), backupOperators, limiters.limit(BackupOperations), resolve.handle((*DbaasAdapterHandler).CollectBackupV2))
+	backups.Get("/backup/:backupId", backupReaders, limiters.limit(ReadOperations), resolve.handle((*DbaasAdapterHandler).TrackBackupV2))
+	backups.Post("/backup/:backupId/restore", audited("RestoreBackup"), backupOperators, limiters.limit(RestoreOperations), resolve.handle((*DbaasAdapterHandler).RestoreBackupV2))
+	backups.Get("/restore/:restoreId", backupReaders, limiters.limit(ReadOperations), resolve.handle((*DbaasAdapterHandler).TrackRestoreV2))
+	backups.Delete("/backup/:backupId", audited("EvictBackup"), backupOperators, limiters.limit(BackupOperations), resolve.handle((*DbaasAdapterHandler).DeleteBackupV2))
+	backups.Delete("/restore/:restoreId", audited("EvictRestore"), backupOperators, limiters.limit(BackupOperations), resolve.handle((*DbaasAdapterHandler).DeleteRestoreV2))
 
-	general.Get("/physical_database/force_registration", adapterHandler.ForceRegistration)
+	general.Get(forceRegistrationPath, respondProblems, basicAuth, aggregatorOnly, resolve.handle((*DbaasAdapterHandler).ForceRegistration))
 
+	general.Get(forceRegistrationPath+"/:trackId", basicAuth, readers, resolve.handle((*DbaasAdapterHandler).TrackForceRegistration))
+}
+
+func copySupports(supports dto.Supports) dto.Supports {
+	supportCopy := make(dto.Supports)
+	for key, val := range supports {
+		supportCopy[key] = val
+	}
+	return supportCopy
+}
+
+// buildHealth reports registration health of every physical database. Overall registration health is the worst one.
+func buildHealth(handlers []*DbaasAdapterHandler) dto.Health {
 	health := dto.Health{
 		Status: "UP",
 		PhysicalDatabaseRegistration: &dto.PhysicalDatabaseRegistrationHealth{
 			Status: "UNKNOWN",
 		},
 	}
-	coreAdminService.PreStart()
-	physicalService.StartRegister()
-	health.PhysicalDatabaseRegistration = &physicalService.Health
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(&health)
-	})
+	if len(handlers) == 1 {
+		registrationHealth := handlers[0].physicalService.GetHealth()
+		health.PhysicalDatabaseRegistration = &registrationHealth
+		return health
+	}
+	health.PhysicalDatabases = make(map[string]*dto.PhysicalDatabaseRegistrationHealth, len(handlers))
+	worst := -1
+	for _, h := range handlers {
+		registrationHealth := h.physicalService.GetHealth()
+		health.PhysicalDatabases[h.id] = &registrationHealth
+		if severity := slices.Index(registrationHealthSeverity, registrationHealth.Status); severity > worst {
+			worst = severity
+			health.PhysicalDatabaseRegistration = &dto.PhysicalDatabaseRegistrationHealth{Status: registrationHealth.Status}
+		}
+	}
+	return health
 }
+
+// registrationHealthSeverity lists registration health statuses from the best to the worst
+var registrationHealthSeverity = []string{"OK", "UNKNOWN", "WARNING", "PROBLEM"}
@@ -7,13 +7,15 @@ import (
 	"strings"
 
 	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/service"
 	utilsCore "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
 
-var validate = validator.New()
+// validateBackup checks requests of v2 backup handlers, violations keep Go names of fields
+var validateBackup = validator.New()
 
 type blobPathQuery struct {
 	BlobPath string `query:"blobPath" validate:"required"`
@@ -24,7 +26,7 @@ func requireBlobPath(c *fiber.Ctx) (string, error) {
 	if err := c.QueryParser(&q); err != nil {
 		return "", fiber.NewError(fiber.StatusBadRequest, "invalid query")
 	}
-	if err := validate.Struct(q); err != nil {
+	if err := validateBackup.Struct(q); err != nil {
 		return "", fiber.NewError(fiber.StatusBadRequest, "query parameter 'blobPath' is required")
 	}
 	return strings.TrimSpace(q.BlobPath), nil
@@ -40,11 +42,11 @@ func requireBlobPath(c *fiber.Ctx) (string, error) {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param body body dto.CreateBackupRequest true "Backup configuration details"
 // @Success 202 {object} dto.BackupResponse "Backup operation accepted and is being processed"
-// @Failure 400 {object} dto.BadRequestResponse "The request was invalid or cannot be served"
-// @Failure 401 {string} string "Authentication is required and has failed or has not been provided"
-// @Failure 403 {string} string "The request was valid, but the server is refusing action"
-// @Failure 404 {string} string "The requested resource could not be found"
-// @Failure 500 {object} dto.ServerErrorResponse "An unexpected error occurred on the server"
+// @Failure 400 {object} dto.Problem "The request was invalid or cannot be served"
+// @Failure 401 {object} dto.Problem "Authentication is required and has failed or has not been provided"
+// @Failure 403 {object} dto.Problem "The request was valid, but the server is refusing action"
+// @Failure 404 {object} dto.Problem "The requested resource could not be found"
+// @Failure 500 {object} dto.Problem "An unexpected error occurred on the server"
 // @Router /{appName}/backups [post]
 func (h *DbaasAdapterHandler) CollectBackupV2(c *fiber.Ctx) error {
 	ctx := getRequestContext(c)
@@ -54,16 +56,13 @@ func (h *DbaasAdapterHandler) CollectBackupV2(c *fiber.Ctx) error {
 	var backupRequest dto.CreateBackupRequest
 	if err := c.BodyParser(&backupRequest); err != nil {
 		h.logger.Error("Failed to parse backup request", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(dto.BadRequestResponse{
-			Error:   "Invalid request parameters",
-			Details: []string{err.Error()},
-		})
+		return service.WrapError(err, service.InvalidArgument, dto.InvalidRequestCode)
 	}
 
-	err := validate.Struct(backupRequest)
+	err := validateBackup.Struct(backupRequest)
 	if err != nil {
 		h.logger.Error("Failed to validate backup request", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(h.handleValidationErrors(err))
+		return err
 	}
 
 	h.logger.Debug(fmt.Sprintf("Backup request: %+v", backupRequest))
@@ -73,12 +72,13 @@ func (h *DbaasAdapterHandler) CollectBackupV2(c *fiber.Ctx) error {
 	for _, db := range backupRequest.Databases {
 		databaseNames = append(databaseNames, db.DatabaseName)
 	}
+	setAuditResources(c, databaseResourceNames(databaseNames)...)
 
 	// Call the service to create backup
 	backupResponse, found := h.backupService.CollectBackupV2(ctx, backupRequest.StorageName, backupRequest.BlobPath, databaseNames)
 	if !found {
 		h.logger.Info("Database not found")
-		return c.Status(fiber.StatusNotFound).SendString("Database not found")
+		return fiber.NewError(fiber.StatusNotFound, "Database not found")
 	}
 
 	h.logger.Debug(fmt.Sprintf("Backup response: %+v", backupResponse))
@@ -94,8 +94,8 @@ func (h *DbaasAdapterHandler) CollectBackupV2(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param backupId path string true "Unique identifier of the backup operation" Format(uuid)
 // @Success 200 {object} dto.BackupResponse "Backup details retrieved successfully"
-// @Failure 404 {string} string "The requested resource could not be found"
-// @Failure 500 {object} dto.ServerErrorResponse "An unexpected error occurred on the server"
+// @Failure 404 {object} dto.Problem "The requested resource could not be found"
+// @Failure 500 {object} dto.Problem "An unexpected error occurred on the server"
 // @Router /{appName}/backups/backup/{backupId} [get]
 func (h *DbaasAdapterHandler) TrackBackupV2(c *fiber.Ctx) error {
 	backupId := c.Params("backupId")
@@ -113,7 +113,7 @@ func (h *DbaasAdapterHandler) TrackBackupV2(c *fiber.Ctx) error {
 	backupResponse, found := h.backupService.TrackBackupV2(ctx, backupId, blobPath)
 	if !found {
 		h.logger.Info("Backup not found", zap.String("backupId", backupId))
-		return c.Status(fiber.StatusNotFound).SendString("Backup not found")
+		return fiber.NewError(fiber.StatusNotFound, "Backup not found")
 	}
 
 	h.logger.Debug(fmt.Sprintf("Backup response: %+v", backupResponse))
@@ -128,8 +128,8 @@ func (h *DbaasAdapterHandler) TrackBackupV2(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param backupId path string true "Unique identifier of the backup operation" Format(uuid)
 // @Success 204 "Backup deleted successfully"
-// @Failure 404 {string} string "The requested resource could not be found"
-// @Failure 500 {object} dto.ServerErrorResponse "An unexpected error occurred on the server"
+// @Failure 404 {object} dto.Problem "The requested resource could not be found"
+// @Failure 500 {object} dto.Problem "An unexpected error occurred on the server"
 // @Router /{appName}/backups/backup/{backupId} [delete]
 func (h *DbaasAdapterHandler) DeleteBackupV2(c *fiber.Ctx) error {
 	backupId := c.Params("backupId")
@@ -146,7 +146,7 @@ func (h *DbaasAdapterHandler) DeleteBackupV2(c *fiber.Ctx) error {
 	found := h.backupService.EvictBackupV2(ctx, backupId, blobPath)
 	if !found {
 		h.logger.Info("Backup not found", zap.String("backupId", backupId))
-		return c.Status(fiber.StatusNotFound).SendString("Backup not found")
+		return fiber.NewError(fiber.StatusNotFound, "Backup not found")
 	}
 
 	h.logger.Debug(fmt.Sprintf("Backup deleted successfully: %s", backupId))
@@ -165,9 +165,9 @@ func (h *DbaasAdapterHandler) DeleteBackupV2(c *fiber.Ctx) error {
 // @Param dryRun query bool false "If true, validates the restore operation without making any changes" default(false)
 // @Param body body dto.CreateRestoreRequest true "Restore configuration details"
 // @Success 202 {object} dto.RestoreResponse "Restore operation accepted and is being processed"
-// @Failure 400 {object} dto.BadRequestResponse "The request was invalid or cannot be served"
-// @Failure 404 {string} string "The requested resource could not be found"
-// @Failure 500 {object} dto.ServerErrorResponse "An unexpected error occurred on the server"
+// @Failure 400 {object} dto.Problem "The request was invalid or cannot be served"
+// @Failure 404 {object} dto.Problem "The requested resource could not be found"
+// @Failure 500 {object} dto.Problem "An unexpected error occurred on the server"
 // @Router /{appName}/backups/backup/{backupId}/restore [post]
 func (h *DbaasAdapterHandler) RestoreBackupV2(c *fiber.Ctx) error {
 	backupId := c.Params("backupId")
@@ -179,17 +179,14 @@ func (h *DbaasAdapterHandler) RestoreBackupV2(c *fiber.Ctx) error {
 	var restoreRequest dto.CreateRestoreRequest
 	if err := c.BodyParser(&restoreRequest); err != nil {
 		h.logger.Error("Failed to parse restore request", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(dto.BadRequestResponse{
-			Error:   "Invalid request parameters",
-			Details: []string{err.Error()},
-		})
+		return service.WrapError(err, service.InvalidArgument, dto.InvalidRequestCode)
 	}
 
 	// Validate the restore request
-	err := validate.Struct(restoreRequest)
+	err := validateBackup.Struct(restoreRequest)
 	if err != nil {
 		h.logger.Error("Failed to validate restore request", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(h.handleValidationErrors(err))
+		return err
 	}
 
 	h.logger.Debug(fmt.Sprintf("Restore request: %+v, dryRun: %v", restoreRequest, dryRun))
@@ -197,7 +194,7 @@ func (h *DbaasAdapterHandler) RestoreBackupV2(c *fiber.Ctx) error {
 	restoreResponse, found := h.backupService.RestoreBackupV2(ctx, backupId, restoreRequest, dryRun)
 	if !found {
 		h.logger.Info("Backup not found", zap.String("backupId", backupId))
-		return c.Status(fiber.StatusNotFound).SendString("Backup not found")
+		return fiber.NewError(fiber.StatusNotFound, "Backup not found")
 	}
 
 	h.logger.Debug(fmt.Sprintf("Restore response: %+v", restoreResponse))
@@ -213,8 +210,8 @@ func (h *DbaasAdapterHandler) RestoreBackupV2(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param restoreId path string true "Unique identifier of the restore operation" Format(uuid)
 // @Success 200 {object} dto.RestoreResponse "Restore details retrieved successfully"
-// @Failure 404 {string} string "The requested resource could not be found"
-// @Failure 500 {object} dto.ServerErrorResponse "An unexpected error occurred on the server"
+// @Failure 404 {object} dto.Problem "The requested resource could not be found"
+// @Failure 500 {object} dto.Problem "An unexpected error occurred on the server"
 // @Router /{appName}/backups/restore/{restoreId} [get]
 func (h *DbaasAdapterHandler) TrackRestoreV2(c *fiber.Ctx) error {
 	restoreId := c.Params("restoreId")
@@ -231,7 +228,7 @@ func (h *DbaasAdapterHandler) TrackRestoreV2(c *fiber.Ctx) error {
 	restoreResponse, found := h.backupService.TrackRestoreV2(ctx, restoreId, blobPath)
 	if !found {
 		h.logger.Info("Restore not found", zap.String("restoreId", restoreId))
-		return c.Status(fiber.StatusNotFound).SendString("Restore not found")
+		return fiber.NewError(fiber.StatusNotFound, "Restore not found")
 	}
 
 	h.logger.Debug(fmt.Sprintf("Restore response: %+v", restoreResponse))
@@ -246,8 +243,8 @@ func (h *DbaasAdapterHandler) TrackRestoreV2(c *fiber.Ctx) error {
 // @Param apiVersion path string true "API version of dbaas adapter" Enums(v1, v2) default(v2)
 // @Param restoreId path string true "Unique identifier of the restore operation" Format(uuid)
 // @Success 204 "Restore deleted successfully"
-// @Failure 404 {string} string "The requested resource could not be found"
-// @Failure 500 {object} dto.ServerErrorResponse "An unexpected error occurred on the server"
+// @Failure 404 {object} dto.Problem "The requested resource could not be found"
+// @Failure 500 {object} dto.Problem "An unexpected error occurred on the server"
 // @Router /{appName}/backups/restore/{restoreId} [delete]
 func (h *DbaasAdapterHandler) DeleteRestoreV2(c *fiber.Ctx) error {
 	restoreId := c.Params("restoreId")
@@ -264,7 +261,7 @@ func (h *DbaasAdapterHandler) DeleteRestoreV2(c *fiber.Ctx) error {
 	found := h.backupService.EvictRestoreV2(ctx, restoreId, blobPath)
 	if !found {
 		h.logger.Info("Restore not found", zap.String("restoreId", restoreId))
-		return c.Status(fiber.StatusNotFound).SendString("Restore not found")
+		return fiber.NewError(fiber.StatusNotFound, "Restore not found")
 	}
 
 	h.logger.Debug(fmt.Sprintf("Restore deleted successfully: %s", restoreId))
@@ -280,36 +277,6 @@ func (h *DbaasAdapterHandler) handlePanicRecovery(c *fiber.Ctx, ctx context.Cont
 			zap.Any("panic", r),
 			zap.Stack("stack"))
 
-		requestId := string(ctx.Value("request_id").([]byte))
-		c.Status(fiber.StatusInternalServerError).JSON(dto.ServerErrorResponse{
-			Error:     "Internal server error",
-			RequestId: requestId,
-		})
-	}
-}
-
-// handleValidationErrors extracts and formats validation errors
-func (h *DbaasAdapterHandler) handleValidationErrors(err error) dto.BadRequestResponse {
-	var validationErrors []string
-	if validationErrs, ok := err.(validator.ValidationErrors); ok {
-		for _, validationErr := range validationErrs {
-			// Get the field name with namespace for nested structs
-			fieldName := validationErr.Namespace()
-			if fieldName == "" {
-				fieldName = validationErr.Field()
-			}
-
-			// Create a more descriptive error message
-			errorMsg := fmt.Sprintf("Field '%s' failed validation: %s", fieldName, validationErr.Tag())
-
-			validationErrors = append(validationErrors, errorMsg)
-		}
-	} else {
-		validationErrors = []string{err.Error()}
-	}
-
-	return dto.BadRequestResponse{
-		Error:   "Invalid request parameters",
-		Details: validationErrors,
+		_ = problemErrorHandler(c, fiber.NewError(fiber.StatusInternalServerError, "Internal server error"))
 	}
 }
@@ -0,0 +1,245 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// OperationClass groups adapter API routes limited together
+type OperationClass string
+
+const (
+	// CreateOperations create databases and users
+	CreateOperations = OperationClass("create")
+	// UpdateOperations change metadata, rotate passwords and migrate users to and from vault
+	UpdateOperations = OperationClass("update")
+	// DropOperations drop databases and users
+	DropOperations = OperationClass("drop")
+	// BackupOperations collect and evict backups
+	BackupOperations = OperationClass("backup")
+	// RestoreOperations restore backups
+	RestoreOperations = OperationClass("restore")
+	// ReadOperations list and describe databases and track operations
+	ReadOperations = OperationClass("read")
+)
+
+var OperationClasses = []OperationClass{CreateOperations, UpdateOperations, DropOperations, BackupOperations, RestoreOperations, ReadOperations}
+
+// defaultQueueTimeout is the time requests wait for the limit if it is not set by WithOperationLimits
+const defaultQueueTimeout = 30 * time.Second
+
+// OperationLimit limits requests of operation class
+type OperationLimit struct {
+	// Concurrency is the number of requests processed at the same time, it is unlimited if it is 0
+	Concurrency int
+	// Rate is the number of requests started per second, it is unlimited if it is 0
+	Rate float64
+}
+
+type operationLimits struct {
+	limits       map[OperationClass]OperationLimit
+	queueTimeout time.Duration
+}
+
+// WithOperationLimits limits concurrency and rate of adapter API requests by operation class.
+// Requests over the limit wait up to queueTimeout and are rejected with 429 and Retry-After header then.
+func WithOperationLimits(limits map[OperationClass]OperationLimit, queueTimeout time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		o.operationLimits = &operationLimits{limits: limits, queueTimeout: queueTimeout}
+	}
+}
+
+// ParseOperationLimits parses configuration of limits, e.g. concurrency "create=10,restore=2" and rate "create=5,backup=0.5"
+func ParseOperationLimits(concurrency, rates map[string]string) (map[OperationClass]OperationLimit, error) {
+	limits := make(map[OperationClass]OperationLimit)
+	for class, value := range concurrency {
+		if !slices.Contains(OperationClasses, OperationClass(class)) {
+			return nil, fmt.Errorf("unknown operation class %q, it must be one of %v", class, OperationClasses)
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("concurrency limit of %s operations must be non-negative integer: %q", class, value)
+		}
+		limit := limits[OperationClass(class)]
+		limit.Concurrency = parsed
+		limits[OperationClass(class)] = limit
+	}
+	for class, value := range rates {
+		if !slices.Contains(OperationClasses, OperationClass(class)) {
+			return nil, fmt.Errorf("unknown operation class %q, it must be one of %v", class, OperationClasses)
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("rate limit of %s operations must be non-negative number: %q", class, value)
+		}
+		limit := limits[OperationClass(class)]
+		limit.Rate = parsed
+		limits[OperationClass(class)] = limit
+	}
+	return limits, nil
+}
+
+// limitMetrics export usage of limits by operation class
+type limitMetrics struct {
+	inFlight *prometheus.GaugeVec
+	queued   *prometheus.GaugeVec
+	rejected *prometheus.CounterVec
+	waited   *prometheus.HistogramVec
+}
+
+func newLimitMetrics(registerer prometheus.Registerer, serviceName string) *limitMetrics {
+	constLabels := prometheus.Labels{"service": serviceName}
+	metrics := &limitMetrics{
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   "dbaas_adapter",
+			Name:        "operations_in_flight",
+			Help:        "Number of adapter API requests being processed by operation class.",
+			ConstLabels: constLabels,
+		}, []string{"class"}),
+		queued: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   "dbaas_adapter",
+			Name:        "operations_queued",
+			Help:        "Number of adapter API requests waiting for concurrency or rate limit by operation class.",
+			ConstLabels: constLabels,
+		}, []string{"class"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "dbaas_adapter",
+			Name:        "operations_rejected_total",
+			Help:        "Number of adapter API requests rejected with 429 by operation class and exceeded limit.",
+			ConstLabels: constLabels,
+		}, []string{"class", "limit"}),
+		waited: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   "dbaas_adapter",
+			Name:        "operation_queue_duration_seconds",
+			Help:        "Time adapter API requests waited for limits by operation class.",
+			ConstLabels: constLabels,
+			Buckets:     prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"class"}),
+	}
+	registerer.MustRegister(metrics.inFlight, metrics.queued, metrics.rejected, metrics.waited)
+	return metrics
+}
+
+// operationLimiter limits requests of one operation class
+type operationLimiter struct {
+	class        string
+	rate         *rate.Limiter
+	slots        chan struct{}
+	queueTimeout time.Duration
+	metrics      *limitMetrics
+}
+
+// operationLimiters returns middleware of operation class
+type operationLimiters map[OperationClass]*operationLimiter
+
+// newOperationLimiters creates limiters of all operation classes, classes without limits only export usage
+func newOperationLimiters(config *operationLimits, registerer prometheus.Registerer, serviceName string) operationLimiters {
+	if config == nil {
+		config = &operationLimits{queueTimeout: defaultQueueTimeout}
+	}
+	metrics := newLimitMetrics(registerer, serviceName)
+	limiters := make(operationLimiters, len(OperationClasses))
+	for _, class := range OperationClasses {
+		limit := config.limits[class]
+		limiter := &operationLimiter{class: string(class), queueTimeout: config.queueTimeout, metrics: metrics}
+		if limit.Rate > 0 {
+			limiter.rate = rate.NewLimiter(rate.Limit(limit.Rate), int(math.Max(1, math.Ceil(limit.Rate))))
+		}
+		if limit.Concurrency > 0 {
+			limiter.slots = make(chan struct{}, limit.Concurrency)
+		}
+		limiters[class] = limiter
+	}
+	return limiters
+}
+
+func (l operationLimiters) limit(class OperationClass) fiber.Handler {
+	return l[class].middleware
+}
+
+// middleware waits for free slot and then for rate limit, so requests rejected by concurrency limit
+// do not take rate of the class
+func (l *operationLimiter) middleware(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), l.queueTimeout)
+	defer cancel()
+	start := time.Now()
+	queued := l.metrics.queued.WithLabelValues(l.class)
+	queued.Inc()
+
+	if l.slots != nil {
+		if !l.acquire(ctx) {
+			queued.Dec()
+			return l.reject(c, "concurrency", l.queueTimeout)
+		}
+		defer func() { <-l.slots }()
+	}
+	if l.rate != nil {
+		reservation := l.rate.Reserve()
+		if delay := reservation.Delay(); delay > 0 && delay > l.queueTimeout-time.Since(start) {
+			reservation.Cancel()
+			queued.Dec()
+			return l.reject(c, "rate", delay)
+		} else if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				reservation.Cancel()
+				queued.Dec()
+				return l.reject(c, "rate", delay-time.Since(start))
+			}
+		}
+	}
+	queued.Dec()
+	l.metrics.waited.WithLabelValues(l.class).Observe(time.Since(start).Seconds())
+
+	inFlight := l.metrics.inFlight.WithLabelValues(l.class)
+	inFlight.Inc()
+	defer inFlight.Dec()
+	return c.Next()
+}
+
+// acquire takes free slot or waits for it until ctx is done
+func (l *operationLimiter) acquire(ctx context.Context) bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// reject responds with 429, Retry-After is the time the limit is expected to allow the request
+func (l *operationLimiter) reject(c *fiber.Ctx, limit string, retryAfter time.Duration) error {
+	l.metrics.rejected.WithLabelValues(l.class, limit).Inc()
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds())))))
+	return fiber.NewError(fiber.StatusTooManyRequests, fmt.Sprintf("Too many %s operations, %s limit is exceeded", l.class, limit))
+}
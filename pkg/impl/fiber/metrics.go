@@ -0,0 +1,60 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"github.com/ansrivas/fiberprometheus/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// WithMetricsRegistry sets registerer of the app metrics and gatherer serving them at /metrics, e.g. both are
+// the same *prometheus.Registry. By default every app has its own registry, so several apps in one process
+// do not conflict. Shared registry requires different metrics service names of the apps.
+func WithMetricsRegistry(registerer prometheus.Registerer, gatherer prometheus.Gatherer) ServerOption {
+	return func(o *serverOptions) {
+		o.metricsRegisterer = registerer
+		o.metricsGatherer = gatherer
+	}
+}
+
+// WithProtectedMetrics requires read-only role for /metrics and /swagger, they do not require authentication
+// by default, so Prometheus scrapes them without credentials
+func WithProtectedMetrics(protected bool) ServerOption {
+	return func(o *serverOptions) { o.protectMetrics = protected }
+}
+
+// MetricsRegisterer returns registerer of the app metrics, adapters can register their own metrics in it
+// to serve them on /metrics endpoint of the app
+func (s *AdapterServer) MetricsRegisterer() prometheus.Registerer {
+	return s.metricsRegisterer
+}
+
+// registerMetrics installs HTTP metrics middleware to the app and serves metrics of the app gatherer
+// together with process wide metrics of prometheus.DefaultGatherer at /metrics of the management app
+// behind the auth handlers
+func (s *AdapterServer) registerMetrics(serviceName string, auth ...fiber.Handler) {
+	httpMetrics := fiberprometheus.NewWithRegistry(s.metricsRegisterer, serviceName, "", "", nil)
+	s.app.Use(httpMetrics.Middleware)
+
+	gatherers := prometheus.Gatherers{prometheus.DefaultGatherer}
+	if s.metricsGatherer != nil && s.metricsGatherer != prometheus.DefaultGatherer {
+		gatherers = append(gatherers, s.metricsGatherer)
+	}
+	handlers := append(auth, adaptor.HTTPHandler(promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{})))
+	s.management.Get("/metrics", handlers...)
+}
@@ -0,0 +1,186 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/service"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const requestIdHeader = "X-Request-ID"
+
+// statusOfKinds are response statuses of typed service errors
+var statusOfKinds = map[service.ErrorKind]int{
+	service.InternalError:   fiber.StatusInternalServerError,
+	service.InvalidArgument: fiber.StatusBadRequest,
+	service.NotFound:        fiber.StatusNotFound,
+	service.Conflict:        fiber.StatusConflict,
+	service.NotSupported:    fiber.StatusNotImplemented,
+	service.Unavailable:     fiber.StatusServiceUnavailable,
+}
+
+// codeOfStatuses are error codes of errors without code, e.g. fiber.Error
+var codeOfStatuses = map[int]dto.ErrorCode{
+	fiber.StatusBadRequest:            dto.InvalidRequestCode,
+	fiber.StatusUnauthorized:          dto.UnauthorizedCode,
+	fiber.StatusForbidden:             dto.ForbiddenCode,
+	fiber.StatusNotFound:              dto.NotFoundCode,
+	fiber.StatusMethodNotAllowed:      dto.MethodNotAllowedCode,
+	fiber.StatusConflict:              dto.ConflictCode,
+	fiber.StatusRequestEntityTooLarge: dto.PayloadTooLargeCode,
+	fiber.StatusUnprocessableEntity:   dto.InvalidRequestCode,
+	fiber.StatusUnsupportedMediaType:  dto.InvalidRequestCode,
+	fiber.StatusTooManyRequests:       dto.TooManyRequestsCode,
+	fiber.StatusNotImplemented:        dto.NotSupportedCode,
+	fiber.StatusServiceUnavailable:    dto.UnavailableCode,
+	fiber.StatusGatewayTimeout:        dto.TimeoutCode,
+}
+
+// retryableStatuses are statuses of errors which may disappear without changes of the request
+var retryableStatuses = map[int]bool{
+	fiber.StatusTooManyRequests:    true,
+	fiber.StatusBadGateway:         true,
+	fiber.StatusServiceUnavailable: true,
+	fiber.StatusGatewayTimeout:     true,
+}
+
+// requestId returns X-Request-ID of the request, the id is generated and responded if the request has no id
+func requestId(c *fiber.Ctx) string {
+	if id := c.Get(requestIdHeader); id != "" {
+		return id
+	}
+	if id := c.GetRespHeader(requestIdHeader); id != "" {
+		return id
+	}
+	id := uuid.New().String()
+	c.Set(requestIdHeader, id)
+	return id
+}
+
+// problemOf describes the error as problem details, errors of services are classified by kinds and codes,
+// other errors are internal errors
+func problemOf(err error) dto.Problem {
+	problem := dto.Problem{Status: fiber.StatusInternalServerError, Detail: err.Error()}
+	var serviceErr *service.Error
+	var fiberErr *fiber.Error
+	var validationErrs validator.ValidationErrors
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var invalidArgumentErr *dto.InvalidArgumentError
+	var alreadyExistsErr *dto.ResourceAlreadyExistsError
+	var onlySpecifiedErr *dto.BackupRestoresOnlySpecifiedDBsError
+	var dropErr *dropFailedError
+	switch {
+	case errors.As(err, &serviceErr):
+		problem.Status = statusOfKinds[serviceErr.Kind]
+		if problem.Status == 0 {
+			problem.Status = fiber.StatusInternalServerError
+		}
+		problem.Code = serviceErr.Code
+		problem.Retryable = serviceErr.Retryable
+	case errors.As(err, &fiberErr):
+		problem.Status = fiberErr.Code
+	case errors.As(err, &validationErrs):
+		problem.Status = fiber.StatusBadRequest
+		problem.Code = dto.ValidationFailedCode
+		problem.Detail = "Invalid request parameters"
+		problem.Errors = validationMessages(validationErrs)
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		problem.Status = fiber.StatusBadRequest
+		problem.Detail = fmt.Sprintf("Invalid request body: %v", err)
+	case errors.As(err, &invalidArgumentErr):
+		problem.Status = fiber.StatusBadRequest
+	case errors.As(err, &alreadyExistsErr):
+		problem.Status = fiber.StatusConflict
+	case errors.As(err, &onlySpecifiedErr):
+		problem.Status = fiber.StatusNotImplemented
+		problem.Code = dto.RestoreRequiresDatabasesCode
+	case errors.As(err, &dropErr):
+		problem.Code = dto.DropFailedCode
+		problem.Resources = dropErr.resources
+	case errors.Is(err, context.DeadlineExceeded):
+		problem.Status = fiber.StatusGatewayTimeout
+	}
+	if problem.Code == "" {
+		problem.Code = codeOfStatuses[problem.Status]
+		if problem.Code == "" {
+			problem.Code = dto.InternalErrorCode
+		}
+	}
+	problem.Retryable = problem.Retryable || retryableStatuses[problem.Status]
+	problem.Type = dto.ProblemTypePrefix + string(problem.Code)
+	problem.Title = http.StatusText(problem.Status)
+	return problem
+}
+
+// validationMessages describes failed validations of request fields
+func validationMessages(validationErrs validator.ValidationErrors) []string {
+	messages := make([]string, 0, len(validationErrs))
+	for _, validationErr := range validationErrs {
+		// namespace includes names of nested structs and indexes of elements, name of the body type is omitted
+		fieldName := validationErr.Namespace()
+		if _, nested, found := strings.Cut(fieldName, "."); found && !strings.HasPrefix(fieldName, "[") {
+			fieldName = nested
+		}
+		if fieldName == "" {
+			fieldName = validationErr.Field()
+		}
+		rule := validationErr.Tag()
+		if validationErr.Param() != "" {
+			rule += "=" + validationErr.Param()
+		}
+		messages = append(messages, fmt.Sprintf("Field '%s' failed validation: %s", fieldName, rule))
+	}
+	return messages
+}
+
+// problemErrorHandler responds with problem details of errors returned by handlers and of recovered panics.
+// Secrets are masked in details, they may contain raw responses of DBaaS aggregator, backup daemon or database.
+func problemErrorHandler(c *fiber.Ctx, err error) error {
+	problem := problemOf(err)
+	problem.Detail = utils.RedactSecrets(problem.Detail)
+	problem.Instance = c.Path()
+	problem.RequestId = requestId(c)
+	return c.Status(problem.Status).JSON(problem, dto.ProblemContentType)
+}
+
+// respondProblems responds with problem details of errors of the following handlers, so adapter API responds
+// with problem details in apps created by adapters without problemErrorHandler too
+func respondProblems(c *fiber.Ctx) error {
+	if err := c.Next(); err != nil {
+		return problemErrorHandler(c, err)
+	}
+	return nil
+}
+
+// dropFailedError keeps results of resources drop, they are responded in problem details
+type dropFailedError struct {
+	resources []dto.DbResource
+}
+
+func (e *dropFailedError) Error() string {
+	return "Some resources are not dropped"
+}
@@ -0,0 +1,124 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// roleOf returns role of authenticated user. Aggregator credentials of basic auth have AggregatorRole,
+// users authenticated by bearer tokens have roles of their principals only.
+func (c *reloadableCredentials) roleOf(user string, byToken bool) (dto.ApiRole, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if !byToken && user == c.current.Username {
+		return dto.AggregatorRole, true
+	}
+	// principal may take username of previous credentials, it is not authorized by them then, see authorize
+	if principal, found := c.principals[user]; found {
+		return principal.Role, true
+	}
+	if !byToken && c.previous != nil && user == c.previous.Username {
+		return dto.AggregatorRole, true
+	}
+	return "", false
+}
+
+// allow returns middleware which rejects authenticated users without AggregatorRole or one of the roles with 403.
+// It must follow basicAuth middleware.
+func (c *reloadableCredentials) allow(roles ...dto.ApiRole) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		user, _ := ctx.Locals("username").(string)
+		byToken, _ := ctx.Locals(tokenAuthenticatedKey).(bool)
+		role, found := c.roleOf(user, byToken)
+		if !found || (role != dto.AggregatorRole && !slices.Contains(roles, role)) {
+			return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("User %s is not allowed to %s %s", user, ctx.Method(), ctx.Path()))
+		}
+		return ctx.Next()
+	}
+}
+
+// UpdateApiPrincipals replaces additional users of adapter API served by the app. Aggregator credentials
+// are not changed, they are updated by UpdateApiCredentials.
+func (s *AdapterServer) UpdateApiPrincipals(principals []dto.ApiPrincipal) error {
+	credentials, err := s.apiCredentials()
+	if err != nil {
+		return err
+	}
+	byUsername := make(map[string]dto.ApiPrincipal, len(principals))
+	credentials.mutex.Lock()
+	defer credentials.mutex.Unlock()
+	for _, principal := range principals {
+		if err = validatePrincipal(principal); err != nil {
+			return err
+		}
+		if _, found := byUsername[principal.Username]; found || principal.Username == credentials.current.Username {
+			return fmt.Errorf("username %s of adapter API principal is not unique", principal.Username)
+		}
+		byUsername[principal.Username] = principal
+	}
+	credentials.principals = byUsername
+	return nil
+}
+
+func validatePrincipal(principal dto.ApiPrincipal) error {
+	if principal.Username == "" {
+		return fmt.Errorf("username of adapter API principal is empty")
+	}
+	if !slices.Contains(dto.ApiRoles, principal.Role) {
+		return fmt.Errorf("role %q of adapter API principal %s is not one of %v", principal.Role, principal.Username, dto.ApiRoles)
+	}
+	return nil
+}
+
+// ReadApiPrincipals reads YAML or JSON list of principals with username, password and role, e.g. mounted secret.
+// Principals without password are authenticated by bearer tokens only, see WithTokenAuthenticator.
+func ReadApiPrincipals(fileName string) ([]dto.ApiPrincipal, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read adapter API principals: %w", err)
+	}
+	var principals []dto.ApiPrincipal
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&principals); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("cannot parse adapter API principals %s: %w", fileName, err)
+	}
+	return principals, nil
+}
+
+// WatchApiPrincipals updates principals of adapter API from the file until ctx is done, see UpdateApiPrincipals
+func (s *AdapterServer) WatchApiPrincipals(ctx context.Context, fileName string, interval time.Duration, logger *zap.Logger) {
+	utils.WatchFiles(ctx, interval, logger, func() error {
+		principals, err := ReadApiPrincipals(fileName)
+		if err != nil {
+			return err
+		}
+		return s.UpdateApiPrincipals(principals)
+	}, fileName)
+}
@@ -0,0 +1,165 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/auth"
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/service"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/basicauth"
+	"go.uber.org/zap"
+)
+
+// previousCredentialsGracePeriod is the time previous API credentials are accepted after update,
+// so requests of aggregator are not rejected until it receives new credentials by registration
+const previousCredentialsGracePeriod = 5 * time.Minute
+
+type reloadableCredentials struct {
+	mutex         sync.RWMutex
+	current       dto.BasicAuth
+	previous      *dto.BasicAuth
+	previousUntil time.Time
+	// principals are additional users of adapter API by username, see UpdateApiPrincipals
+	principals map[string]dto.ApiPrincipal
+	// tokens authenticates bearer tokens if it is set, see WithTokenAuthenticator
+	tokens auth.TokenAuthenticator
+	// physicalServices send new credentials to aggregator
+	physicalServices []*service.PhysicalDatabaseRegistrationService
+}
+
+// newApiCredentials creates credentials of adapter API of the app,
+// they can be replaced by UpdateApiCredentials and UpdateApiPrincipals
+func newApiCredentials(user, pass string, tokens auth.TokenAuthenticator, physicalServices []*service.PhysicalDatabaseRegistrationService) *reloadableCredentials {
+	return &reloadableCredentials{
+		current:          dto.BasicAuth{Username: user, Password: pass},
+		tokens:           tokens,
+		physicalServices: physicalServices,
+	}
+}
+
+func (s *AdapterServer) apiCredentials() (*reloadableCredentials, error) {
+	if s.credentials == nil {
+		return nil, fmt.Errorf("adapter API is not registered in the app")
+	}
+	return s.credentials, nil
+}
+
+// basicAuth returns authentication middleware accepting aggregator credentials and all the principals
+// with passwords, principals are accepted with bearer tokens too if token authenticator is set
+func (c *reloadableCredentials) basicAuth() fiber.Handler {
+	const realm = "dbaas adapter API"
+	basicAuth := basicauth.New(basicauth.Config{
+		Realm:      realm,
+		Authorizer: c.authorize,
+		// rejection is responded with problem details like other errors
+		Unauthorized: func(ctx *fiber.Ctx) error {
+			ctx.Set(fiber.HeaderWWWAuthenticate, "basic realm="+realm)
+			return fiber.ErrUnauthorized
+		},
+	})
+	if c.tokens == nil {
+		return basicAuth
+	}
+	return func(ctx *fiber.Ctx) error {
+		if token, found := bearerToken(ctx); found {
+			return c.authenticateToken(ctx, token)
+		}
+		return basicAuth(ctx)
+	}
+}
+
+func (c *reloadableCredentials) authorize(user, pass string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if matchCredentials(c.current, user, pass) {
+		return true
+	}
+	if principal, found := c.principals[user]; found {
+		// principals without password are authenticated by bearer tokens only
+		return principal.Password != "" &&
+			matchCredentials(dto.BasicAuth{Username: principal.Username, Password: principal.Password}, user, pass)
+	}
+	return c.previous != nil && time.Now().Before(c.previousUntil) && matchCredentials(*c.previous, user, pass)
+}
+
+func matchCredentials(credentials dto.BasicAuth, user, pass string) bool {
+	userMatch := subtle.ConstantTimeCompare([]byte(credentials.Username), []byte(user)) == 1
+	passMatch := subtle.ConstantTimeCompare([]byte(credentials.Password), []byte(pass)) == 1
+	return userMatch && passMatch
+}
+
+// UpdateApiCredentials replaces basic auth credentials of adapter API served by the app and registers
+// physical databases with new credentials. Previous credentials are accepted for a few minutes after update.
+func (s *AdapterServer) UpdateApiCredentials(user, pass string) error {
+	credentials, err := s.apiCredentials()
+	if err != nil {
+		return err
+	}
+
+	updated := dto.BasicAuth{Username: user, Password: pass}
+	credentials.mutex.Lock()
+	if credentials.current == updated {
+		credentials.mutex.Unlock()
+		return nil
+	}
+	if _, found := credentials.principals[user]; found {
+		credentials.mutex.Unlock()
+		return fmt.Errorf("username %s of adapter API credentials is used by principal", user)
+	}
+	previous := credentials.current
+	credentials.previous = &previous
+	credentials.previousUntil = time.Now().Add(previousCredentialsGracePeriod)
+	credentials.current = updated
+	credentials.mutex.Unlock()
+
+	for _, physicalService := range credentials.physicalServices {
+		physicalService.UpdateAdapterCredentials(updated)
+	}
+	return nil
+}
+
+// WatchApiCredentials updates credentials of adapter API from mounted secret directory with
+// username and password files until ctx is done, see UpdateApiCredentials
+func (s *AdapterServer) WatchApiCredentials(ctx context.Context, dir string, interval time.Duration, logger *zap.Logger) {
+	utils.WatchCredentials(ctx, dir, interval, logger, func(username, password string) {
+		if err := s.UpdateApiCredentials(username, password); err != nil {
+			logger.Warn(fmt.Sprintf("Cannot update adapter API credentials: %v", err))
+		}
+	})
+}
+
+func getLogLevel(c *fiber.Ctx) error {
+	return c.JSON(dto.LogLevel{Level: utils.GetLogLevel()})
+}
+
+func setLogLevel(c *fiber.Ctx) error {
+	var request dto.LogLevel
+	if err := json.Unmarshal(c.Body(), &request); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := utils.SetLogLevel(request.Level); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return c.JSON(dto.LogLevel{Level: utils.GetLogLevel()})
+}
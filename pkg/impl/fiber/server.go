@@ -16,33 +16,214 @@ package fiber
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"os"
+	"os/signal"
 	"strconv"
+	"sync"
+	"syscall"
 
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
 	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-func GetFiberServer(setUp func(app *fiber.App, ctx context.Context) error) (context.CancelFunc, *fiber.App, error) {
+// ShutdownHook is called on graceful shutdown before fiber app stops listening.
+// Hook should return when its work is done or ctx is done.
+type ShutdownHook func(ctx context.Context)
+
+// AdapterServer keeps settings and state of one adapter app: options set by ServerOption, management app,
+// API credentials, in-flight requests and shutdown hooks. Apps do not share the state, so several adapter apps
+// and tests may run in one process.
+type AdapterServer struct {
+	app        *fiber.App
+	management *fiber.App
+	options    *serverOptions
+
+	metricsRegisterer prometheus.Registerer
+	metricsGatherer   prometheus.Gatherer
+	// credentials and limiters are created when adapter API is registered
+	credentials *reloadableCredentials
+	limiters    operationLimiters
+	requests    requestTracker
+
+	hooksMutex sync.Mutex
+	hooks      []ShutdownHook
+}
+
+// NewAdapterServer creates state of the app configured by options. It is needed by adapters creating fiber app
+// themselves, GetFiberServer creates the state of its app and passes it to setUp, see ServerFromContext.
+// Options of listeners are used by RunFiberServer only.
+func NewAdapterServer(app *fiber.App, opts ...ServerOption) *AdapterServer {
+	return newAdapterServer(app, newServerOptions(opts))
+}
+
+func newAdapterServer(app *fiber.App, options *serverOptions) *AdapterServer {
+	server := &AdapterServer{
+		app:               app,
+		management:        app,
+		options:           options,
+		metricsRegisterer: options.metricsRegisterer,
+		metricsGatherer:   options.metricsGatherer,
+	}
+	if server.metricsRegisterer == nil {
+		registry := prometheus.NewRegistry()
+		server.metricsRegisterer, server.metricsGatherer = registry, registry
+	}
+	if options.managementPort > 0 {
+		server.management = fiber.New(options.fiberConfig())
+	}
+	return server
+}
+
+// App returns fiber app serving adapter API
+func (s *AdapterServer) App() *fiber.App {
+	return s.app
+}
+
+// ManagementApp returns the app serving health, metrics, pprof and log level endpoints.
+// It is the adapter app itself unless separate management port is configured by WithManagementPort.
+func (s *AdapterServer) ManagementApp() *fiber.App {
+	return s.management
+}
+
+// RegisterShutdownHook adds hook which is called by Drain.
+func (s *AdapterServer) RegisterShutdownHook(hook ShutdownHook) {
+	s.hooksMutex.Lock()
+	defer s.hooksMutex.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+// Drain runs shutdown hooks one by one, every hook is called once.
+// Adapter handlers stop accepting database creation and wait for in-flight requests during draining.
+func (s *AdapterServer) Drain(ctx context.Context) {
+	s.hooksMutex.Lock()
+	hooks := s.hooks
+	s.hooks = nil
+	s.hooksMutex.Unlock()
+
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+}
+
+type serverContextKey struct{}
+
+// ServerFromContext returns state of the app passed to setUp by GetFiberServer and RunFiberServer, nil otherwise.
+// Handlers registered by BuildFiberMultiDBaaSAdapterHandlers with it are drained on shutdown.
+func ServerFromContext(ctx context.Context) *AdapterServer {
+	server, _ := ctx.Value(serverContextKey{}).(*AdapterServer)
+	return server
+}
+
+func GetFiberServer(setUp func(app *fiber.App, ctx context.Context) error, opts ...ServerOption) (context.CancelFunc, *fiber.App, error) {
+	cancel, server, setupErr := GetAdapterServer(setUp, opts...)
+	return cancel, server.App(), setupErr
+}
+
+// GetAdapterServer creates fiber app configured by options and sets it up like GetFiberServer does,
+// it returns state of the app which is used to drain it or update its credentials.
+func GetAdapterServer(setUp func(app *fiber.App, ctx context.Context) error, opts ...ServerOption) (context.CancelFunc, *AdapterServer, error) {
+	options := newServerOptions(opts)
 	serverCtx, cancel := context.WithCancel(context.Background())
-	app := fiber.New(fiber.Config{Network: "tcp"})
+	server := newAdapterServer(fiber.New(options.fiberConfig()), options)
 
-	setupErr := setUp(app, serverCtx)
+	setupErr := setUp(server.app, context.WithValue(serverCtx, serverContextKey{}, server))
 
-	return cancel, app, setupErr
+	return cancel, server, setupErr
 }
 
-func RunFiberServer(port int, setUp func(app *fiber.App, ctx context.Context) error) error {
-	cancel, app, setupErr := GetFiberServer(setUp)
+// RunFiberServer starts fiber app and blocks until it stops. On SIGTERM or SIGINT the app is drained
+// with AdapterServer.Drain and then shut down. Whole shutdown sequence is limited by WithShutdownTimeout.
+// Listeners are configured by options, see ServerOption.
+func RunFiberServer(port int, setUp func(app *fiber.App, ctx context.Context) error, opts ...ServerOption) error {
+	cancel, server, setupErr := GetAdapterServer(setUp, opts...)
 	if setupErr != nil {
 		cancel()
 		return setupErr
 	}
 
 	defer cancel()
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(signals)
+
+	app, management, options := server.app, server.management, server.options
+	// both ports are bound before serving, so failure to bind one of them does not leave the other app serving
+	listener, err := listen(watchCtx, app, port, options)
+	if err != nil {
+		return err
+	}
+	listenResult := make(chan error, 2)
+	if management != app {
+		managementListener, err := net.Listen(management.Config().Network, joinHostPort(options.bindAddress, options.managementPort))
+		if err != nil {
+			return errors.Join(err, listener.Close())
+		}
+		go func() {
+			listenResult <- management.Listener(managementListener)
+		}()
+	}
+	go func() {
+		listenResult <- app.Listener(listener)
+	}()
+
+	select {
+	case err := <-listenResult:
+		// failure of one listener stops the other one too, so the process does not keep serving part of endpoints
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), options.shutdownTimeout)
+		defer shutdownCancel()
+		cancel()
+		return errors.Join(err, shutdownApps(shutdownCtx, app, management))
+	case <-signals:
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), options.shutdownTimeout)
+		defer shutdownCancel()
+		server.Drain(shutdownCtx)
+		cancel()
+		if err := shutdownApps(shutdownCtx, app, management); err != nil {
+			return err
+		}
+		return <-listenResult
+	}
+}
+
+// shutdownApps shuts down the app and its management app if it is served on separate port
+func shutdownApps(ctx context.Context, app, management *fiber.App) error {
+	err := app.ShutdownWithContext(ctx)
+	if management != app {
+		err = errors.Join(err, management.ShutdownWithContext(ctx))
+	}
+	return err
+}
 
-	if utils.IsHttpsEnabled() {
-		return app.ListenTLS(":"+strconv.Itoa(port), "/certs/tls.crt", "/certs/tls.key")
-	} else {
-		return app.Listen(":" + strconv.Itoa(port))
+// listen binds the port of the app. TLS certificate is reloaded when cert-manager
+// updates mounted files, so certificate rotation does not require restart.
+func listen(ctx context.Context, app *fiber.App, port int, options *serverOptions) (net.Listener, error) {
+	address := joinHostPort(options.bindAddress, port)
+	if !options.tlsEnabled {
+		return net.Listen(app.Config().Network, address)
 	}
+	reloader, err := utils.NewCertificateReloader(options.tlsCertFile, options.tlsKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig, err := options.tlsConfig(reloader)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen(app.Config().Network, address)
+	if err != nil {
+		return nil, err
+	}
+	reloader.Watch(ctx, options.reloadInterval, utils.GetLogger())
+	return tls.NewListener(listener, tlsConfig), nil
+}
+
+func joinHostPort(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
 }
@@ -0,0 +1,220 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/audit"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/auth"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultTlsCertFile    = "/certs/tls.crt"
+	defaultTlsKeyFile     = "/certs/tls.key"
+	defaultReloadInterval = 30 * time.Second
+)
+
+type serverOptions struct {
+	bindAddress    string
+	managementPort int
+	readTimeout    time.Duration
+	writeTimeout   time.Duration
+	idleTimeout    time.Duration
+	bodyLimit      int
+	// shutdownTimeout limits the whole shutdown sequence of RunFiberServer
+	shutdownTimeout time.Duration
+	// reloadInterval is the interval of checks of TLS certificate files
+	reloadInterval time.Duration
+
+	metricsRegisterer  prometheus.Registerer
+	metricsGatherer    prometheus.Gatherer
+	auditor            *audit.Auditor
+	tokenAuthenticator auth.TokenAuthenticator
+	operationLimits    *operationLimits
+	// protectMetrics requires read-only role for /metrics and /swagger
+	protectMetrics bool
+
+	tlsEnabled         bool
+	tlsCertFile        string
+	tlsKeyFile         string
+	tlsMinVersion      uint16
+	tlsCipherSuites    []uint16
+	clientCAFile       string
+	clientCertRequired bool
+}
+
+// ServerOption configures listeners of the adapter, see RunFiberServer
+type ServerOption func(*serverOptions)
+
+func newServerOptions(opts []ServerOption) *serverOptions {
+	options := &serverOptions{
+		shutdownTimeout: utils.GetGracefulShutdownTimeout(),
+		reloadInterval:  defaultReloadInterval,
+		tlsEnabled:      utils.IsHttpsEnabled(),
+		tlsCertFile:     defaultTlsCertFile,
+		tlsKeyFile:      defaultTlsKeyFile,
+		tlsMinVersion:   tls.VersionTLS12,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// WithBindAddress sets the interface address listeners are bound to, all interfaces by default
+func WithBindAddress(address string) ServerOption {
+	return func(o *serverOptions) { o.bindAddress = address }
+}
+
+// WithManagementPort serves health, metrics, pprof and log level endpoints on the separate port,
+// so they are not exposed together with adapter API. Management port does not use TLS.
+func WithManagementPort(port int) ServerOption {
+	return func(o *serverOptions) { o.managementPort = port }
+}
+
+// WithTimeouts sets read, write and idle timeouts of connections, zero value means no timeout
+func WithTimeouts(read, write, idle time.Duration) ServerOption {
+	return func(o *serverOptions) {
+		o.readTimeout = read
+		o.writeTimeout = write
+		o.idleTimeout = idle
+	}
+}
+
+// WithBodyLimit sets max size of request body in bytes, fiber default is 4 MB
+func WithBodyLimit(bytes int) ServerOption {
+	return func(o *serverOptions) { o.bodyLimit = bytes }
+}
+
+// WithShutdownTimeout limits the whole shutdown sequence of RunFiberServer,
+// by default it is GRACEFUL_SHUTDOWN_TIMEOUT_SEC env or 25 seconds
+func WithShutdownTimeout(timeout time.Duration) ServerOption {
+	return func(o *serverOptions) { o.shutdownTimeout = timeout }
+}
+
+// WithReloadInterval sets the interval of checks of TLS certificate files, 30 seconds by default
+func WithReloadInterval(interval time.Duration) ServerOption {
+	return func(o *serverOptions) { o.reloadInterval = interval }
+}
+
+// WithTLS enables or disables TLS of adapter API, by default it is enabled by INTERNAL_TLS_ENABLED env
+func WithTLS(enabled bool) ServerOption {
+	return func(o *serverOptions) { o.tlsEnabled = enabled }
+}
+
+// WithTLSFiles sets certificate and key of adapter API, /certs/tls.crt and /certs/tls.key by default
+func WithTLSFiles(certFile, keyFile string) ServerOption {
+	return func(o *serverOptions) {
+		o.tlsCertFile = certFile
+		o.tlsKeyFile = keyFile
+	}
+}
+
+// WithTLSMinVersion sets min TLS version, tls.VersionTLS12 by default
+func WithTLSMinVersion(version uint16) ServerOption {
+	return func(o *serverOptions) { o.tlsMinVersion = version }
+}
+
+// WithTLSCipherSuites limits cipher suites of TLS 1.2, TLS 1.3 suites are not configurable
+func WithTLSCipherSuites(cipherSuites []uint16) ServerOption {
+	return func(o *serverOptions) { o.tlsCipherSuites = cipherSuites }
+}
+
+// WithClientCertificates verifies client certificates by CA from the file.
+// If required is false, clients without certificate are accepted too.
+func WithClientCertificates(caFile string, required bool) ServerOption {
+	return func(o *serverOptions) {
+		o.clientCAFile = caFile
+		o.clientCertRequired = required
+	}
+}
+
+func (o *serverOptions) fiberConfig() fiber.Config {
+	return fiber.Config{
+		Network:      "tcp",
+		ReadTimeout:  o.readTimeout,
+		WriteTimeout: o.writeTimeout,
+		IdleTimeout:  o.idleTimeout,
+		BodyLimit:    o.bodyLimit,
+		ErrorHandler: problemErrorHandler,
+	}
+}
+
+func (o *serverOptions) tlsConfig(reloader *utils.CertificateReloader) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:     o.tlsMinVersion,
+		CipherSuites:   o.tlsCipherSuites,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if o.clientCAFile != "" {
+		caCertificates, err := os.ReadFile(o.clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read client CA: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caCertificates) {
+			return nil, fmt.Errorf("no certificates found in %s", o.clientCAFile)
+		}
+		config.ClientCAs = clientCAs
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if o.clientCertRequired {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return config, nil
+}
+
+// ParseTLSVersion converts "1.2" or "1.3" to TLS version constant
+func ParseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q", version)
+	}
+}
+
+// ParseCipherSuites converts comma separated cipher suite names, e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+// to cipher suite ids. Only secure cipher suites are accepted.
+func ParseCipherSuites(names string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+	var result []uint16
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %s", name)
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
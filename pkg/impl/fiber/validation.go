@@ -0,0 +1,102 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiber
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	// namePrefixRegexp allows prefixes valid in names of databases and users of all supported engines
+	namePrefixRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{0,62}$`)
+	roleNameRegexp   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.-]{0,62}$`)
+)
+
+// validate checks request bodies of adapter API
+var validate = newValidator()
+
+type supportedRolesKey struct{}
+
+// withSupportedRoles makes dbrole validation accept only the roles, any role name is accepted if roles are empty
+func withSupportedRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, supportedRolesKey{}, roles)
+}
+
+// newValidator validates request bodies by validate tags, violations are named by json names of fields.
+// Custom tags:
+//   - classifier: metadata has classifier object with namespace and string microserviceName if they are present
+//   - nameprefix: prefix of names of databases and users
+//   - dbrole: role of user, it must be supported by the adapter
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	must(validate.RegisterValidation("classifier", validateClassifier))
+	must(validate.RegisterValidation("nameprefix", func(fl validator.FieldLevel) bool {
+		return namePrefixRegexp.MatchString(fl.Field().String())
+	}))
+	must(validate.RegisterValidationCtx("dbrole", func(ctx context.Context, fl validator.FieldLevel) bool {
+		role := fl.Field().String()
+		if !roleNameRegexp.MatchString(role) {
+			return false
+		}
+		roles, _ := ctx.Value(supportedRolesKey{}).([]string)
+		return len(roles) == 0 || slices.Contains(roles, role)
+	}))
+	return validate
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// validateClassifier checks shape of metadata used by vault integration, so it does not fail on type assertions
+func validateClassifier(fl validator.FieldLevel) bool {
+	metadata, ok := fl.Field().Interface().(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if value, found := metadata["classifier"]; found {
+		classifier, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if namespace, ok := classifier["namespace"].(string); !ok || namespace == "" {
+			return false
+		}
+	}
+	if value, found := metadata["microserviceName"]; found {
+		if microserviceName, ok := value.(string); !ok || microserviceName == "" {
+			return false
+		}
+	}
+	return true
+}
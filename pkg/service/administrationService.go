@@ -16,8 +16,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
@@ -26,11 +30,21 @@ import (
 
 const (
 	vaultRole           = "vaultRole"
+	vaultDynamicRole    = "vaultDynamicRole"
 	dbResourceKind      = "database"
 	userResourceKind    = "user"
 	vaultPasswordPrefix = "vault:"
 )
 
+var (
+	ErrUserNotInVault         = NewError(InvalidArgument, dto.UserNotInVaultCode, "password of the user is not stored in vault")
+	ErrNotSupported           = NewError(NotSupported, dto.NotSupportedCode, "operation is not supported by the adapter")
+	ErrPasswordManagedByVault = NewError(InvalidArgument, dto.PasswordManagedByVaultCode, "password of the user is managed by vault")
+	ErrUserNotFound           = NewError(NotFound, dto.NotFoundCode, "user is not found")
+	// ErrVaultRoleNotDeleted is returned by MigrateFromVault together with the new password of the user
+	ErrVaultRoleNotDeleted = NewError(InternalError, dto.InternalErrorCode, "vault role of the user cannot be deleted")
+)
+
 type DbAdministration interface {
 	// CreateDatabase may return 400 "Provided namePrefix does not meet the requirements"
 	CreateDatabase(ctx context.Context, requestOnCreateDb dto.DbCreateRequest) (string, *dto.LogicalDatabaseDescribed, error)
@@ -53,6 +67,19 @@ type DbAdministration interface {
 	CreateRoles(ctx context.Context, roles []dto.AdditionalRole) ([]dto.Success, *dto.Failure)
 }
 
+// DynamicCredentialsAdministration is an optional interface of DbAdministration.
+// It is required to create vault dynamic roles when vault client works in dynamic credentials mode.
+type DynamicCredentialsAdministration interface {
+	GetDynamicRoleStatements(ctx context.Context, dbName, userName string) (utils.DynamicRoleStatements, error)
+}
+
+// VaultReverseMigrationAdministration is an optional interface of DbAdministration.
+// It is required to migrate user password from vault back to the database.
+type VaultReverseMigrationAdministration interface {
+	// MigrateFromVault sets new password of the user and revokes vault access to the user if it was granted
+	MigrateFromVault(ctx context.Context, dbName, userName, password string) error
+}
+
 type CoreAdministrationServiceIface interface {
 	CreateDatabase(ctx context.Context, requestOnCreateDb dto.DbCreateRequest) (interface{}, error)
 	DropResources(ctx context.Context, resources []dto.DbResource) (*[]dto.DbResource, bool)
@@ -64,21 +91,124 @@ type CoreAdministrationServiceIface interface {
 	PreStart()
 	CreateUser(ctx context.Context, userName string, requestOnCreateUser dto.UserCreateRequest) (*dto.CreatedUser, error)
 	MigrateToVault(ctx context.Context, dbName, userName string) (string, error)
+	// MigrateFromVault replaces vault managed password of the user with generated one and returns the new password.
+	// If vault role cannot be deleted, the new password is returned together with ErrVaultRoleNotDeleted.
+	MigrateFromVault(ctx context.Context, dbName, userName string) (string, error)
 	GetVersion() dto.ApiVersion
 	CreateRoles(ctx context.Context, roles []dto.AdditionalRole) ([]dto.Success, *dto.Failure)
 	GetSupportedRoles() []string
 	GetFeatures() map[string]bool
 	GetROHost() string
+	StartVaultMigration(ctx context.Context, request dto.VaultMigrationRequest) (*dto.VaultMigrationJob, error)
+	GetVaultMigrationJob() *dto.VaultMigrationJob
+	RotatePassword(ctx context.Context, dbName, userName string) (*dto.CreatedUser, error)
+	GetPasswordRotationRecords(dbName, userName string) []dto.PasswordRotationRecord
+}
+
+// RolesResultsCreator is an optional interface of CoreAdministrationServiceIface implementations which can create
+// additional roles one by one. Registration reports result of every role if it is implemented and falls back to
+// CreateRoles otherwise.
+type RolesResultsCreator interface {
+	// CreateRolesWithResults creates every role separately and returns result for each of them.
+	// created is called for every role as soon as it is created, it may be called concurrently.
+	CreateRolesWithResults(ctx context.Context, roles []dto.AdditionalRole, created func(dto.Success)) ([]dto.Success, []dto.Failure)
 }
 
+// Drainer is an optional interface of CoreAdministrationServiceIface implementations running background jobs,
+// adapter handlers stop them by Drain when adapter is shutting down.
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
+var _ Drainer = &CoreAdministrationService{}
+
 type CoreAdministrationService struct {
-	namespace      string
-	port           int
+	namespace string
+	port      int
+	// dbAdm runs calls of dbAdmImpl in spans, dbAdmImpl is used to check optional interfaces
 	dbAdm          DbAdministration
+	dbAdmImpl      DbAdministration
 	logger         *zap.Logger
 	isVaultEnabled bool
 	vaultClient    *utils.VaultClient
 	roHost         string
+	// cloudPublicHost is used in vault role names
+	cloudPublicHost string
+	// rolesConcurrency limits the number of additional roles being created at the same time
+	rolesConcurrency int
+	vaultMigration   *vaultMigration
+	passwordRotation *passwordRotation
+	metrics          *Metrics
+	// loopContext stops background jobs of the service, e.g. password rotation policy
+	loopContext context.Context
+}
+
+type administrationOptions struct {
+	cloudPublicHost               string
+	rolesConcurrency              int
+	vaultMigrationRate            int
+	vaultMigrationCheckpointDir   string
+	passwordRotationCheckInterval time.Duration
+	metrics                       *Metrics
+	loopContext                   context.Context
+	connectionPublisher           *connectionPropertiesPublisher
+}
+
+const (
+	defaultRolesConcurrency   = 4
+	defaultVaultMigrationRate = 5
+)
+
+// AdministrationOption overrides default setting of CoreAdministrationService,
+// fiber.BuildAdapter sets them from the loaded configuration
+type AdministrationOption func(*administrationOptions)
+
+// WithCloudPublicHost sets host used in vault role names, it is CLOUD_PUBLIC_HOST env by default
+func WithCloudPublicHost(host string) AdministrationOption {
+	return func(o *administrationOptions) { o.cloudPublicHost = host }
+}
+
+// WithRolesConcurrency limits the number of additional roles created at the same time, 4 by default
+func WithRolesConcurrency(concurrency int) AdministrationOption {
+	return func(o *administrationOptions) { o.rolesConcurrency = concurrency }
+}
+
+// WithVaultMigrationRate sets default number of users migrated to vault per second, 5 by default
+func WithVaultMigrationRate(rate int) AdministrationOption {
+	return func(o *administrationOptions) { o.vaultMigrationRate = rate }
+}
+
+// WithVaultMigrationCheckpointDir sets directory of bulk vault migration progress, it is kept in memory by default
+func WithVaultMigrationCheckpointDir(dir string) AdministrationOption {
+	return func(o *administrationOptions) { o.vaultMigrationCheckpointDir = dir }
+}
+
+// WithPasswordRotationCheckInterval enables password rotation policy, it is disabled by default
+func WithPasswordRotationCheckInterval(interval time.Duration) AdministrationOption {
+	return func(o *administrationOptions) { o.passwordRotationCheckInterval = interval }
+}
+
+// WithMetrics sets metrics of database lifecycle operations, metrics are not exported by default
+func WithMetrics(metrics *Metrics) AdministrationOption {
+	return func(o *administrationOptions) { o.metrics = metrics }
+}
+
+// WithLoopContext sets context which stops background jobs of the service, context.Background() by default
+func WithLoopContext(ctx context.Context) AdministrationOption {
+	return func(o *administrationOptions) { o.loopContext = ctx }
+}
+
+// WithConnectionPropertiesPublisher sets client of dbaas aggregator which receives connection properties changed
+// by the adapter itself, e.g. by password rotation policy. dbType and physicalDatabaseId identify the adapter
+// in dbaas aggregator. Password rotation policy is not started without the publisher.
+func WithConnectionPropertiesPublisher(publisher ConnectionPropertiesPublisher, dbType, physicalDatabaseId string) AdministrationOption {
+	return func(o *administrationOptions) {
+		o.connectionPublisher = &connectionPropertiesPublisher{
+			publisher:          publisher,
+			dbType:             dbType,
+			physicalDatabaseId: physicalDatabaseId,
+		}
+	}
 }
 
 func NewCoreAdministrationService(
@@ -88,19 +218,50 @@ func NewCoreAdministrationService(
 	logger *zap.Logger,
 	isVaultEnabled bool,
 	vaultClient *utils.VaultClient,
-	roHost string) CoreAdministrationServiceIface {
+	roHost string,
+	opts ...AdministrationOption) CoreAdministrationServiceIface {
+	options := administrationOptions{
+		cloudPublicHost:    utils.GetEnv("CLOUD_PUBLIC_HOST", ""),
+		rolesConcurrency:   defaultRolesConcurrency,
+		vaultMigrationRate: defaultVaultMigrationRate,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.metrics == nil {
+		options.metrics = unregisteredMetrics()
+	}
+	if options.loopContext == nil {
+		options.loopContext = context.Background()
+	}
 	return &CoreAdministrationService{
-		namespace:      namespace,
-		port:           port,
-		dbAdm:          dbAdm,
-		logger:         logger,
-		isVaultEnabled: isVaultEnabled,
-		vaultClient:    vaultClient,
-		roHost:         roHost,
+		namespace:        namespace,
+		port:             port,
+		dbAdm:            traceDbAdministration(dbAdm),
+		dbAdmImpl:        dbAdm,
+		logger:           logger,
+		isVaultEnabled:   isVaultEnabled,
+		vaultClient:      vaultClient,
+		roHost:           roHost,
+		cloudPublicHost:  options.cloudPublicHost,
+		rolesConcurrency: max(options.rolesConcurrency, 1),
+		vaultMigration:   newVaultMigration(logger, options.vaultMigrationRate, options.vaultMigrationCheckpointDir),
+		passwordRotation: newPasswordRotation(options.passwordRotationCheckInterval, options.connectionPublisher),
+		metrics:          options.metrics,
+		loopContext:      options.loopContext,
 	}
 }
 
-func (adminService *CoreAdministrationService) CreateDatabase(ctx context.Context, requestOnCreateDb dto.DbCreateRequest) (interface{}, error) {
+func (adminService *CoreAdministrationService) CreateDatabase(ctx context.Context, requestOnCreateDb dto.DbCreateRequest) (response interface{}, err error) {
+	start := time.Now()
+	defer func() {
+		// panic is recovered by handlers, so it is recorded as failure and propagated
+		if r := recover(); r != nil {
+			adminService.metrics.databaseCreated(start, fmt.Errorf("%v", r))
+			panic(r)
+		}
+		adminService.metrics.databaseCreated(start, err)
+	}()
 	//metadata creation should be inside as well
 	logicalDatabaseName, dbDescribed, createErr := adminService.dbAdm.CreateDatabase(ctx, requestOnCreateDb)
 	if createErr != nil {
@@ -136,7 +297,9 @@ func (adminService *CoreAdministrationService) DropResources(ctx context.Context
 			}
 			logger.Warn(fmt.Sprintf("Error during deleting resource %s with name \"%s\", %v", resource.Kind, resource.Name, errMsg))
 			isFailed = true
+			adminService.metrics.resourceDropped(resource.Kind, true)
 		} else {
+			adminService.metrics.resourceDropped(resource.Kind, false)
 			if resource.Status == "" {
 				resource.Status = dto.DELETED
 			}
@@ -182,6 +345,7 @@ func (adminService *CoreAdministrationService) GetDefaultUserCreateRequest() dto
 func (adminService *CoreAdministrationService) PreStart() {
 	adminService.logger.Debug(fmt.Sprintf("PreStart function is started"))
 	adminService.dbAdm.PreStart()
+	adminService.startPasswordRotationPolicy(adminService.loopContext)
 	adminService.logger.Debug(fmt.Sprintf("PreStart function is finished"))
 }
 
@@ -206,9 +370,56 @@ func (adminService *CoreAdministrationService) MigrateToVault(ctx context.Contex
 		return "", err
 	}
 	metadata := adminService.dbAdm.GetMetadata(ctx, dbName)
+	// user may already have a role, e.g. created with legacy name, so another role is not created
+	if _, roleName, found := adminService.findVaultRole(metadata, userName); found {
+		utils.AddLoggerContext(adminService.logger, ctx).Info(fmt.Sprintf("User %s of database %s already has vault role %s", userName, dbName, roleName))
+		return roleName, nil
+	}
 	return adminService.createVaultRole(ctx, metadata, dbName, userName)
 }
 
+func (adminService *CoreAdministrationService) MigrateFromVault(ctx context.Context, dbName, userName string) (string, error) {
+	logger := utils.AddLoggerContext(adminService.logger, ctx)
+	if !adminService.isVaultEnabled {
+		return "", ErrVaultDisabled
+	}
+	reverseAdm, ok := adminService.dbAdmImpl.(VaultReverseMigrationAdministration)
+	if !ok {
+		return "", ErrNotSupported
+	}
+	metadata := adminService.dbAdm.GetMetadata(ctx, dbName)
+	if err := validateSettingMetadata(metadata); err != nil {
+		return "", err
+	}
+	roleKey, roleName, found := adminService.findVaultRole(metadata, userName)
+	if !found {
+		return "", fmt.Errorf("%w: user %s of database %s", ErrUserNotInVault, userName, dbName)
+	}
+
+	password, err := utils.GeneratePassword(utils.DefaultPasswordLength)
+	if err != nil {
+		return "", err
+	}
+	// password is set before vault role deletion, so the user is accessible even if the deletion fails
+	if err = traceMigrateFromVault(ctx, reverseAdm, dbName, userName, password); err != nil {
+		return "", err
+	}
+	if roleKey == vaultDynamicRole {
+		err = adminService.vaultClient.DeleteDynamicVaultRole(roleName)
+	} else {
+		err = adminService.vaultClient.DeleteVaultRole(roleName)
+	}
+	adminService.metrics.vaultRoleOperation("delete", err)
+	if err != nil {
+		// role is kept in metadata, so the next migration of the user deletes it
+		logger.Warn(fmt.Sprintf("Password of user %s of database %s is changed, but vault role %s cannot be deleted: %v", userName, dbName, roleName, err))
+		return password, fmt.Errorf("%w: vault role %s must be deleted, otherwise vault rotates the password: %v", ErrVaultRoleNotDeleted, roleName, err)
+	}
+	adminService.dbAdm.UpdateMetadata(ctx, removeVaultRoleFromMetadata(metadata, roleKey, roleName), dbName)
+	logger.Info(fmt.Sprintf("User %s of database %s is migrated from vault role %s", userName, dbName, roleName))
+	return password, nil
+}
+
 func (adminService *CoreAdministrationService) GetVersion() dto.ApiVersion {
 	return adminService.dbAdm.GetVersion()
 }
@@ -221,12 +432,31 @@ func (adminService *CoreAdministrationService) createVaultRole(ctx context.Conte
 	classifier := metadata["classifier"].(map[string]interface{})
 	namespace := classifier["namespace"].(string)
 	microserviceName := metadata["microserviceName"].(string)
-	cloudPublicHost := utils.GetEnv("CLOUD_PUBLIC_HOST", "")
+	cloudPublicHost := adminService.cloudPublicHost
+	if adminService.vaultClient.IsDynamicCredentials() {
+		dynamicAdm, ok := adminService.dbAdmImpl.(DynamicCredentialsAdministration)
+		if !ok {
+			return "", errors.New("adapter does not support vault dynamic credentials")
+		}
+		statements, err := traceGetDynamicRoleStatements(ctx, dynamicAdm, dbName, userName)
+		if err != nil {
+			return "", err
+		}
+		roleName, err := adminService.vaultClient.CreateDynamicVaultRole(cloudPublicHost, namespace, microserviceName, userName, statements)
+		adminService.metrics.vaultRoleOperation("create", err)
+		if err != nil {
+			return "", err
+		}
+		metadata = appendVaultRoleToMetadata(metadata, vaultDynamicRole, roleName)
+		adminService.dbAdm.UpdateMetadata(ctx, metadata, dbName)
+		return roleName, nil
+	}
 	roleName, err := adminService.vaultClient.CreateVaultRole(cloudPublicHost, namespace, microserviceName, userName)
+	adminService.metrics.vaultRoleOperation("create", err)
 	if err != nil {
 		return "", err
 	}
-	metadata = appendVaultRoleToMetadata(metadata, roleName)
+	metadata = appendVaultRoleToMetadata(metadata, vaultRole, roleName)
 	adminService.dbAdm.UpdateMetadata(ctx, metadata, dbName)
 	return roleName, nil
 }
@@ -237,15 +467,17 @@ func (adminService *CoreAdministrationService) performVaultRolesDelete(ctx conte
 		if resource.Kind == dbResourceKind {
 			metadata := adminService.dbAdm.GetMetadata(ctx, resource.Name)
 			if metadata != nil {
-				if vaultRoleName, ok := metadata[vaultRole].(string); ok {
-					_ = adminService.vaultClient.DeleteVaultRole(vaultRoleName)
-				} else if vaultRoleNames, ok := metadata[vaultRole].([]interface{}); ok {
-					for _, vaultRoleName := range vaultRoleNames {
-						_ = adminService.vaultClient.DeleteVaultRole(vaultRoleName.(string))
-					}
-				} else {
+				staticRoles := vaultRolesFromMetadata(metadata, vaultRole)
+				dynamicRoles := vaultRolesFromMetadata(metadata, vaultDynamicRole)
+				if len(staticRoles) == 0 && len(dynamicRoles) == 0 {
 					logger.Debug(fmt.Sprintf("vaultRole can't be found in metadata for %s", resource.Name))
 				}
+				for _, vaultRoleName := range staticRoles {
+					adminService.metrics.vaultRoleOperation("delete", adminService.vaultClient.DeleteVaultRole(vaultRoleName))
+				}
+				for _, vaultRoleName := range dynamicRoles {
+					adminService.metrics.vaultRoleOperation("delete", adminService.vaultClient.DeleteDynamicVaultRole(vaultRoleName))
+				}
 			} else {
 				logger.Debug(fmt.Sprintf(fmt.Sprintf("can't get metadata for %s", resource.Name)))
 			}
@@ -309,79 +541,209 @@ func (adminService *CoreAdministrationService) GetROHost() string {
 	return adminService.roHost
 }
 
-func (adminService *CoreAdministrationService) CreateRoles(ctx context.Context, additionalRoles []dto.AdditionalRole) (resultSuccess []dto.Success, failure *dto.Failure) {
-	success, failure := adminService.dbAdm.CreateRoles(ctx, additionalRoles)
-	needToDrop := false
+// CreateRoles creates every role separately by CreateRolesWithResults, so failed role does not drop other roles.
+// The first failure is returned.
+func (adminService *CoreAdministrationService) CreateRoles(ctx context.Context, additionalRoles []dto.AdditionalRole) ([]dto.Success, *dto.Failure) {
+	success, failures := adminService.CreateRolesWithResults(ctx, additionalRoles, nil)
+	if len(failures) > 0 {
+		return success, &failures[0]
+	}
+	return success, nil
+}
 
-	for _, successForMigrate := range success {
-		userResources := make([]dto.DbResource, 0)
-		for _, resource := range successForMigrate.Resources {
-			if resource.Kind == userResourceKind {
-				userResources = append(userResources, resource)
+var _ RolesResultsCreator = &CoreAdministrationService{}
+
+// CreateRolesWithResults creates every role separately, at most rolesConcurrency roles at the same time
+func (adminService *CoreAdministrationService) CreateRolesWithResults(ctx context.Context, additionalRoles []dto.AdditionalRole, created func(dto.Success)) ([]dto.Success, []dto.Failure) {
+	type roleResult struct {
+		success *dto.Success
+		failure *dto.Failure
+	}
+	results := make([]roleResult, len(additionalRoles))
+	semaphore := make(chan struct{}, adminService.rolesConcurrency)
+	var wg sync.WaitGroup
+	for i, role := range additionalRoles {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, role dto.AdditionalRole) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i].success, results[i].failure = adminService.createRole(ctx, role)
+			if results[i].success != nil && created != nil {
+				created(*results[i].success)
 			}
+		}(i, role)
+	}
+	wg.Wait()
+
+	var success []dto.Success
+	var failures []dto.Failure
+	for _, result := range results {
+		if result.failure != nil {
+			failures = append(failures, *result.failure)
+		} else if result.success != nil {
+			success = append(success, *result.success)
 		}
-		if failure != nil && failure.Id == successForMigrate.Id {
-			needToDrop = true
+	}
+	return success, failures
+}
+
+// createRole creates one additional role and migrates its users to vault if vault is enabled.
+// Created users are dropped if the role cannot be created completely.
+func (adminService *CoreAdministrationService) createRole(ctx context.Context, role dto.AdditionalRole) (result *dto.Success, failure *dto.Failure) {
+	logger := utils.AddLoggerContext(adminService.logger, ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(fmt.Sprintf("Panic during additional role %s creation: %v", role.Id, r))
+			result = nil
+			failure = &dto.Failure{Id: role.Id, Message: fmt.Sprintf("%v", r)}
 		}
+	}()
 
-		if !needToDrop && adminService.isVaultEnabled {
-			for i, resource := range userResources {
-				vaultRoleName, err := adminService.MigrateToVault(ctx, successForMigrate.DbName, resource.Name)
+	success, failure := adminService.dbAdm.CreateRoles(ctx, []dto.AdditionalRole{role})
+	userResources := make([]dto.DbResource, 0)
+	for _, created := range success {
+		for _, resource := range created.Resources {
+			if resource.Kind == userResourceKind {
+				userResources = append(userResources, resource)
+			}
+		}
+	}
+	if failure == nil && len(success) == 0 {
+		failure = &dto.Failure{Message: "adapter returned no result for the role"}
+	}
+	if failure == nil {
+		result = &success[0]
+		if adminService.isVaultEnabled {
+			for _, resource := range userResources {
+				properties := connectionPropertiesOf(result.ConnectionProperties, resource.Name)
+				if properties == nil {
+					failure = &dto.Failure{
+						Message: fmt.Sprintf("adapter returned no connection properties of user %s for database %s", resource.Name, result.DbName),
+					}
+					break
+				}
+				vaultRoleName, err := adminService.MigrateToVault(ctx, result.DbName, resource.Name)
 				if err != nil {
-					if failure == nil {
-						failure = &dto.Failure{
-							Id:      successForMigrate.Id,
-							Message: fmt.Sprintf("cannot migrate role %s for database %s to vault", vaultRoleName, successForMigrate.DbName),
-						}
-					} else {
-						failure.Id = successForMigrate.Id
+					failure = &dto.Failure{
+						Message: fmt.Sprintf("cannot migrate role %s for database %s to vault: %v", resource.Name, result.DbName, err),
 					}
-					needToDrop = true
 					break
-				} else {
-					password := utils.VaultPasswordPrefix + vaultRoleName
-					successForMigrate.ConnectionProperties[i]["password"] = password
 				}
+				properties["password"] = utils.VaultPasswordPrefix + vaultRoleName
 			}
 		}
-
-		if needToDrop {
+	}
+	if failure != nil {
+		failure.Id = role.Id
+		logger.Warn(fmt.Sprintf("Failed to create additional role %s: %s", role.Id, failure.Message))
+		if len(userResources) > 0 {
 			adminService.DropResources(ctx, userResources)
-			continue
-		} else {
-			resultSuccess = append(resultSuccess, successForMigrate)
+		}
+		return nil, failure
+	}
+	return result, nil
+}
+
+// connectionPropertiesOf returns connection properties of the user, they are matched by username
+// because adapters do not return them in the order of user resources
+func connectionPropertiesOf(connectionProperties []dto.ConnectionProperties, userName string) dto.ConnectionProperties {
+	for _, properties := range connectionProperties {
+		if name, ok := properties["username"].(string); ok && name == userName {
+			return properties
 		}
 	}
-	return resultSuccess, failure
+	return nil
 }
 
-func appendVaultRoleToMetadata(metadata map[string]interface{}, roleName string) map[string]interface{} {
-	if role, ok := metadata[vaultRole].(string); ok {
+func appendVaultRoleToMetadata(metadata map[string]interface{}, key, roleName string) map[string]interface{} {
+	if role, ok := metadata[key].(string); ok {
 		roles := make([]interface{}, 0)
 		roles = append(roles, role, roleName)
-		metadata[vaultRole] = roles
-	} else if roles, ok := metadata[vaultRole].([]interface{}); ok {
+		metadata[key] = roles
+	} else if roles, ok := metadata[key].([]interface{}); ok {
 		roles = append(roles, roleName)
-		metadata[vaultRole] = roles
+		metadata[key] = roles
 	} else {
-		metadata[vaultRole] = roleName
+		metadata[key] = roleName
 	}
 	return metadata
 }
 
+// findVaultRole returns metadata key and name of the vault role of the user.
+// Roles created with legacy names are found as well.
+func (adminService *CoreAdministrationService) findVaultRole(metadata map[string]interface{}, userName string) (key, roleName string, found bool) {
+	if validateSettingMetadata(metadata) != nil {
+		return "", "", false
+	}
+	classifier := metadata["classifier"].(map[string]interface{})
+	namespace, _ := classifier["namespace"].(string)
+	microserviceName, _ := metadata["microserviceName"].(string)
+	cloudPublicHost := adminService.cloudPublicHost
+	resolve := func(existingRoles []string) (string, bool) {
+		return utils.ResolveVaultRoleName(utils.DefaultVaultRoleNameMaxLength, existingRoles, cloudPublicHost, namespace, microserviceName, userName)
+	}
+	if adminService.vaultClient != nil {
+		resolve = func(existingRoles []string) (string, bool) {
+			return adminService.vaultClient.ResolveVaultRoleName(existingRoles, cloudPublicHost, namespace, microserviceName, userName)
+		}
+	}
+	for _, key := range []string{vaultRole, vaultDynamicRole} {
+		roleName, found := resolve(vaultRolesFromMetadata(metadata, key))
+		if found {
+			return key, roleName, true
+		}
+	}
+	return "", "", false
+}
+
+func removeVaultRoleFromMetadata(metadata map[string]interface{}, key, roleName string) map[string]interface{} {
+	roles := slices.DeleteFunc(vaultRolesFromMetadata(metadata, key), func(role string) bool {
+		return role == roleName
+	})
+	switch len(roles) {
+	case 0:
+		delete(metadata, key)
+	case 1:
+		metadata[key] = roles[0]
+	default:
+		result := make([]interface{}, 0, len(roles))
+		for _, role := range roles {
+			result = append(result, role)
+		}
+		metadata[key] = result
+	}
+	return metadata
+}
+
+func vaultRolesFromMetadata(metadata map[string]interface{}, key string) []string {
+	if roleName, ok := metadata[key].(string); ok {
+		return []string{roleName}
+	}
+	var roles []string
+	if roleNames, ok := metadata[key].([]interface{}); ok {
+		for _, roleName := range roleNames {
+			if name, ok := roleName.(string); ok {
+				roles = append(roles, name)
+			}
+		}
+	}
+	return roles
+}
+
+// validateSettingMetadata checks metadata has classifier with namespace and microserviceName, vault roles are named by them
 func validateSettingMetadata(metadata map[string]interface{}) error {
-	classifierData := metadata["classifier"]
-	if classifierData == nil {
-		return fmt.Errorf("request contains not valid 'classifier' parameter in metadata")
+	classifier, ok := metadata["classifier"].(map[string]interface{})
+	if !ok {
+		return NewError(InvalidArgument, dto.ValidationFailedCode, "request contains not valid 'classifier' parameter in metadata")
 	}
 
-	classifier := metadata["classifier"].(map[string]interface{})
-	if classifier["namespace"] == nil {
-		return fmt.Errorf("request contains not valid 'namespace' parameter in classifier")
+	if namespace, ok := classifier["namespace"].(string); !ok || namespace == "" {
+		return NewError(InvalidArgument, dto.ValidationFailedCode, "request contains not valid 'namespace' parameter in classifier")
 	}
 
-	if metadata["microserviceName"] == nil {
-		return fmt.Errorf("request contains not valid 'microserviceName' parameter in metadata")
+	if microserviceName, ok := metadata["microserviceName"].(string); !ok || microserviceName == "" {
+		return NewError(InvalidArgument, dto.ValidationFailedCode, "request contains not valid 'microserviceName' parameter in metadata")
 	}
 
 	return nil
@@ -0,0 +1,323 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	vault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDbAdministration keeps users and metadata of databases in memory. Roles which ids start with "failed"
+// cannot be created. Created roles, dropped resources and users migrated to vault are recorded.
+type testDbAdministration struct {
+	DbAdministration
+	mutex    sync.Mutex
+	users    map[string][]string
+	metadata map[string]map[string]interface{}
+	created  []string
+	dropped  []string
+	migrated []string
+	// passwords are set by MigrateFromVault and RotatePassword
+	passwords map[string]string
+}
+
+func (adm *testDbAdministration) CreateRoles(_ context.Context, roles []dao.AdditionalRole) ([]dao.Success, *dao.Failure) {
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	var success []dao.Success
+	for _, role := range roles {
+		if strings.HasPrefix(role.Id, "failed") {
+			return success, &dao.Failure{Id: role.Id, Message: "cannot create role"}
+		}
+		adm.created = append(adm.created, role.Id)
+		success = append(success, dao.Success{
+			Id:                   role.Id,
+			DbName:               role.DbName,
+			ConnectionProperties: []dao.ConnectionProperties{{"username": role.Id + "-user", "password": "secret"}},
+			Resources:            []dao.DbResource{{Kind: userResourceKind, Name: role.Id + "-user"}},
+		})
+	}
+	return success, nil
+}
+
+func (adm *testDbAdministration) GetVersion() dao.ApiVersion {
+	return "v2"
+}
+
+func (adm *testDbAdministration) DropResources(_ context.Context, resources []dao.DbResource) []dao.DbResource {
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	for _, resource := range resources {
+		adm.dropped = append(adm.dropped, resource.Name)
+	}
+	return resources
+}
+
+func (adm *testDbAdministration) GetDatabases(_ context.Context) []string {
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	return slices.Sorted(maps.Keys(adm.users))
+}
+
+func (adm *testDbAdministration) DescribeDatabases(_ context.Context, logicalDatabases []string, _ bool, _ bool) map[string]dao.LogicalDatabaseDescribed {
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	described := make(map[string]dao.LogicalDatabaseDescribed)
+	for _, dbName := range logicalDatabases {
+		var resources []dao.DbResource
+		for _, user := range adm.users[dbName] {
+			resources = append(resources, dao.DbResource{Kind: userResourceKind, Name: user})
+		}
+		described[dbName] = dao.LogicalDatabaseDescribed{Resources: resources}
+	}
+	return described
+}
+
+func (adm *testDbAdministration) GetMetadata(_ context.Context, logicalDatabase string) map[string]interface{} {
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	return maps.Clone(adm.metadata[logicalDatabase])
+}
+
+func (adm *testDbAdministration) UpdateMetadata(_ context.Context, newMetadata map[string]interface{}, logicalDatabase string) {
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	adm.metadata[logicalDatabase] = newMetadata
+}
+
+func (adm *testDbAdministration) MigrateToVault(_ context.Context, dbName, userName string) error {
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	adm.migrated = append(adm.migrated, dbName+"/"+userName)
+	return nil
+}
+
+func (adm *testDbAdministration) MigrateFromVault(_ context.Context, dbName, userName, password string) error {
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	if adm.passwords == nil {
+		adm.passwords = make(map[string]string)
+	}
+	adm.passwords[dbName+"/"+userName] = password
+	return nil
+}
+
+func (adm *testDbAdministration) RotatePassword(_ context.Context, dbName, userName, password string) (dao.ConnectionProperties, error) {
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	if !slices.Contains(adm.users[dbName], userName) {
+		return nil, errors.New("user is not found")
+	}
+	if adm.passwords == nil {
+		adm.passwords = make(map[string]string)
+	}
+	adm.passwords[dbName+"/"+userName] = password
+	return dao.ConnectionProperties{"username": userName, "password": password}, nil
+}
+
+// testDatabaseMetadata is the metadata of the database having vault roles of the users
+func testDatabaseMetadata(vaultRoles ...string) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"classifier":       map[string]interface{}{"namespace": "ns"},
+		"microserviceName": "ms",
+	}
+	for _, role := range vaultRoles {
+		metadata = appendVaultRoleToMetadata(metadata, vaultRole, role)
+	}
+	return metadata
+}
+
+func newTestAdministrationService(adm DbAdministration, opts ...AdministrationOption) *CoreAdministrationService {
+	return NewCoreAdministrationService("test", 8080, adm, utils.GetLogger(false), false, nil, "", opts...).(*CoreAdministrationService)
+}
+
+// testVault serves vault static roles API and records requests, roles cannot be deleted if failDelete is set
+type testVault struct {
+	mutex      sync.Mutex
+	requests   []string
+	failDelete bool
+}
+
+func (v *testVault) handler(w http.ResponseWriter, r *http.Request) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.requests = append(v.requests, r.Method+" "+r.URL.Path)
+	if r.Method == http.MethodDelete && v.failDelete {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"errors":["vault is sealed"]}`))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (v *testVault) state() []string {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return append([]string{}, v.requests...)
+}
+
+type testVaultAuthenticator struct{}
+
+func (testVaultAuthenticator) Login(*vault.Client) (*vault.Secret, error) {
+	return &vault.Secret{Auth: &vault.SecretAuth{ClientToken: "token"}}, nil
+}
+
+// newTestVaultAdministrationService returns administration service with vault integration enabled
+func newTestVaultAdministrationService(t *testing.T, adm DbAdministration, fakeVault *testVault, opts ...AdministrationOption) *CoreAdministrationService {
+	server := httptest.NewServer(http.HandlerFunc(fakeVault.handler))
+	t.Cleanup(server.Close)
+	vaultClient, err := utils.NewVaultClient(utils.VaultConfig{
+		IsVaultEnabled: true,
+		Address:        server.URL,
+		VaultDBName:    "postgres",
+		Authenticator:  testVaultAuthenticator{},
+	})
+	require.NoError(t, err)
+	t.Cleanup(vaultClient.Close)
+	opts = append([]AdministrationOption{WithCloudPublicHost("host")}, opts...)
+	return NewCoreAdministrationService("test", 8080, adm, utils.GetLogger(false), true, vaultClient, "", opts...).(*CoreAdministrationService)
+}
+
+func TestMigrateFromVault(t *testing.T) {
+	roleName := utils.LegacyVaultRoleName("host", "ns", "ms", "user")
+	newDatabases := func() *testDbAdministration {
+		return &testDbAdministration{
+			users:    map[string][]string{"db": {"user", "other"}},
+			metadata: map[string]map[string]interface{}{"db": testDatabaseMetadata(roleName)},
+		}
+	}
+
+	t.Run("Password is set and vault role is deleted", func(t *testing.T) {
+		adm := newDatabases()
+		fakeVault := &testVault{}
+		admin := newTestVaultAdministrationService(t, adm, fakeVault)
+
+		password, err := admin.MigrateFromVault(context.Background(), "db", "user")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, password)
+		assert.Equal(t, password, adm.passwords["db/user"])
+		assert.Equal(t, []string{"DELETE /v1/database/static-roles/" + roleName}, fakeVault.state())
+		assert.NotContains(t, adm.metadata["db"], vaultRole)
+	})
+	t.Run("Password is returned if vault role cannot be deleted", func(t *testing.T) {
+		adm := newDatabases()
+		admin := newTestVaultAdministrationService(t, adm, &testVault{failDelete: true})
+
+		password, err := admin.MigrateFromVault(context.Background(), "db", "user")
+
+		assert.ErrorIs(t, err, ErrVaultRoleNotDeleted)
+		assert.NotEmpty(t, password)
+		assert.Equal(t, password, adm.passwords["db/user"])
+		assert.Equal(t, roleName, adm.metadata["db"][vaultRole], "role is kept to be deleted by the next migration")
+	})
+	t.Run("User is not in vault", func(t *testing.T) {
+		adm := newDatabases()
+		admin := newTestVaultAdministrationService(t, adm, &testVault{})
+
+		_, err := admin.MigrateFromVault(context.Background(), "db", "other")
+
+		assert.ErrorIs(t, err, ErrUserNotInVault)
+		assert.Empty(t, adm.passwords)
+	})
+	t.Run("Adapter does not support migration", func(t *testing.T) {
+		adm := newDatabases()
+		admin := newTestVaultAdministrationService(t, struct{ DbAdministration }{adm}, &testVault{})
+
+		_, err := admin.MigrateFromVault(context.Background(), "db", "user")
+
+		assert.ErrorIs(t, err, ErrNotSupported)
+	})
+	t.Run("Vault is disabled", func(t *testing.T) {
+		admin := newTestAdministrationService(newDatabases())
+
+		_, err := admin.MigrateFromVault(context.Background(), "db", "user")
+
+		assert.ErrorIs(t, err, ErrVaultDisabled)
+	})
+}
+
+func TestMigrateToVault(t *testing.T) {
+	t.Run("Role is created", func(t *testing.T) {
+		adm := &testDbAdministration{metadata: map[string]map[string]interface{}{"db": testDatabaseMetadata()}}
+		fakeVault := &testVault{}
+		admin := newTestVaultAdministrationService(t, adm, fakeVault)
+
+		roleName, err := admin.MigrateToVault(context.Background(), "db", "user")
+
+		require.NoError(t, err)
+		assert.Equal(t, utils.LegacyVaultRoleName("host", "ns", "ms", "user"), roleName)
+		assert.Equal(t, []string{"POST /v1/database/static-roles/" + roleName}, fakeVault.state())
+		assert.Equal(t, roleName, adm.metadata["db"][vaultRole])
+	})
+	t.Run("Role with legacy name is kept", func(t *testing.T) {
+		legacy := utils.LegacyVaultRoleName("host", "ns", "ms", "admin:user")
+		adm := &testDbAdministration{metadata: map[string]map[string]interface{}{"db": testDatabaseMetadata(legacy)}}
+		fakeVault := &testVault{}
+		admin := newTestVaultAdministrationService(t, adm, fakeVault)
+
+		roleName, err := admin.MigrateToVault(context.Background(), "db", "admin:user")
+
+		require.NoError(t, err)
+		assert.Equal(t, legacy, roleName)
+		assert.Empty(t, fakeVault.state(), "another role is not created")
+		assert.Equal(t, legacy, adm.metadata["db"][vaultRole])
+	})
+}
+
+// twoUsersDbAdministration returns connection properties of the role users in other order than their resources
+type twoUsersDbAdministration struct {
+	*testDbAdministration
+}
+
+func (adm twoUsersDbAdministration) CreateRoles(_ context.Context, roles []dao.AdditionalRole) ([]dao.Success, *dao.Failure) {
+	return []dao.Success{{
+		Id:                   roles[0].Id,
+		DbName:               roles[0].DbName,
+		ConnectionProperties: []dao.ConnectionProperties{{"username": "rw"}, {"username": "admin"}},
+		Resources: []dao.DbResource{
+			{Kind: "database", Name: roles[0].DbName},
+			{Kind: userResourceKind, Name: "admin"},
+			{Kind: userResourceKind, Name: "rw"},
+		},
+	}}, nil
+}
+
+func TestCreateRolesWithResults_VaultPasswordsOfUsers(t *testing.T) {
+	adm := &testDbAdministration{metadata: map[string]map[string]interface{}{"db": testDatabaseMetadata()}}
+	admin := newTestVaultAdministrationService(t, twoUsersDbAdministration{adm}, &testVault{})
+
+	success, failures := admin.CreateRolesWithResults(context.Background(), []dao.AdditionalRole{{Id: "role", DbName: "db"}}, nil)
+
+	require.Empty(t, failures)
+	require.Len(t, success, 1)
+	for _, properties := range success[0].ConnectionProperties {
+		userName := properties["username"].(string)
+		assert.Equal(t, utils.VaultPasswordPrefix+utils.LegacyVaultRoleName("host", "ns", "ms", userName), properties["password"])
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+)
+
+const (
+	backupOperation  = "backup"
+	restoreOperation = "restore"
+	// startedOperationTTL is the time start of operation is kept, operations which tracks are not requested
+	// in final status, e.g. tracked by other adapter replica, are forgotten after it
+	startedOperationTTL = 24 * time.Hour
+)
+
+// instrumentedBackupService records durations and outcomes of backups and restores started by this adapter
+type instrumentedBackupService struct {
+	BackupAdministrationService
+	metrics *Metrics
+	mutex   sync.Mutex
+	// started keeps start time of operations by operation and track id until they are finished or expired
+	started map[string]time.Time
+}
+
+// InstrumentBackupService wraps backup service with metrics of backups and restores. Operation is recorded
+// when its track is requested in final status for the first time.
+func InstrumentBackupService(backupService BackupAdministrationService, metrics *Metrics) BackupAdministrationService {
+	return &instrumentedBackupService{
+		BackupAdministrationService: backupService,
+		metrics:                     metrics,
+		started:                     make(map[string]time.Time),
+	}
+}
+
+func (s *instrumentedBackupService) start(operation, id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	for key, start := range s.started {
+		if now.Sub(start) > startedOperationTTL {
+			delete(s.started, key)
+		}
+	}
+	s.started[operation+"/"+id] = now
+}
+
+// finish records operation if it was started by this service and is not recorded yet
+func (s *instrumentedBackupService) finish(operation, id string, success bool) {
+	s.mutex.Lock()
+	start, found := s.started[operation+"/"+id]
+	delete(s.started, operation+"/"+id)
+	s.mutex.Unlock()
+	if found {
+		s.metrics.backupFinished(operation, success, time.Since(start))
+	}
+}
+
+func (s *instrumentedBackupService) CollectBackup(ctx context.Context, logicalDatabases []string, keepFromRequest string, allowEviction bool) dto.DatabaseAdapterBaseTrack {
+	track := s.BackupAdministrationService.CollectBackup(ctx, logicalDatabases, keepFromRequest, allowEviction)
+	s.startTrack(backupOperation, track)
+	return track
+}
+
+func (s *instrumentedBackupService) TrackBackup(ctx context.Context, trackId string) (dto.DatabaseAdapterBaseTrack, bool) {
+	track, found := s.BackupAdministrationService.TrackBackup(ctx, trackId)
+	if found {
+		s.finishTrack(backupOperation, track)
+	}
+	return track, found
+}
+
+func (s *instrumentedBackupService) RestoreBackup(ctx context.Context, backupId string, logicalDatabases []dto.DbInfo, regenerateNames, oldNameFormat bool) (*dto.DatabaseAdapterRestoreTrack, error) {
+	track, err := s.BackupAdministrationService.RestoreBackup(ctx, backupId, logicalDatabases, regenerateNames, oldNameFormat)
+	if err != nil {
+		s.metrics.backupFinished(restoreOperation, false, -1)
+	} else if track != nil {
+		s.startTrack(restoreOperation, track.DatabaseAdapterBaseTrack)
+	}
+	return track, err
+}
+
+func (s *instrumentedBackupService) TrackRestore(ctx context.Context, trackId string) (dto.DatabaseAdapterRestoreTrack, bool) {
+	track, found := s.BackupAdministrationService.TrackRestore(ctx, trackId)
+	if found {
+		s.finishTrack(restoreOperation, track.DatabaseAdapterBaseTrack)
+	}
+	return track, found
+}
+
+func (s *instrumentedBackupService) startTrack(operation string, track dto.DatabaseAdapterBaseTrack) {
+	switch track.Status {
+	case dto.FailTrackStatus:
+		s.metrics.backupFinished(operation, false, -1)
+	case dto.SuccessTrackStatus:
+		// duration is unknown, operation was finished before the track was returned
+		s.metrics.backupFinished(operation, true, -1)
+	default:
+		s.start(operation, track.TrackId)
+	}
+}
+
+func (s *instrumentedBackupService) finishTrack(operation string, track dto.DatabaseAdapterBaseTrack) {
+	if track.Status == dto.SuccessTrackStatus || track.Status == dto.FailTrackStatus {
+		s.finish(operation, track.TrackId, track.Status == dto.SuccessTrackStatus)
+	}
+}
+
+func (s *instrumentedBackupService) CollectBackupV2(ctx context.Context, storageName, blobPath string, databaseNames []string) (*dto.BackupResponse, bool) {
+	response, ok := s.BackupAdministrationService.CollectBackupV2(ctx, storageName, blobPath, databaseNames)
+	if !ok || response == nil {
+		s.metrics.backupFinished(backupOperation, false, -1)
+	} else {
+		s.startV2(backupOperation, response.BackupId, response.Status)
+	}
+	return response, ok
+}
+
+func (s *instrumentedBackupService) TrackBackupV2(ctx context.Context, backupId, blobPath string) (*dto.BackupResponse, bool) {
+	response, ok := s.BackupAdministrationService.TrackBackupV2(ctx, backupId, blobPath)
+	if ok && response != nil {
+		s.finishV2(backupOperation, backupId, response.Status)
+	}
+	return response, ok
+}
+
+func (s *instrumentedBackupService) RestoreBackupV2(ctx context.Context, backupId string, restoreRequest dto.CreateRestoreRequest, dryRun bool) (*dto.RestoreResponse, bool) {
+	response, ok := s.BackupAdministrationService.RestoreBackupV2(ctx, backupId, restoreRequest, dryRun)
+	if dryRun {
+		return response, ok
+	}
+	if !ok || response == nil {
+		s.metrics.backupFinished(restoreOperation, false, -1)
+	} else {
+		s.startV2(restoreOperation, response.RestoreId, response.Status)
+	}
+	return response, ok
+}
+
+func (s *instrumentedBackupService) TrackRestoreV2(ctx context.Context, restoreId, blobPath string) (*dto.RestoreResponse, bool) {
+	response, ok := s.BackupAdministrationService.TrackRestoreV2(ctx, restoreId, blobPath)
+	if ok && response != nil {
+		s.finishV2(restoreOperation, restoreId, response.Status)
+	}
+	return response, ok
+}
+
+func (s *instrumentedBackupService) startV2(operation, id string, status dto.BackupRestoreStatus) {
+	switch status {
+	case dto.FailedStatus:
+		s.metrics.backupFinished(operation, false, -1)
+	case dto.CompletedStatus:
+		s.metrics.backupFinished(operation, true, -1)
+	default:
+		s.start(operation, id)
+	}
+}
+
+func (s *instrumentedBackupService) finishV2(operation, id string, status dto.BackupRestoreStatus) {
+	if status == dto.CompletedStatus || status == dto.FailedStatus {
+		s.finish(operation, id, status == dto.CompletedStatus)
+	}
+}
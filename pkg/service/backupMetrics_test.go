@@ -0,0 +1,96 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackupService returns tracks with the status of the test
+type testBackupService struct {
+	BackupAdministrationService
+	status dto.DatabaseAdapterBackupAdapterTrackStatus
+}
+
+func (s *testBackupService) CollectBackup(context.Context, []string, string, bool) dto.DatabaseAdapterBaseTrack {
+	return dto.DatabaseAdapterBaseTrack{TrackId: "backup", Status: s.status}
+}
+
+func (s *testBackupService) TrackBackup(_ context.Context, trackId string) (dto.DatabaseAdapterBaseTrack, bool) {
+	return dto.DatabaseAdapterBaseTrack{TrackId: trackId, Status: s.status}, true
+}
+
+func TestInstrumentBackupService(t *testing.T) {
+	newService := func(t *testing.T, status dto.DatabaseAdapterBackupAdapterTrackStatus) (*instrumentedBackupService, *prometheus.Registry) {
+		registry := prometheus.NewRegistry()
+		metrics, err := NewMetrics(registry, "test")
+		require.NoError(t, err)
+		return InstrumentBackupService(&testBackupService{status: status}, metrics).(*instrumentedBackupService), registry
+	}
+
+	t.Run("Duration is recorded when track is finished", func(t *testing.T) {
+		backupService, registry := newService(t, dto.ProceedingTrackStatus)
+		backupService.CollectBackup(context.Background(), nil, "", false)
+		backupService.BackupAdministrationService.(*testBackupService).status = dto.SuccessTrackStatus
+		backupService.TrackBackup(context.Background(), "backup")
+		backupService.TrackBackup(context.Background(), "backup")
+
+		assert.Equal(t, 1.0, testutil.ToFloat64(backupService.metrics.backupOperations.WithLabelValues("test", backupOperation, successResult)))
+		count, err := testutil.GatherAndCount(registry, "dbaas_adapter_backup_duration_seconds")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+	t.Run("Duration of track finished at start is not recorded", func(t *testing.T) {
+		backupService, registry := newService(t, dto.SuccessTrackStatus)
+		backupService.CollectBackup(context.Background(), nil, "", false)
+
+		assert.Equal(t, 1.0, testutil.ToFloat64(backupService.metrics.backupOperations.WithLabelValues("test", backupOperation, successResult)))
+		count, err := testutil.GatherAndCount(registry, "dbaas_adapter_backup_duration_seconds")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+	t.Run("Expired operations are forgotten", func(t *testing.T) {
+		backupService, _ := newService(t, dto.ProceedingTrackStatus)
+		backupService.started["backup/expired"] = time.Now().Add(-startedOperationTTL - time.Minute)
+
+		backupService.CollectBackup(context.Background(), nil, "", false)
+
+		assert.NotContains(t, backupService.started, "backup/expired")
+		assert.Contains(t, backupService.started, "backup/backup")
+	})
+}
+
+func TestNewMetrics_RegistrationError(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "databases_created_total",
+		Help:      "Counter without labels.",
+	}))
+
+	_, err := NewMetrics(registry, "test")
+
+	assert.ErrorContains(t, err, "cannot register metrics")
+	_, err = NewMetrics(prometheus.NewRegistry(), "test")
+	assert.NoError(t, err)
+}
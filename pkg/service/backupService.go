@@ -91,16 +91,16 @@ func (d DefaultBackupAdministrationImpl) SendBackupRequest(ctx context.Context,
 	if method == http.MethodPost {
 		codedBody, errm := json.Marshal(bodyStruct)
 		utils.PanicError(errm, logger.Error, "Failed to marshal request body to send to backup")
-		req, err = http.NewRequest(method, d.backupAddress+uri, bytes.NewReader(codedBody))
+		req, err = http.NewRequestWithContext(ctx, method, d.backupAddress+uri, bytes.NewReader(codedBody))
 	} else {
-		req, err = http.NewRequest(method, d.backupAddress+uri, nil)
+		req, err = http.NewRequestWithContext(ctx, method, d.backupAddress+uri, nil)
 	}
 	utils.PanicError(err, logger.Error, "Failed to prepare request to send to backup")
 	if method == http.MethodPost {
 		req.Header.Set("Content-Type", "application/json")
 	}
 	req.SetBasicAuth(d.backupApiUser, d.backupApiPass)
-	res, errs := d.client.Do(req)
+	res, errs := utils.DoTraced(d.client, req)
 	utils.PanicError(errs, logger.Error, "Failed to send request to backup")
 	logger.Info(fmt.Sprintf("Received response with status: %s", res.Status))
 
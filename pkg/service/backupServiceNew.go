@@ -47,7 +47,7 @@ func (d DefaultBackupAdministrationImpl) CollectBackupV2(ctx context.Context, st
 		return nil, false
 	}
 	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
-		utils.PanicError(fmt.Errorf("failed to create backup: %s", string(body)), logger.Error, "Failed to create backup")
+		utils.PanicError(fmt.Errorf("failed to create backup: %s", utils.RedactSecrets(string(body))), logger.Error, "Failed to create backup")
 	}
 
 	databases := make([]dto.LogicalDatabaseBackup, 0, len(databaseNames))
@@ -87,8 +87,8 @@ func (d DefaultBackupAdministrationImpl) TrackBackupV2(ctx context.Context, back
 	q := u.Query()
 	q.Set("blobPath", blobPath)
 	u.RawQuery = q.Encode()
-	req, _ := http.NewRequest(http.MethodGet, u.String(), nil)
-	res, err := d.client.Do(req)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	res, err := utils.DoTraced(d.client, req)
 	if err != nil {
 		utils.PanicError(err, logger.Error, "Failed to get backup status")
 	}
@@ -104,7 +104,7 @@ func (d DefaultBackupAdministrationImpl) TrackBackupV2(ctx context.Context, back
 	}
 
 	if res.StatusCode != http.StatusOK {
-		utils.PanicError(fmt.Errorf("failed to get backup status: %s", string(body)), logger.Error, "Failed to get backup status")
+		utils.PanicError(fmt.Errorf("failed to get backup status: %s", utils.RedactSecrets(string(body))), logger.Error, "Failed to get backup status")
 	}
 
 	backupResponse := &dto.BackupResponse{
@@ -125,12 +125,12 @@ func (d DefaultBackupAdministrationImpl) EvictBackupV2(ctx context.Context, back
 	q := u.Query()
 	q.Set("blobPath", blobPath)
 	u.RawQuery = q.Encode()
-	req, err := http.NewRequest(http.MethodDelete, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
 	if err != nil {
 		utils.PanicError(err, logger.Error, "Failed to create request")
 	}
 
-	res, err := d.client.Do(req)
+	res, err := utils.DoTraced(d.client, req)
 	if err != nil {
 		utils.PanicError(err, logger.Error, "Failed to evict backup")
 	}
@@ -145,7 +145,7 @@ func (d DefaultBackupAdministrationImpl) EvictBackupV2(ctx context.Context, back
 	}
 
 	if res.StatusCode != http.StatusOK {
-		utils.PanicError(fmt.Errorf("failed to evict backup: %s", string(body)), logger.Error, "Failed to evict backup")
+		utils.PanicError(fmt.Errorf("failed to evict backup: %s", utils.RedactSecrets(string(body))), logger.Error, "Failed to evict backup")
 	}
 
 	return true
@@ -204,7 +204,7 @@ func (d DefaultBackupAdministrationImpl) RestoreBackupV2(ctx context.Context, ba
 		return nil, false
 	}
 	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
-		utils.PanicError(fmt.Errorf("failed to create restore: %s", string(body)), logger.Error, "Failed to create restore")
+		utils.PanicError(fmt.Errorf("failed to create restore: %s", utils.RedactSecrets(string(body))), logger.Error, "Failed to create restore")
 	}
 
 	restoreResponse := &dto.RestoreResponse{
@@ -230,8 +230,8 @@ func (d DefaultBackupAdministrationImpl) TrackRestoreV2(ctx context.Context, res
 	q := u.Query()
 	q.Set("blobPath", blobPath)
 	u.RawQuery = q.Encode()
-	req, _ := http.NewRequest(http.MethodGet, u.String(), nil)
-	res, err := d.client.Do(req)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	res, err := utils.DoTraced(d.client, req)
 	if err != nil {
 		utils.PanicError(err, logger.Error, "Failed to get restore status")
 	}
@@ -247,7 +247,7 @@ func (d DefaultBackupAdministrationImpl) TrackRestoreV2(ctx context.Context, res
 	}
 
 	if res.StatusCode != http.StatusOK {
-		utils.PanicError(fmt.Errorf("failed to get restore status: %s", string(body)), logger.Error, "Failed to get restore status")
+		utils.PanicError(fmt.Errorf("failed to get restore status: %s", utils.RedactSecrets(string(body))), logger.Error, "Failed to get restore status")
 	}
 
 	restoreResponse := &dto.RestoreResponse{
@@ -269,12 +269,12 @@ func (d DefaultBackupAdministrationImpl) EvictRestoreV2(ctx context.Context, res
 	q := u.Query()
 	q.Set("blobPath", blobPath)
 	u.RawQuery = q.Encode()
-	req, err := http.NewRequest(http.MethodDelete, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
 	if err != nil {
 		utils.PanicError(err, logger.Error, "Failed to create request")
 	}
 
-	res, err := d.client.Do(req)
+	res, err := utils.DoTraced(d.client, req)
 	if err != nil {
 		utils.PanicError(err, logger.Error, "Failed to evict restore")
 	}
@@ -289,7 +289,7 @@ func (d DefaultBackupAdministrationImpl) EvictRestoreV2(ctx context.Context, res
 	}
 
 	if res.StatusCode != http.StatusOK {
-		utils.PanicError(fmt.Errorf("failed to evict restore: %s", string(body)), logger.Error, "Failed to evict restore")
+		utils.PanicError(fmt.Errorf("failed to evict restore: %s", utils.RedactSecrets(string(body))), logger.Error, "Failed to evict restore")
 	}
 
 	return true
@@ -0,0 +1,71 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+)
+
+// ErrorKind classifies errors of services, adapter API responds with status of the kind
+type ErrorKind string
+
+const (
+	InternalError   = ErrorKind("internal")
+	InvalidArgument = ErrorKind("invalid-argument")
+	NotFound        = ErrorKind("not-found")
+	Conflict        = ErrorKind("conflict")
+	NotSupported    = ErrorKind("not-supported")
+	Unavailable     = ErrorKind("unavailable")
+)
+
+// Error is a typed error of services, its code is responded in problem details.
+// Wrapping errors keep the kind and the code, e.g. fmt.Errorf("%w: user %s", ErrUserNotInVault, user).
+type Error struct {
+	Kind      ErrorKind
+	Code      dto.ErrorCode
+	Message   string
+	Retryable bool
+	// Err is the cause of the error
+	Err error
+}
+
+// NewError creates typed error of the kind
+func NewError(kind ErrorKind, code dto.ErrorCode, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, Retryable: kind == Unavailable}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WrapError classifies err by the kind and the code, errors classified already are returned as is
+func WrapError(err error, kind ErrorKind, code dto.ErrorCode) error {
+	if err == nil {
+		return nil
+	}
+	var typed *Error
+	if errors.As(err, &typed) {
+		return err
+	}
+	wrapped := NewError(kind, code, err.Error())
+	wrapped.Err = err
+	return wrapped
+}
@@ -0,0 +1,188 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "dbaas_adapter"
+	successResult    = "success"
+	failureResult    = "failure"
+)
+
+// Metrics are metrics of database lifecycle operations. They are shared by administration, registration
+// and backup services of the adapter, see WithMetrics, WithRegistrationMetrics and InstrumentBackupService.
+type Metrics struct {
+	// engine is the value of engine label, e.g. "postgresql"
+	engine string
+
+	databasesCreated     *prometheus.CounterVec
+	databaseCreateTime   *prometheus.HistogramVec
+	resourcesDropped     *prometheus.CounterVec
+	vaultRoleOperations  *prometheus.CounterVec
+	registrationAttempts *prometheus.CounterVec
+	registrationSuccess  *prometheus.GaugeVec
+	additionalRoles      *prometheus.CounterVec
+	backupOperations     *prometheus.CounterVec
+	backupTime           *prometheus.HistogramVec
+}
+
+// NewMetrics registers metrics of the engine in the registerer. Metrics are not exported if registerer is nil.
+// Metrics already registered in the registerer by other physical database of the adapter are reused,
+// other registration errors, e.g. metric with the same name and different labels, are returned.
+func NewMetrics(registerer prometheus.Registerer, engine string) (*Metrics, error) {
+	metrics := &Metrics{
+		engine: engine,
+		databasesCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "databases_created_total",
+			Help:      "Number of logical database creations by result.",
+		}, []string{"engine", "result"}),
+		databaseCreateTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "database_create_duration_seconds",
+			Help:      "Duration of logical database creation including vault roles.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"engine", "result"}),
+		resourcesDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "resources_dropped_total",
+			Help:      "Number of dropped resources by kind and result, e.g. databases and users.",
+		}, []string{"engine", "kind", "result"}),
+		vaultRoleOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "vault_role_operations_total",
+			Help:      "Number of vault role operations by operation and result.",
+		}, []string{"engine", "operation", "result"}),
+		registrationAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "registration_attempts_total",
+			Help:      "Number of physical database registration requests to dbaas aggregator by result.",
+		}, []string{"engine", "result"}),
+		registrationSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "registration_last_success_timestamp_seconds",
+			Help:      "Unix time of the last successful physical database registration.",
+		}, []string{"engine"}),
+		additionalRoles: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "additional_roles_processed_total",
+			Help:      "Number of additional roles requested by dbaas aggregator and processed by result.",
+		}, []string{"engine", "result"}),
+		backupOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "backup_operations_total",
+			Help:      "Number of finished backups and restores by result.",
+		}, []string{"engine", "operation", "result"}),
+		backupTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "backup_duration_seconds",
+			Help:      "Duration of backups and restores from request to completion.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+		}, []string{"engine", "operation", "result"}),
+	}
+	if registerer == nil {
+		return metrics, nil
+	}
+	var errs []error
+	metrics.databasesCreated = register(registerer, metrics.databasesCreated, &errs)
+	metrics.databaseCreateTime = register(registerer, metrics.databaseCreateTime, &errs)
+	metrics.resourcesDropped = register(registerer, metrics.resourcesDropped, &errs)
+	metrics.vaultRoleOperations = register(registerer, metrics.vaultRoleOperations, &errs)
+	metrics.registrationAttempts = register(registerer, metrics.registrationAttempts, &errs)
+	metrics.registrationSuccess = register(registerer, metrics.registrationSuccess, &errs)
+	metrics.additionalRoles = register(registerer, metrics.additionalRoles, &errs)
+	metrics.backupOperations = register(registerer, metrics.backupOperations, &errs)
+	metrics.backupTime = register(registerer, metrics.backupTime, &errs)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("cannot register metrics: %w", errors.Join(errs...))
+	}
+	return metrics, nil
+}
+
+// register returns collector already registered with the same description if there is one,
+// other registration errors are appended to errs
+func register[T prometheus.Collector](registerer prometheus.Registerer, collector T, errs *[]error) T {
+	if err := registerer.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		*errs = append(*errs, err)
+	}
+	return collector
+}
+
+// unregisteredMetrics is used by services created without metrics
+func unregisteredMetrics() *Metrics {
+	metrics, _ := NewMetrics(nil, "")
+	return metrics
+}
+
+func resultOf(err error) string {
+	if err != nil {
+		return failureResult
+	}
+	return successResult
+}
+
+func (m *Metrics) databaseCreated(start time.Time, err error) {
+	m.databasesCreated.WithLabelValues(m.engine, resultOf(err)).Inc()
+	m.databaseCreateTime.WithLabelValues(m.engine, resultOf(err)).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) resourceDropped(kind string, failed bool) {
+	result := successResult
+	if failed {
+		result = failureResult
+	}
+	m.resourcesDropped.WithLabelValues(m.engine, kind, result).Inc()
+}
+
+func (m *Metrics) vaultRoleOperation(operation string, err error) {
+	m.vaultRoleOperations.WithLabelValues(m.engine, operation, resultOf(err)).Inc()
+}
+
+func (m *Metrics) registrationAttempt(err error) {
+	m.registrationAttempts.WithLabelValues(m.engine, resultOf(err)).Inc()
+	if err == nil {
+		m.registrationSuccess.WithLabelValues(m.engine).SetToCurrentTime()
+	}
+}
+
+func (m *Metrics) additionalRolesProcessed(succeeded, failed int) {
+	m.additionalRoles.WithLabelValues(m.engine, successResult).Add(float64(succeeded))
+	m.additionalRoles.WithLabelValues(m.engine, failureResult).Add(float64(failed))
+}
+
+// backupFinished records outcome of backup or restore, duration is not recorded if it is negative
+func (m *Metrics) backupFinished(operation string, success bool, duration time.Duration) {
+	result := successResult
+	if !success {
+		result = failureResult
+	}
+	m.backupOperations.WithLabelValues(m.engine, operation, result).Inc()
+	if duration >= 0 {
+		m.backupTime.WithLabelValues(m.engine, operation, result).Observe(duration.Seconds())
+	}
+}
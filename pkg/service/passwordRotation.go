@@ -0,0 +1,373 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// passwordRotationPeriodKey is the metadata key of the database with rotation period, e.g. "720h"
+	passwordRotationPeriodKey = "passwordRotationPeriod"
+	// passwordRotationsKey is the metadata key with the last rotation time of every user
+	passwordRotationsKey       = "passwordRotations"
+	maxPasswordRotationRecords = 1000
+	// maxMetadataUpdateAttempts limits attempts to save rotation times when metadata is changed concurrently
+	maxMetadataUpdateAttempts = 5
+)
+
+// PasswordRotator is an optional interface of DbAdministration. It is required for password rotation of users.
+type PasswordRotator interface {
+	// RotatePassword sets new password of the user and returns connection properties with the new password
+	RotatePassword(ctx context.Context, dbName, userName, password string) (dto.ConnectionProperties, error)
+}
+
+// MetadataCompareAndSetter is an optional interface of DbAdministration. If it is implemented, password rotation
+// times are saved to metadata only if it has not been changed since it was read, so metadata updated by
+// dbaas aggregator at the same time is not overwritten.
+type MetadataCompareAndSetter interface {
+	// CompareAndSetMetadata replaces metadata of the database with newMetadata if it is equal to expected,
+	// it returns false if metadata has been changed.
+	CompareAndSetMetadata(ctx context.Context, logicalDatabase string, expected, newMetadata map[string]interface{}) (bool, error)
+}
+
+// ConnectionPropertiesPublisher sends connection properties changed by the adapter to dbaas aggregator,
+// it is implemented by dbaas.Client
+type ConnectionPropertiesPublisher interface {
+	UpdateConnectionProperties(namespace, dbType string, data dto.UpdateConnectionPropertiesRequest) error
+}
+
+type connectionPropertiesPublisher struct {
+	publisher          ConnectionPropertiesPublisher
+	dbType             string
+	physicalDatabaseId string
+}
+
+func (p *connectionPropertiesPublisher) publish(metadata map[string]interface{}, connectionProperties dto.ConnectionProperties) error {
+	if err := validateSettingMetadata(metadata); err != nil {
+		return err
+	}
+	classifier := metadata["classifier"].(map[string]interface{})
+	return p.publisher.UpdateConnectionProperties(classifier["namespace"].(string), p.dbType, dto.UpdateConnectionPropertiesRequest{
+		Classifier:           classifier,
+		ConnectionProperties: connectionProperties,
+		PhysicalDatabaseId:   p.physicalDatabaseId,
+	})
+}
+
+type passwordRotation struct {
+	mutex sync.Mutex
+	// records keeps the latest rotation audit records
+	records []dto.PasswordRotationRecord
+	// checkInterval is the interval of rotation policy checks, policy is disabled if it is 0
+	checkInterval time.Duration
+	// publisher sends passwords rotated by the policy to dbaas aggregator
+	publisher *connectionPropertiesPublisher
+	// pending are connection properties of users which passwords are rotated, but not accepted by dbaas aggregator.
+	// The same passwords are sent again by the next check. They are kept only in memory, so after restart
+	// the users are rotated again because their rotation times are not updated.
+	pending   map[string]dto.ConnectionProperties
+	startOnce sync.Once
+}
+
+func newPasswordRotation(checkInterval time.Duration, publisher *connectionPropertiesPublisher) *passwordRotation {
+	return &passwordRotation{
+		checkInterval: checkInterval,
+		publisher:     publisher,
+	}
+}
+
+func pendingPasswordKey(dbName, userName string) string {
+	return dbName + "/" + userName
+}
+
+func (rotation *passwordRotation) pendingPassword(dbName, userName string) (dto.ConnectionProperties, bool) {
+	rotation.mutex.Lock()
+	defer rotation.mutex.Unlock()
+	connectionProperties, found := rotation.pending[pendingPasswordKey(dbName, userName)]
+	return connectionProperties, found
+}
+
+func (rotation *passwordRotation) setPendingPassword(dbName, userName string, connectionProperties dto.ConnectionProperties) {
+	rotation.mutex.Lock()
+	defer rotation.mutex.Unlock()
+	if connectionProperties == nil {
+		delete(rotation.pending, pendingPasswordKey(dbName, userName))
+		return
+	}
+	if rotation.pending == nil {
+		rotation.pending = make(map[string]dto.ConnectionProperties)
+	}
+	rotation.pending[pendingPasswordKey(dbName, userName)] = connectionProperties
+}
+
+func (rotation *passwordRotation) audit(logger *zap.Logger, record dto.PasswordRotationRecord) {
+	logger.Info("Password rotation audit",
+		zap.String("dbName", record.DbName),
+		zap.String("userName", record.UserName),
+		zap.String("trigger", string(record.Trigger)),
+		zap.Bool("success", record.Success),
+		zap.String("error", record.ErrorMessage))
+	rotation.mutex.Lock()
+	defer rotation.mutex.Unlock()
+	rotation.records = append(rotation.records, record)
+	if len(rotation.records) > maxPasswordRotationRecords {
+		rotation.records = rotation.records[len(rotation.records)-maxPasswordRotationRecords:]
+	}
+}
+
+// RotatePassword sets new generated password of the user and returns the user with new connection properties.
+// Users which passwords are stored in vault are not rotated by the adapter.
+func (adminService *CoreAdministrationService) RotatePassword(ctx context.Context, dbName, userName string) (*dto.CreatedUser, error) {
+	if _, ok := adminService.dbAdmImpl.(PasswordRotator); !ok {
+		return nil, ErrNotSupported
+	}
+	if !adminService.hasUser(ctx, dbName, userName) {
+		return nil, fmt.Errorf("%w: user %s of database %s", ErrUserNotFound, userName, dbName)
+	}
+	metadata := adminService.dbAdm.GetMetadata(ctx, dbName)
+	if adminService.isManagedByVault(metadata, userName) {
+		return nil, fmt.Errorf("%w: user %s of database %s", ErrPasswordManagedByVault, userName, dbName)
+	}
+	connectionProperties, err := adminService.rotatePassword(ctx, dbName, userName, dto.PasswordRotationManualTrigger)
+	if err != nil {
+		return nil, err
+	}
+	// the caller gets the new password, so pending password of the policy is outdated
+	adminService.passwordRotation.setPendingPassword(dbName, userName, nil)
+	if _, hasPolicy := metadata[passwordRotationPeriodKey]; hasPolicy {
+		rotated := map[string]time.Time{strings.Clone(userName): time.Now()}
+		if err := adminService.saveLastPasswordRotations(ctx, dbName, rotated); err != nil {
+			adminService.logger.Warn(fmt.Sprintf("Rotation time of user %s of database %s is not saved: %v", userName, dbName, err))
+		}
+	}
+	return &dto.CreatedUser{Name: userName, ConnectionProperties: connectionProperties}, nil
+}
+
+// GetPasswordRotationRecords returns audit records of password rotations of the database user
+func (adminService *CoreAdministrationService) GetPasswordRotationRecords(dbName, userName string) []dto.PasswordRotationRecord {
+	rotation := adminService.passwordRotation
+	rotation.mutex.Lock()
+	defer rotation.mutex.Unlock()
+	result := make([]dto.PasswordRotationRecord, 0)
+	for _, record := range rotation.records {
+		if record.DbName == dbName && record.UserName == userName {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+func (adminService *CoreAdministrationService) rotatePassword(ctx context.Context, dbName, userName string, trigger dto.PasswordRotationTrigger) (connectionProperties dto.ConnectionProperties, err error) {
+	logger := utils.AddLoggerContext(adminService.logger, ctx)
+	// names may refer to request buffers, so they are copied to outlive the request in audit records
+	record := dto.PasswordRotationRecord{DbName: strings.Clone(dbName), UserName: strings.Clone(userName), Trigger: trigger}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+		record.Time = time.Now().UTC().Format(time.RFC3339)
+		record.Success = err == nil
+		if err != nil {
+			record.ErrorMessage = err.Error()
+		}
+		adminService.passwordRotation.audit(logger, record)
+	}()
+
+	password, err := utils.GeneratePassword(utils.DefaultPasswordLength)
+	if err != nil {
+		return nil, err
+	}
+	return traceRotatePassword(ctx, adminService.dbAdmImpl.(PasswordRotator), dbName, userName, password)
+}
+
+// databaseUsers returns names of users of the database
+func (adminService *CoreAdministrationService) databaseUsers(ctx context.Context, dbName string) []string {
+	var users []string
+	described := adminService.dbAdm.DescribeDatabases(ctx, []string{dbName}, true, false)
+	for _, resource := range described[dbName].Resources {
+		if resource.Kind == userResourceKind {
+			users = append(users, strings.TrimPrefix(resource.Name, "admin:"))
+		}
+	}
+	return users
+}
+
+func (adminService *CoreAdministrationService) hasUser(ctx context.Context, dbName, userName string) bool {
+	return slices.Contains(adminService.databaseUsers(ctx, dbName), userName)
+}
+
+func (adminService *CoreAdministrationService) isManagedByVault(metadata map[string]interface{}, userName string) bool {
+	if !adminService.isVaultEnabled {
+		return false
+	}
+	_, _, found := adminService.findVaultRole(metadata, userName)
+	return found
+}
+
+// startPasswordRotationPolicy periodically rotates passwords of users of the databases which metadata
+// contains rotation period until ctx is done. It is enabled by WithPasswordRotationCheckInterval option
+// and requires WithConnectionPropertiesPublisher, so rotated passwords are sent to dbaas aggregator.
+func (adminService *CoreAdministrationService) startPasswordRotationPolicy(ctx context.Context) {
+	rotation := adminService.passwordRotation
+	if rotation.checkInterval <= 0 {
+		return
+	}
+	if _, ok := adminService.dbAdmImpl.(PasswordRotator); !ok {
+		adminService.logger.Warn("Password rotation policy is enabled, but adapter does not support password rotation")
+		return
+	}
+	if rotation.publisher == nil {
+		adminService.logger.Warn("Password rotation policy is enabled, but rotated passwords cannot be sent to dbaas aggregator")
+		return
+	}
+	rotation.startOnce.Do(func() {
+		adminService.logger.Info(fmt.Sprintf("Password rotation policy is checked every %v", rotation.checkInterval))
+		go func() {
+			ticker := time.NewTicker(rotation.checkInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					adminService.logger.Info("Password rotation policy is stopped")
+					return
+				case <-ticker.C:
+					adminService.applyPasswordRotationPolicy(ctx)
+				}
+			}
+		}()
+	})
+}
+
+func (adminService *CoreAdministrationService) applyPasswordRotationPolicy(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			adminService.logger.Error(fmt.Sprintf("Panic during password rotation policy check: %v", r))
+		}
+	}()
+	for _, dbName := range adminService.dbAdm.GetDatabases(ctx) {
+		adminService.applyDatabasePasswordRotationPolicy(ctx, dbName)
+	}
+}
+
+func (adminService *CoreAdministrationService) applyDatabasePasswordRotationPolicy(ctx context.Context, dbName string) {
+	metadata := adminService.dbAdm.GetMetadata(ctx, dbName)
+	periodValue, ok := metadata[passwordRotationPeriodKey].(string)
+	if !ok {
+		return
+	}
+	period, err := time.ParseDuration(periodValue)
+	if err != nil || period <= 0 {
+		adminService.logger.Warn(fmt.Sprintf("Invalid password rotation period %q of database %s", periodValue, dbName))
+		return
+	}
+
+	now := time.Now()
+	rotated := make(map[string]time.Time)
+	for _, userName := range adminService.databaseUsers(ctx, dbName) {
+		if adminService.isManagedByVault(metadata, userName) {
+			adminService.passwordRotation.setPendingPassword(dbName, userName, nil)
+			continue
+		}
+		connectionProperties, pending := adminService.passwordRotation.pendingPassword(dbName, userName)
+		if !pending {
+			lastRotation, found := getLastPasswordRotation(metadata, userName)
+			if !found {
+				// rotation period starts when the policy is found for the first time
+				rotated[userName] = now
+				continue
+			}
+			if now.Sub(lastRotation) < period {
+				continue
+			}
+			connectionProperties, err = adminService.rotatePassword(ctx, dbName, userName, dto.PasswordRotationPolicyTrigger)
+			if err != nil {
+				continue
+			}
+		}
+		// rotation is done only when dbaas aggregator has the new password, otherwise the same password is sent
+		// again by the next check
+		if err = adminService.passwordRotation.publisher.publish(metadata, connectionProperties); err != nil {
+			adminService.passwordRotation.setPendingPassword(dbName, userName, connectionProperties)
+			adminService.logger.Error(fmt.Sprintf("Password of user %s of database %s is rotated, but dbaas aggregator did not accept it: %v", userName, dbName, err))
+			continue
+		}
+		adminService.passwordRotation.setPendingPassword(dbName, userName, nil)
+		rotated[userName] = now
+	}
+	if len(rotated) == 0 {
+		return
+	}
+	if err = adminService.saveLastPasswordRotations(ctx, dbName, rotated); err != nil {
+		adminService.logger.Warn(fmt.Sprintf("Password rotation times of database %s are not saved: %v", dbName, err))
+	}
+}
+
+// saveLastPasswordRotations sets rotation times of the users in the current metadata of the database.
+// Metadata is compared and set if adapter implements MetadataCompareAndSetter, so changes made by dbaas aggregator
+// since metadata is read are not lost. Otherwise, metadata is read right before it is updated.
+func (adminService *CoreAdministrationService) saveLastPasswordRotations(ctx context.Context, dbName string, rotated map[string]time.Time) error {
+	setter, compareAndSet := adminService.dbAdmImpl.(MetadataCompareAndSetter)
+	for attempt := 0; attempt < maxMetadataUpdateAttempts; attempt++ {
+		current := adminService.dbAdm.GetMetadata(ctx, dbName)
+		if current == nil {
+			return fmt.Errorf("metadata of database %s is not found", dbName)
+		}
+		updated := maps.Clone(current)
+		for userName, rotationTime := range rotated {
+			setLastPasswordRotation(updated, userName, rotationTime)
+		}
+		if !compareAndSet {
+			adminService.dbAdm.UpdateMetadata(ctx, updated, dbName)
+			return nil
+		}
+		swapped, err := setter.CompareAndSetMetadata(ctx, dbName, current, updated)
+		if err != nil {
+			return err
+		}
+		if swapped {
+			return nil
+		}
+	}
+	return fmt.Errorf("metadata of database %s is changed concurrently", dbName)
+}
+
+func getLastPasswordRotation(metadata map[string]interface{}, userName string) (time.Time, bool) {
+	rotations, _ := metadata[passwordRotationsKey].(map[string]interface{})
+	value, _ := rotations[userName].(string)
+	lastRotation, err := time.Parse(time.RFC3339, value)
+	return lastRotation, err == nil
+}
+
+// setLastPasswordRotation sets rotation time of the user in copy of rotations, so metadata read before is not changed
+func setLastPasswordRotation(metadata map[string]interface{}, userName string, rotationTime time.Time) {
+	rotations, _ := metadata[passwordRotationsKey].(map[string]interface{})
+	rotations = maps.Clone(rotations)
+	if rotations == nil {
+		rotations = make(map[string]interface{})
+	}
+	rotations[userName] = rotationTime.UTC().Format(time.RFC3339)
+	metadata[passwordRotationsKey] = rotations
+}
@@ -0,0 +1,149 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPublisher records connection properties sent to dbaas aggregator, they are rejected if err is set
+type testPublisher struct {
+	namespaces []string
+	requests   []dao.UpdateConnectionPropertiesRequest
+	err        error
+}
+
+func (p *testPublisher) UpdateConnectionProperties(namespace, dbType string, data dao.UpdateConnectionPropertiesRequest) error {
+	p.namespaces = append(p.namespaces, namespace+"/"+dbType)
+	p.requests = append(p.requests, data)
+	return p.err
+}
+
+// compareAndSetDbAdministration sets metadata only if it is not changed, beforeSet is called once before
+// the first comparison to change metadata concurrently
+type compareAndSetDbAdministration struct {
+	*testDbAdministration
+	beforeSet func()
+}
+
+func (adm *compareAndSetDbAdministration) CompareAndSetMetadata(_ context.Context, logicalDatabase string, expected, newMetadata map[string]interface{}) (bool, error) {
+	if adm.beforeSet != nil {
+		adm.beforeSet()
+		adm.beforeSet = nil
+	}
+	adm.mutex.Lock()
+	defer adm.mutex.Unlock()
+	if !reflect.DeepEqual(adm.metadata[logicalDatabase], expected) {
+		return false, nil
+	}
+	adm.metadata[logicalDatabase] = newMetadata
+	return true, nil
+}
+
+func TestPasswordRotationPolicy(t *testing.T) {
+	lastRotation := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	newDatabases := func() *testDbAdministration {
+		metadata := testDatabaseMetadata()
+		metadata[passwordRotationPeriodKey] = "1h"
+		metadata[passwordRotationsKey] = map[string]interface{}{"user": lastRotation}
+		return &testDbAdministration{
+			users:    map[string][]string{"db": {"user"}},
+			metadata: map[string]map[string]interface{}{"db": metadata},
+		}
+	}
+
+	t.Run("Rotated password is sent to aggregator", func(t *testing.T) {
+		adm := newDatabases()
+		publisher := &testPublisher{}
+		admin := newTestAdministrationService(adm, WithConnectionPropertiesPublisher(publisher, "postgresql", "core"))
+
+		admin.applyPasswordRotationPolicy(context.Background())
+
+		require.Len(t, publisher.requests, 1)
+		assert.Equal(t, []string{"ns/postgresql"}, publisher.namespaces)
+		assert.Equal(t, "core", publisher.requests[0].PhysicalDatabaseId)
+		assert.Equal(t, adm.passwords["db/user"], publisher.requests[0].ConnectionProperties["password"])
+		rotated, found := getLastPasswordRotation(adm.metadata["db"], "user")
+		assert.True(t, found)
+		assert.WithinDuration(t, time.Now(), rotated, time.Minute)
+	})
+	t.Run("Rotated password is sent again if aggregator rejects it", func(t *testing.T) {
+		adm := newDatabases()
+		publisher := &testPublisher{err: errors.New("aggregator is unavailable")}
+		admin := newTestAdministrationService(adm, WithConnectionPropertiesPublisher(publisher, "postgresql", "core"))
+
+		admin.applyPasswordRotationPolicy(context.Background())
+		admin.applyPasswordRotationPolicy(context.Background())
+
+		require.Len(t, publisher.requests, 2)
+		assert.Len(t, admin.GetPasswordRotationRecords("db", "user"), 1, "password is rotated once")
+		assert.Equal(t, adm.passwords["db/user"], publisher.requests[1].ConnectionProperties["password"])
+		rotations := adm.metadata["db"][passwordRotationsKey].(map[string]interface{})
+		assert.Equal(t, lastRotation, rotations["user"], "rotation is not marked as done")
+
+		publisher.err = nil
+		admin.applyPasswordRotationPolicy(context.Background())
+
+		require.Len(t, publisher.requests, 3)
+		assert.Equal(t, adm.passwords["db/user"], publisher.requests[2].ConnectionProperties["password"])
+		rotated, _ := getLastPasswordRotation(adm.metadata["db"], "user")
+		assert.WithinDuration(t, time.Now(), rotated, time.Minute)
+	})
+	t.Run("Metadata changed concurrently is kept", func(t *testing.T) {
+		adm := &compareAndSetDbAdministration{testDbAdministration: newDatabases()}
+		publisher := &testPublisher{}
+		admin := newTestAdministrationService(adm, WithConnectionPropertiesPublisher(publisher, "postgresql", "core"))
+		// aggregator updates metadata while the password is rotated
+		adm.beforeSet = func() {
+			adm.metadata["db"]["microserviceName"] = "updated"
+		}
+
+		admin.applyPasswordRotationPolicy(context.Background())
+
+		assert.Equal(t, "updated", adm.metadata["db"]["microserviceName"])
+		rotated, _ := getLastPasswordRotation(adm.metadata["db"], "user")
+		assert.WithinDuration(t, time.Now(), rotated, time.Minute)
+	})
+	t.Run("Policy is not started without publisher", func(t *testing.T) {
+		admin := newTestAdministrationService(newDatabases(), WithPasswordRotationCheckInterval(time.Millisecond))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		admin.startPasswordRotationPolicy(ctx)
+		time.Sleep(20 * time.Millisecond)
+
+		assert.Empty(t, admin.GetPasswordRotationRecords("db", "user"))
+	})
+}
+
+func TestRotatePasswordOfUnknownUser(t *testing.T) {
+	admin := newTestAdministrationService(&testDbAdministration{
+		users:    map[string][]string{"db": {"user"}},
+		metadata: map[string]map[string]interface{}{"db": testDatabaseMetadata()},
+	})
+
+	_, err := admin.RotatePassword(context.Background(), "db", "unknown")
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.Empty(t, admin.GetPasswordRotationRecords("db", "unknown"))
+}
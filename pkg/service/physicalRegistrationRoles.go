@@ -17,17 +17,62 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	entity "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/helper"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"go.uber.org/zap"
 )
 
+// rolesCheckpoint contains results of additional roles of the instruction which have been created by adapter.
+// Results are sent to DBaaS again instead of creating the roles twice if DBaaS requests them again,
+// e.g. when adapter has been restarted before DBaaS accepted them.
+type rolesCheckpoint struct {
+	InstructionId string                 `json:"instructionId"`
+	CreatedRoles  map[string]createdRole `json:"createdRoles"`
+}
+
+// createdRole is the result of the role without plaintext passwords, they are kept only in memory by
+// PhysicalDatabaseRegistrationService.createdRoles. Passwords referring vault roles are kept as is.
+type createdRole struct {
+	DbName   string         `json:"dbName,omitempty"`
+	Result   entity.Success `json:"result"`
+	Redacted bool           `json:"redacted,omitempty"`
+}
+
+func redactCreatedRole(success entity.Success) createdRole {
+	role := createdRole{DbName: success.DbName, Result: success}
+	role.Result.ConnectionProperties = make([]entity.ConnectionProperties, 0, len(success.ConnectionProperties))
+	for _, properties := range success.ConnectionProperties {
+		redacted := make(entity.ConnectionProperties, len(properties))
+		for key, value := range properties {
+			if password, ok := value.(string); ok && key == "password" && !strings.HasPrefix(password, utils.VaultPasswordPrefix) {
+				role.Redacted = true
+				continue
+			}
+			redacted[key] = value
+		}
+		role.Result.ConnectionProperties = append(role.Result.ConnectionProperties, redacted)
+	}
+	return role
+}
+
+// newRolesCheckpointStore keeps checkpoint in dir if it is set, so it survives restarts.
+// Otherwise, checkpoint is kept in memory and helps only when registration is retried by the running adapter.
+func newRolesCheckpointStore(logger *zap.Logger, dir string) helper.StateStore {
+	return newStateStore(logger, dir, "additional roles checkpoint")
+}
+
 func (srv *PhysicalDatabaseRegistrationService) registerWithRoles() {
 	srv.mutex.Lock()
 	defer srv.mutex.Unlock()
 	roleProcessingStarted := false
+	var rolesErr error
 	defer func() {
 		if r := recover(); r != nil {
-			srv.Health = entity.PhysicalDatabaseRegistrationHealth{Status: "WARNING"}
+			srv.setHealth("WARNING")
 			if roleProcessingStarted {
 				panic(r)
 			}
@@ -38,39 +83,193 @@ func (srv *PhysicalDatabaseRegistrationService) registerWithRoles() {
 				srv.logger.Info("Aggregator is not healthy")
 				srv.logger.Error(fmt.Sprintf("%v", r))
 			}
+		} else if rolesErr != nil {
+			srv.logger.Error(fmt.Sprintf("Additional roles are not processed completely, they will be processed on the next registration: %v", rolesErr))
+			srv.setHealth("WARNING")
 		} else {
-			srv.Health = entity.PhysicalDatabaseRegistrationHealth{Status: "OK"}
+			srv.setHealth("OK")
 		}
-		srv.status = entity.StatusRun
+		srv.setRunStatus()
 	}()
 
 	resp := srv.sendRegisterRequest()
 	roleProcessingStarted = true
 	if len(resp.Instruction.AdditionalRoles) > 0 {
-		srv.performAdditionalRoles(resp.Instruction)
+		rolesErr = srv.performAdditionalRoles(resp.Instruction)
+	}
+	srv.logger.Info("Registration finished")
+}
+
+// registerWithRolesAndReturnResult performs registration with additional roles processing without crashing on failure.
+// Function returns nil in case of successful registration, error otherwise.
+// Must be called under the srv.mutex.
+func (srv *PhysicalDatabaseRegistrationService) registerWithRolesAndReturnResult() (err error) {
+	defer func() {
+		srv.setRunStatus()
+	}()
+	defer srv.recoverRegistrationResult(&err)
+
+	resp := srv.sendRegisterRequest()
+	if len(resp.Instruction.AdditionalRoles) > 0 {
+		err = srv.performAdditionalRoles(resp.Instruction)
 	}
 	srv.logger.Info("Registration finished")
+	return err
 }
 
-func (srv *PhysicalDatabaseRegistrationService) performAdditionalRoles(instruction entity.Instruction) {
+// performAdditionalRoles creates roles requested by DBaaS and reports result of every role until DBaaS stops
+// sending roles. Every created role is saved to checkpoint, so if DBaaS sends it again, e.g. after adapter restart,
+// its saved result is reported instead of creating the role again.
+// Processing stops with error if DBaaS sends again the role which has failed or if there is nothing new to report.
+func (srv *PhysicalDatabaseRegistrationService) performAdditionalRoles(instruction entity.Instruction) error {
 	additionalRoles := instruction.AdditionalRoles
+	checkpoint := srv.loadRolesCheckpoint(instruction.Id)
+	failedRoles := make(map[string]string)
+	reportedRoles := make(map[string]bool)
 	var err error
-	srv.logger.Info("Start processing additional roles")
+	srv.logger.Info(fmt.Sprintf("Start processing additional roles of instruction %s, %d roles are already created", instruction.Id, len(checkpoint.CreatedRoles)))
 	for len(additionalRoles) > 0 {
-		success, failure := srv.administrationService.CreateRoles(context.Background(), additionalRoles)
-		result := entity.PhysicalDatabaseRoleRequest{
-			Success: success,
-			Failure: failure,
+		var result entity.PhysicalDatabaseRoleRequest
+		pendingRoles := make([]entity.AdditionalRole, 0, len(additionalRoles))
+		for _, role := range additionalRoles {
+			if _, failed := failedRoles[role.Id]; failed {
+				return fmt.Errorf("failed to create additional role %s: %s", role.Id, failedRoles[role.Id])
+			}
+			if created, ok := checkpoint.CreatedRoles[role.Id]; ok {
+				success, restored := srv.restoreCreatedRole(role.Id, created)
+				if !restored {
+					srv.logger.Info(fmt.Sprintf("Password of additional role %s is lost after restart, create the role again", role.Id))
+					srv.dropCreatedRole(created)
+					delete(checkpoint.CreatedRoles, role.Id)
+					srv.saveRolesCheckpoint(checkpoint)
+					pendingRoles = append(pendingRoles, role)
+					continue
+				}
+				if !reportedRoles[role.Id] {
+					srv.logger.Info(fmt.Sprintf("Additional role %s is already created, send its result again", role.Id))
+					result.Success = append(result.Success, success)
+				}
+				continue
+			}
+			pendingRoles = append(pendingRoles, role)
+		}
+		if len(pendingRoles) == 0 && len(result.Success) == 0 {
+			return fmt.Errorf("DBaaS requested again %d additional roles which results have already been sent", len(additionalRoles))
 		}
 
-		additionalRoles, err = srv.client.AdditionalRoles(srv.phydbid, srv.dbName, result, instruction)
-		if failure != nil {
-			panic(fmt.Errorf("Failed to create additional roles. Error message: %s", failure.Message))
+		if len(pendingRoles) > 0 {
+			success, failures := srv.createRoles(pendingRoles, &checkpoint)
+			result.Success = append(result.Success, success...)
+			result.Failures = failures
+		}
+		if len(result.Failures) > 0 {
+			result.Failure = &result.Failures[0]
 		}
+		srv.logger.Info(fmt.Sprintf("Additional roles processed: %d succeeded, %d failed", len(result.Success), len(result.Failures)))
+		srv.metrics.additionalRolesProcessed(len(result.Success), len(result.Failures))
+
+		additionalRoles, err = srv.client.AdditionalRoles(srv.phydbid, srv.dbName, result, instruction)
 		if err != nil {
-			panic(fmt.Errorf("Failed to create additional roles. err: %v", err))
+			return fmt.Errorf("failed to send additional roles result. err: %v", err)
+		}
+
+		for _, success := range result.Success {
+			reportedRoles[success.Id] = true
+		}
+		for _, failure := range result.Failures {
+			failedRoles[failure.Id] = failure.Message
 		}
 	}
+	srv.deleteRolesCheckpoint()
+	if len(failedRoles) > 0 {
+		return fmt.Errorf("failed to create %d additional roles", len(failedRoles))
+	}
+	return nil
+}
+
+// createRoles creates roles and saves every created role to the checkpoint.
+// Roles are created one by one if administration service implements RolesResultsCreator, otherwise in one batch.
+func (srv *PhysicalDatabaseRegistrationService) createRoles(roles []entity.AdditionalRole, checkpoint *rolesCheckpoint) ([]entity.Success, []entity.Failure) {
+	var checkpointMutex sync.Mutex
+	saveCreated := func(success entity.Success) {
+		checkpointMutex.Lock()
+		defer checkpointMutex.Unlock()
+		srv.createdRoles[success.Id] = success
+		checkpoint.CreatedRoles[success.Id] = redactCreatedRole(success)
+		srv.saveRolesCheckpoint(*checkpoint)
+	}
+
+	if creator, ok := srv.administrationService.(RolesResultsCreator); ok {
+		return creator.CreateRolesWithResults(context.Background(), roles, saveCreated)
+	}
+	success, failure := srv.administrationService.CreateRoles(context.Background(), roles)
+	for _, created := range success {
+		saveCreated(created)
+	}
+	if failure != nil {
+		return success, []entity.Failure{*failure}
+	}
+	return success, nil
+}
+
+// restoreCreatedRole returns result of the created role with passwords. They are taken from memory
+// if the role has been created by the running adapter, otherwise only the roles without plaintext passwords are restored.
+func (srv *PhysicalDatabaseRegistrationService) restoreCreatedRole(id string, created createdRole) (entity.Success, bool) {
+	if success, ok := srv.createdRoles[id]; ok {
+		return success, true
+	}
+	success := created.Result
+	success.DbName = created.DbName
+	return success, !created.Redacted
+}
+
+// dropCreatedRole drops users of the role which result cannot be restored, so the role can be created again
+func (srv *PhysicalDatabaseRegistrationService) dropCreatedRole(created createdRole) {
+	userResources := make([]entity.DbResource, 0)
+	for _, resource := range created.Result.Resources {
+		if resource.Kind == userResourceKind {
+			userResources = append(userResources, resource)
+		}
+	}
+	if len(userResources) > 0 {
+		srv.administrationService.DropResources(context.Background(), userResources)
+	}
+}
+
+func (srv *PhysicalDatabaseRegistrationService) rolesCheckpointKey() string {
+	return "additional-roles-" + srv.dbName + "-" + srv.phydbid
+}
+
+func (srv *PhysicalDatabaseRegistrationService) loadRolesCheckpoint(instructionId string) rolesCheckpoint {
+	var checkpoint rolesCheckpoint
+	found, err := srv.rolesCheckpoint.Load(srv.rolesCheckpointKey(), &checkpoint)
+	if err != nil {
+		srv.logger.Warn(fmt.Sprintf("Cannot read additional roles checkpoint, roles are processed from scratch: %v", err))
+	}
+	if srv.createdRoles == nil || srv.createdRolesInstruction != instructionId {
+		srv.createdRoles = make(map[string]entity.Success)
+		srv.createdRolesInstruction = instructionId
+	}
+	if !found || err != nil || checkpoint.InstructionId != instructionId {
+		return rolesCheckpoint{InstructionId: instructionId, CreatedRoles: make(map[string]createdRole)}
+	}
+	if checkpoint.CreatedRoles == nil {
+		checkpoint.CreatedRoles = make(map[string]createdRole)
+	}
+	return checkpoint
+}
+
+func (srv *PhysicalDatabaseRegistrationService) saveRolesCheckpoint(checkpoint rolesCheckpoint) {
+	if err := srv.rolesCheckpoint.Save(srv.rolesCheckpointKey(), checkpoint); err != nil {
+		srv.logger.Warn(fmt.Sprintf("Cannot save additional roles checkpoint: %v", err))
+	}
+}
+
+func (srv *PhysicalDatabaseRegistrationService) deleteRolesCheckpoint() {
+	srv.createdRoles = nil
+	if err := srv.rolesCheckpoint.Delete(srv.rolesCheckpointKey()); err != nil {
+		srv.logger.Warn(fmt.Sprintf("Cannot delete additional roles checkpoint: %v", err))
+	}
 }
 
 func (srv *PhysicalDatabaseRegistrationService) modifyReqParams(request *entity.PhysicalDatabaseRegistrationRequest) {
@@ -91,6 +290,7 @@ func (srv *PhysicalDatabaseRegistrationService) modifyReqParams(request *entity.
 			Features:       srv.administrationService.GetFeatures(),
 			ROHost:         srv.administrationService.GetROHost(),
 		}
-		request.Status = srv.status
 	}
+	// status is sent for every API version, so draining adapter is not used for new databases by any aggregator
+	request.Status = srv.status
 }
@@ -0,0 +1,229 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dbaas"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/helper"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAggregator answers additional roles requests with the next batch of roles and records received results
+type testAggregator struct {
+	mutex    sync.Mutex
+	batches  [][]dao.AdditionalRole
+	received []dao.PhysicalDatabaseRoleRequest
+}
+
+func (a *testAggregator) handler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api-version" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	var request dao.PhysicalDatabaseRoleRequest
+	_ = json.NewDecoder(r.Body).Decode(&request)
+	a.received = append(a.received, request)
+	if len(a.batches) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(a.batches[0])
+	if len(a.batches) > 1 {
+		a.batches = a.batches[1:]
+	}
+}
+
+func newTestRegistrationService(t *testing.T, aggregator *testAggregator, admin CoreAdministrationServiceIface) *PhysicalDatabaseRegistrationService {
+	server := httptest.NewServer(http.HandlerFunc(aggregator.handler))
+	t.Cleanup(server.Close)
+	client, err := dbaas.NewDbaasClient(server.URL, &dao.BasicAuth{Username: "dbaas", Password: "dbaas"}, server.Client())
+	require.NoError(t, err)
+	return &PhysicalDatabaseRegistrationService{
+		dbName:                "postgresql",
+		phydbid:               "core",
+		logger:                utils.GetLogger(false),
+		client:                client,
+		administrationService: admin,
+		rolesCheckpoint:       helper.NewInMemoryStateStore(),
+		metrics:               unregisteredMetrics(),
+	}
+}
+
+func roleIds(success []dao.Success) []string {
+	ids := make([]string, 0, len(success))
+	for _, s := range success {
+		ids = append(ids, s.Id)
+	}
+	return ids
+}
+
+func TestCreateRolesWithResults(t *testing.T) {
+	adm := &testDbAdministration{}
+	admin := newTestAdministrationService(adm)
+	var mutex sync.Mutex
+	var created []string
+
+	success, failures := admin.CreateRolesWithResults(context.Background(), []dao.AdditionalRole{
+		{Id: "role-1", DbName: "db"}, {Id: "failed-role", DbName: "db"}, {Id: "role-2", DbName: "db"},
+	}, func(success dao.Success) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		created = append(created, success.Id)
+	})
+
+	assert.Equal(t, []string{"role-1", "role-2"}, roleIds(success))
+	require.Len(t, failures, 1)
+	assert.Equal(t, "failed-role", failures[0].Id)
+	assert.ElementsMatch(t, []string{"role-1", "role-2"}, created)
+}
+
+func TestCreateRoles_KeepsRolesCreatedAfterFailure(t *testing.T) {
+	adm := &testDbAdministration{}
+	admin := newTestAdministrationService(adm)
+
+	success, failure := admin.CreateRoles(context.Background(), []dao.AdditionalRole{
+		{Id: "role-1", DbName: "db"}, {Id: "failed-role", DbName: "db"}, {Id: "role-2", DbName: "db"},
+	})
+
+	assert.Equal(t, []string{"role-1", "role-2"}, roleIds(success))
+	require.NotNil(t, failure)
+	assert.Equal(t, "failed-role", failure.Id)
+	assert.Empty(t, adm.dropped)
+}
+
+func TestPerformAdditionalRoles_CheckpointWithoutPasswords(t *testing.T) {
+	adm := &testDbAdministration{}
+	aggregator := &testAggregator{batches: [][]dao.AdditionalRole{{{Id: "role-1"}}}}
+	store := helper.NewInMemoryStateStore()
+	srv := newTestRegistrationService(t, aggregator, newTestAdministrationService(adm))
+	srv.rolesCheckpoint = store
+	instruction := dao.Instruction{Id: "instruction", AdditionalRoles: []dao.AdditionalRole{{Id: "role-1"}}}
+
+	assert.Error(t, srv.performAdditionalRoles(instruction))
+
+	var checkpoint rolesCheckpoint
+	found, err := store.Load(srv.rolesCheckpointKey(), &checkpoint)
+	require.NoError(t, err)
+	require.True(t, found)
+	saved, err := json.Marshal(checkpoint)
+	require.NoError(t, err)
+	assert.NotContains(t, string(saved), "secret")
+	assert.Equal(t, "secret", aggregator.received[0].Success[0].ConnectionProperties[0]["password"])
+
+	// adapter is restarted, so the password of created role is lost and the role is created again
+	aggregator.batches = nil
+	restarted := newTestRegistrationService(t, aggregator, newTestAdministrationService(adm))
+	restarted.rolesCheckpoint = store
+
+	assert.NoError(t, restarted.performAdditionalRoles(instruction))
+
+	assert.Equal(t, []string{"role-1-user"}, adm.dropped)
+	assert.Equal(t, []string{"role-1", "role-1"}, adm.created)
+	require.Len(t, aggregator.received, 2)
+	assert.Equal(t, "secret", aggregator.received[1].Success[0].ConnectionProperties[0]["password"])
+}
+
+func TestPerformAdditionalRoles_ResumesFromCheckpoint(t *testing.T) {
+	adm := &testDbAdministration{}
+	aggregator := &testAggregator{}
+	srv := newTestRegistrationService(t, aggregator, newTestAdministrationService(adm))
+	instruction := dao.Instruction{Id: "instruction", AdditionalRoles: []dao.AdditionalRole{{Id: "role-1"}, {Id: "role-2"}}}
+	require.NoError(t, srv.rolesCheckpoint.Save(srv.rolesCheckpointKey(), rolesCheckpoint{
+		InstructionId: "instruction",
+		CreatedRoles:  map[string]createdRole{"role-1": {DbName: "db", Result: dao.Success{Id: "role-1"}}},
+	}))
+
+	assert.NoError(t, srv.performAdditionalRoles(instruction))
+
+	assert.Equal(t, []string{"role-2"}, adm.created, "role from checkpoint is not created again")
+	require.Len(t, aggregator.received, 1)
+	assert.ElementsMatch(t, []string{"role-1", "role-2"}, roleIds(aggregator.received[0].Success))
+	found, err := srv.rolesCheckpoint.Load(srv.rolesCheckpointKey(), &rolesCheckpoint{})
+	assert.NoError(t, err)
+	assert.False(t, found, "checkpoint is deleted when instruction is processed")
+}
+
+func TestPerformAdditionalRoles_ResentRoles(t *testing.T) {
+	adm := &testDbAdministration{}
+	aggregator := &testAggregator{batches: [][]dao.AdditionalRole{{{Id: "role-1"}}}}
+	srv := newTestRegistrationService(t, aggregator, newTestAdministrationService(adm))
+	instruction := dao.Instruction{Id: "instruction", AdditionalRoles: []dao.AdditionalRole{{Id: "role-1"}}}
+
+	err := srv.performAdditionalRoles(instruction)
+
+	assert.ErrorContains(t, err, "already been sent")
+	assert.Equal(t, []string{"role-1"}, adm.created)
+	assert.Len(t, aggregator.received, 1, "result which has already been sent is not sent in a loop")
+	var checkpoint rolesCheckpoint
+	found, err := srv.rolesCheckpoint.Load(srv.rolesCheckpointKey(), &checkpoint)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Contains(t, checkpoint.CreatedRoles, "role-1", "created role is kept to be reported on the next registration")
+}
+
+func TestPerformAdditionalRoles_BatchCreation(t *testing.T) {
+	adm := &testDbAdministration{}
+	aggregator := &testAggregator{}
+	admin := newTestAdministrationService(adm)
+	// embedding hides CreateRolesWithResults, so roles are created by CreateRoles
+	srv := newTestRegistrationService(t, aggregator, struct{ CoreAdministrationServiceIface }{admin})
+	instruction := dao.Instruction{Id: "instruction", AdditionalRoles: []dao.AdditionalRole{{Id: "role-1"}, {Id: "failed-role"}}}
+
+	err := srv.performAdditionalRoles(instruction)
+
+	assert.ErrorContains(t, err, "failed to create 1 additional roles")
+	require.Len(t, aggregator.received, 1)
+	assert.Equal(t, []string{"role-1"}, roleIds(aggregator.received[0].Success))
+	require.NotNil(t, aggregator.received[0].Failure)
+	assert.Equal(t, "failed-role", aggregator.received[0].Failure.Id)
+}
+
+func TestDrain_WaitsForContext(t *testing.T) {
+	srv := newTestRegistrationService(t, &testAggregator{}, newTestAdministrationService(&testDbAdministration{}))
+	srv.executor = helper.NewBackgroundExecutor()
+	srv.tracks = make(map[string]*dao.PhysicalDatabaseRegistrationTrack)
+	srv.basicAdapterAuth.Store(&dao.BasicAuth{Username: "adapter", Password: "adapter"})
+	// registration in progress holds the mutex, so queued registrations are not finished
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+	for i := 0; i < 10; i++ {
+		_ = srv.executor.Submit(func() {
+			srv.mutex.Lock()
+			defer srv.mutex.Unlock()
+		})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := srv.Drain(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, rejected := srv.ForceRegistration()
+	assert.ErrorIs(t, rejected, ErrRegistrationRejected)
+}
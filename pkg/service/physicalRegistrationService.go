@@ -16,25 +16,36 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	entity "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dbaas"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/helper"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// maxRegistrationTracks limits the number of forced registration tracks kept in memory
+const maxRegistrationTracks = 50
+
+// ErrRegistrationRejected is returned by ForceRegistration when registration cannot be queued
+var ErrRegistrationRejected = NewError(Unavailable, entity.UnavailableCode, "physical database registration is rejected")
+
 type PhysicalDatabaseRegistrationService struct {
-	dbName                 string
-	logger                 *zap.Logger
-	phydbid                string
-	adapterAddress         string
-	basicAdapterAuth       entity.BasicAuth
-	labels                 map[string]string
-	client                 *dbaas.Client
+	dbName           string
+	logger           *zap.Logger
+	phydbid          string
+	adapterAddress   string
+	basicAdapterAuth atomic.Pointer[entity.BasicAuth]
+	labels           map[string]string
+	client           *dbaas.Client
+	// Health is the result of the last registration, it must be read by GetHealth
 	Health                 entity.PhysicalDatabaseRegistrationHealth
+	healthMutex            sync.RWMutex
 	registrationFixedDelay int
 	registrationRetryTime  int
 	registrationRetryDelay int
@@ -45,6 +56,21 @@ type PhysicalDatabaseRegistrationService struct {
 	executor    *helper.BackgroundExecutor
 	loopContext context.Context
 	status      entity.Status
+	// draining is set when adapter is shutting down and should not be registered as running anymore
+	draining atomic.Bool
+
+	// tracksMutex guards tracks and trackIds, it is separated from mutex because registration may take long time.
+	tracksMutex sync.Mutex
+	tracks      map[string]*entity.PhysicalDatabaseRegistrationTrack
+	trackIds    []string
+
+	// rolesCheckpoint keeps additional roles created by adapter to resume instruction processing after restart
+	rolesCheckpoint helper.StateStore
+	// createdRoles are results of additional roles of createdRolesInstruction with passwords, which are not
+	// saved to rolesCheckpoint. They are guarded by mutex.
+	createdRoles            map[string]entity.Success
+	createdRolesInstruction string
+	metrics                 *Metrics
 }
 
 func NewPhysicalRegistrationService(
@@ -60,14 +86,14 @@ func NewPhysicalRegistrationService(
 	registrationRetryDelay int,
 	administrationService CoreAdministrationServiceIface,
 	context context.Context,
+	opts ...RegistrationOption,
 ) *PhysicalDatabaseRegistrationService {
 
-	return &PhysicalDatabaseRegistrationService{
+	srv := &PhysicalDatabaseRegistrationService{
 		dbName:                 dbName,
 		logger:                 logger,
 		phydbid:                phydbid,
 		adapterAddress:         adapterAddress,
-		basicAdapterAuth:       basicAdapterAuth,
 		labels:                 labels,
 		client:                 dbaasClient,
 		Health:                 entity.PhysicalDatabaseRegistrationHealth{Status: "UNKNOWN"},
@@ -78,9 +104,58 @@ func NewPhysicalRegistrationService(
 		administrationService:  administrationService,
 		loopContext:            context,
 		status:                 entity.StatusRunning,
+		tracks:                 make(map[string]*entity.PhysicalDatabaseRegistrationTrack),
+		rolesCheckpoint:        newRolesCheckpointStore(logger, ""),
+		metrics:                unregisteredMetrics(),
+	}
+	srv.basicAdapterAuth.Store(&basicAdapterAuth)
+	for _, opt := range opts {
+		opt(srv)
+	}
+	return srv
+}
+
+// RegistrationOption overrides default setting of PhysicalDatabaseRegistrationService,
+// fiber.BuildAdapter sets them from the loaded configuration
+type RegistrationOption func(*PhysicalDatabaseRegistrationService)
+
+// WithRolesCheckpointDir sets directory of additional roles progress, it is kept in memory by default
+func WithRolesCheckpointDir(dir string) RegistrationOption {
+	return func(srv *PhysicalDatabaseRegistrationService) {
+		srv.rolesCheckpoint = newRolesCheckpointStore(srv.logger, dir)
 	}
 }
 
+// WithRegistrationMetrics sets metrics of registration and additional roles, metrics are not exported by default
+func WithRegistrationMetrics(metrics *Metrics) RegistrationOption {
+	return func(srv *PhysicalDatabaseRegistrationService) {
+		if metrics != nil {
+			srv.metrics = metrics
+		}
+	}
+}
+
+// GetHealth returns the result of the last registration
+func (srv *PhysicalDatabaseRegistrationService) GetHealth() entity.PhysicalDatabaseRegistrationHealth {
+	srv.healthMutex.RLock()
+	defer srv.healthMutex.RUnlock()
+	return srv.Health
+}
+
+func (srv *PhysicalDatabaseRegistrationService) setHealth(status string) {
+	srv.healthMutex.Lock()
+	defer srv.healthMutex.Unlock()
+	srv.Health = entity.PhysicalDatabaseRegistrationHealth{Status: status}
+}
+
+// UpdateAdapterCredentials replaces credentials of adapter API sent to DBaaS and registers physical database
+// with them immediately, so aggregator does not use previous credentials longer than needed.
+// If the registration is rejected, the credentials are sent by the next periodical registration.
+func (srv *PhysicalDatabaseRegistrationService) UpdateAdapterCredentials(credentials entity.BasicAuth) (entity.PhysicalDatabaseRegistrationTrack, error) {
+	srv.basicAdapterAuth.Store(&credentials)
+	return srv.ForceRegistration()
+}
+
 func (srv *PhysicalDatabaseRegistrationService) StartRegister() {
 	if srv.administrationService.GetVersion() == "v1" {
 		go srv.registerPeriodically(srv.Register)
@@ -96,6 +171,10 @@ func (srv *PhysicalDatabaseRegistrationService) registerPeriodically(regFunc fun
 			srv.logger.Info("Periodical registration is finished")
 			return
 		default:
+			if srv.draining.Load() {
+				srv.logger.Info("Periodical registration is finished due to draining")
+				return
+			}
 			regFunc()
 			nextTime := time.Now().Truncate(time.Millisecond).Add(time.Duration(srv.registrationFixedDelay) * time.Millisecond)
 			time.Sleep(time.Until(nextTime))
@@ -110,26 +189,42 @@ func (srv *PhysicalDatabaseRegistrationService) Register() {
 	defer func() {
 		if r := recover(); r != nil {
 			srv.logger.Warn(fmt.Sprintf("Recovered from physical database registration panic, set health WARNING: %+v", r))
-			srv.Health = entity.PhysicalDatabaseRegistrationHealth{Status: "WARNING"}
+			srv.setHealth("WARNING")
 		} else {
 			srv.logger.Info("Successfully registered physical database, set health OK")
-			srv.Health = entity.PhysicalDatabaseRegistrationHealth{Status: "OK"}
+			srv.setHealth("OK")
 		}
 	}()
 	defer srv.mutex.Unlock()
 
 	srv.mutex.Lock()
 	srv.sendRegisterRequest()
+	srv.setRunStatus()
+}
+
+// setRunStatus moves registration status to run after registration unless adapter is draining.
+// Must be called under the srv.mutex.
+func (srv *PhysicalDatabaseRegistrationService) setRunStatus() {
+	if !srv.draining.Load() {
+		srv.status = entity.StatusRun
+	}
 }
 
 // RegisterWithRetry performs attempts to register physical database in DBaaS during the retryTimeSec.
 // If one attempt fails, next attempt is being performed after the retryDelaySec seconds.
 // Health status is being updated after the each registration attempt.
 func (srv *PhysicalDatabaseRegistrationService) RegisterWithRetry() {
+	srv.registerWithRetry("", srv.registerAndReturnResult)
+}
+
+// registerWithRetry calls regFunc until it succeeds or registrationRetryTime is over.
+// Result is saved to the forced registration track with trackId if it is not empty.
+func (srv *PhysicalDatabaseRegistrationService) registerWithRetry(trackId string, regFunc func() error) {
 	defer func() {
 		if r := recover(); r != nil {
 			srv.logger.Warn(fmt.Sprintf("Recovered from force physical database registration panic, set health PROBLEM: %+v", r))
-			srv.Health = entity.PhysicalDatabaseRegistrationHealth{Status: "PROBLEM"}
+			srv.setHealth("PROBLEM")
+			srv.completeRegistrationTrack(trackId, fmt.Errorf("%v", r))
 		}
 	}()
 	defer srv.mutex.Unlock()
@@ -139,16 +234,22 @@ func (srv *PhysicalDatabaseRegistrationService) RegisterWithRetry() {
 	nextTime := time.Now().Truncate(time.Millisecond)
 	lastTime := nextTime.Add(time.Duration(srv.registrationRetryTime) * time.Millisecond)
 
-	for !srv.registerAndReturnResult() {
+	for {
+		err := regFunc()
+		if err == nil {
+			break
+		}
 		nextTime = nextTime.Add(time.Duration(srv.registrationRetryDelay) * time.Millisecond)
 		if nextTime.Before(lastTime) || nextTime.Equal(lastTime) {
 			time.Sleep(time.Until(nextTime))
 		} else {
 			srv.logger.Warn("Force physical db registration has failed.")
+			srv.completeRegistrationTrack(trackId, err)
 			return
 		}
 	}
 	srv.logger.Info("Force physical db registration finished successfully.")
+	srv.completeRegistrationTrack(trackId, nil)
 }
 
 // sendRegisterRequest sends HTTP request to register physical database in DBaaS.
@@ -156,12 +257,13 @@ func (srv *PhysicalDatabaseRegistrationService) RegisterWithRetry() {
 func (srv *PhysicalDatabaseRegistrationService) sendRegisterRequest() entity.PhysicalDatabaseRegistrationResponse {
 	body := entity.PhysicalDatabaseRegistrationRequest{
 		AdapterAddress:       srv.adapterAddress,
-		HttpBasicCredentials: srv.basicAdapterAuth,
+		HttpBasicCredentials: *srv.basicAdapterAuth.Load(),
 		Labels:               srv.labels,
 	}
 	srv.modifyReqParams(&body)
 
 	response, err := srv.client.PhysicalDatabaseRegistration(srv.dbName, srv.phydbid, body)
+	srv.metrics.registrationAttempt(err)
 	if srv.administrationService.GetVersion() == "v1" {
 		return entity.PhysicalDatabaseRegistrationResponse{}
 	}
@@ -174,21 +276,28 @@ func (srv *PhysicalDatabaseRegistrationService) sendRegisterRequest() entity.Phy
 }
 
 // registerAndReturnResult send the physical database registration request and updates health status depending on the
-// registration result. Function returns true in case of successful registration, false otherwise.
-func (srv *PhysicalDatabaseRegistrationService) registerAndReturnResult() bool {
-	defer func() bool {
-		if r := recover(); r != nil {
-			srv.logger.Warn(fmt.Sprintf("Recovered from force physical database registration panic, set health PROBLEM: %+v", r))
-			srv.Health = entity.PhysicalDatabaseRegistrationHealth{Status: "PROBLEM"}
-			return false
-		} else {
-			srv.logger.Info("Successfully registered physical database, set health OK")
-			srv.Health = entity.PhysicalDatabaseRegistrationHealth{Status: "OK"}
-			return true
-		}
-	}()
+// registration result. Function returns nil in case of successful registration, error otherwise.
+func (srv *PhysicalDatabaseRegistrationService) registerAndReturnResult() (err error) {
+	defer srv.recoverRegistrationResult(&err)
 	srv.sendRegisterRequest()
-	return true
+	srv.setRunStatus()
+	return nil
+}
+
+// recoverRegistrationResult must be deferred by registration functions which return error. It recovers from
+// registration panic, sets err and updates health status depending on the registration result.
+func (srv *PhysicalDatabaseRegistrationService) recoverRegistrationResult(err *error) {
+	if r := recover(); r != nil {
+		srv.logger.Warn(fmt.Sprintf("Recovered from force physical database registration panic, set health PROBLEM: %+v", r))
+		srv.setHealth("PROBLEM")
+		*err = fmt.Errorf("%v", r)
+	} else if *err != nil {
+		srv.logger.Warn(fmt.Sprintf("Physical database registration is not completed, set health WARNING: %v", *err))
+		srv.setHealth("WARNING")
+	} else {
+		srv.logger.Info("Successfully registered physical database, set health OK")
+		srv.setHealth("OK")
+	}
 }
 
 type PhysicalDatabase struct {
@@ -206,10 +315,121 @@ func (srv *PhysicalDatabaseRegistrationService) GetPhysicalDatabase() *PhysicalD
 	}
 }
 
-func (srv *PhysicalDatabaseRegistrationService) ForceRegistration() {
+// ForceRegistration submits registration with retry to the background executor and returns the track
+// which can be used to get the registration result with GetRegistrationTrack.
+// Registration is rejected with ErrRegistrationRejected when adapter is draining or too many registrations are
+// queued, the returned track is failed then.
+func (srv *PhysicalDatabaseRegistrationService) ForceRegistration() (entity.PhysicalDatabaseRegistrationTrack, error) {
+	track := srv.newRegistrationTrack()
+	regFunc := srv.registerAndReturnResult
 	if srv.administrationService.GetVersion() != "v1" {
-		srv.logger.Warn(fmt.Sprintf("Force registration not supported for API %s", srv.administrationService.GetVersion()))
+		regFunc = srv.registerWithRolesAndReturnResult
+	}
+	err := srv.executor.Submit(func() {
+		srv.registerWithRetry(track.TrackId, regFunc)
+	})
+	if err != nil {
+		if errors.Is(err, helper.ErrExecutorShutdown) {
+			err = fmt.Errorf("%w: adapter is draining", ErrRegistrationRejected)
+		} else {
+			err = fmt.Errorf("%w: %v", ErrRegistrationRejected, err)
+		}
+		srv.logger.Warn(fmt.Sprintf("Force registration is rejected: %v", err))
+		srv.completeRegistrationTrack(track.TrackId, err)
+		track, _ = srv.GetRegistrationTrack(track.TrackId)
+		return track, err
+	}
+	return track, nil
+}
+
+// Drain moves registration status to draining and sends it to DBaaS, so aggregator stops using this physical database
+// for new logical databases. Then it waits for the background registration tasks to finish.
+// Function returns when draining is finished or ctx is done.
+func (srv *PhysicalDatabaseRegistrationService) Drain(ctx context.Context) error {
+	srv.draining.Store(true)
+	srv.executor.Shutdown()
+
+	result := make(chan error, 1)
+	go func() {
+		srv.mutex.Lock()
+		defer srv.mutex.Unlock()
+		srv.status = entity.StatusDraining
+		result <- srv.sendDrainingStatus()
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return fmt.Errorf("draining status was not sent to DBaaS: %w", ctx.Err())
+	}
+
+	if !srv.executor.AwaitTermination(ctx) {
+		return fmt.Errorf("background registration tasks are not finished: %w", ctx.Err())
+	}
+	srv.logger.Info("Physical database registration is drained")
+	return nil
+}
+
+// sendDrainingStatus sends registration request with draining status. Must be called under the srv.mutex.
+func (srv *PhysicalDatabaseRegistrationService) sendDrainingStatus() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			srv.logger.Warn(fmt.Sprintf("Failed to send draining status to DBaaS: %+v", r))
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	srv.sendRegisterRequest()
+	srv.logger.Info("Draining status is sent to DBaaS")
+	return nil
+}
+
+// GetRegistrationTrack returns the forced registration track by its identifier.
+func (srv *PhysicalDatabaseRegistrationService) GetRegistrationTrack(trackId string) (entity.PhysicalDatabaseRegistrationTrack, bool) {
+	srv.tracksMutex.Lock()
+	defer srv.tracksMutex.Unlock()
+	track, found := srv.tracks[trackId]
+	if !found {
+		return entity.PhysicalDatabaseRegistrationTrack{}, false
+	}
+	return *track, true
+}
+
+func (srv *PhysicalDatabaseRegistrationService) newRegistrationTrack() entity.PhysicalDatabaseRegistrationTrack {
+	srv.tracksMutex.Lock()
+	defer srv.tracksMutex.Unlock()
+	track := &entity.PhysicalDatabaseRegistrationTrack{
+		TrackId:      uuid.New().String(),
+		Status:       entity.RegistrationProceedingStatus,
+		CreationTime: time.Now().UTC().Format(time.RFC3339),
+	}
+	if len(srv.trackIds) >= maxRegistrationTracks {
+		delete(srv.tracks, srv.trackIds[0])
+		srv.trackIds = srv.trackIds[1:]
+	}
+	srv.tracks[track.TrackId] = track
+	srv.trackIds = append(srv.trackIds, track.TrackId)
+	return *track
+}
+
+func (srv *PhysicalDatabaseRegistrationService) completeRegistrationTrack(trackId string, err error) {
+	if trackId == "" {
+		return
+	}
+	srv.tracksMutex.Lock()
+	defer srv.tracksMutex.Unlock()
+	track, found := srv.tracks[trackId]
+	if !found {
 		return
 	}
-	srv.executor.Submit(srv.RegisterWithRetry)
+	completionTime := time.Now().UTC().Format(time.RFC3339)
+	track.CompletionTime = &completionTime
+	if err != nil {
+		track.Status = entity.RegistrationFailStatus
+		track.ErrorMessage = err.Error()
+	} else {
+		track.Status = entity.RegistrationSuccessStatus
+	}
 }
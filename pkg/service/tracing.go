@@ -0,0 +1,130 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedDbAdministration runs every call of DbAdministration which takes context in its own span.
+// It does not implement optional interfaces of DbAdministration, so they are checked on the wrapped one.
+type tracedDbAdministration struct {
+	DbAdministration
+}
+
+func traceDbAdministration(dbAdm DbAdministration) DbAdministration {
+	return tracedDbAdministration{dbAdm}
+}
+
+// startDbSpan starts span of DbAdministration call, span must be finished by deferred endDbSpan,
+// because implementations report errors by panic
+func startDbSpan(ctx context.Context, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return utils.Tracer().Start(ctx, "DbAdministration."+operation, trace.WithAttributes(attributes...))
+}
+
+func endDbSpan(span trace.Span, err *error) {
+	if r := recover(); r != nil {
+		utils.EndSpan(span, fmt.Errorf("%v", r))
+		panic(r)
+	}
+	if err != nil {
+		utils.EndSpan(span, *err)
+	} else {
+		span.End()
+	}
+}
+
+func (t tracedDbAdministration) CreateDatabase(ctx context.Context, requestOnCreateDb dto.DbCreateRequest) (name string, described *dto.LogicalDatabaseDescribed, err error) {
+	ctx, span := startDbSpan(ctx, "CreateDatabase")
+	defer endDbSpan(span, &err)
+	return t.DbAdministration.CreateDatabase(ctx, requestOnCreateDb)
+}
+
+func (t tracedDbAdministration) DescribeDatabases(ctx context.Context, logicalDatabases []string, showResources bool, showConnections bool) map[string]dto.LogicalDatabaseDescribed {
+	ctx, span := startDbSpan(ctx, "DescribeDatabases", attribute.Int("db.databases.count", len(logicalDatabases)))
+	defer endDbSpan(span, nil)
+	return t.DbAdministration.DescribeDatabases(ctx, logicalDatabases, showResources, showConnections)
+}
+
+func (t tracedDbAdministration) GetDatabases(ctx context.Context) []string {
+	ctx, span := startDbSpan(ctx, "GetDatabases")
+	defer endDbSpan(span, nil)
+	return t.DbAdministration.GetDatabases(ctx)
+}
+
+func (t tracedDbAdministration) DropResources(ctx context.Context, resources []dto.DbResource) []dto.DbResource {
+	ctx, span := startDbSpan(ctx, "DropResources", attribute.Int("db.resources.count", len(resources)))
+	defer endDbSpan(span, nil)
+	return t.DbAdministration.DropResources(ctx, resources)
+}
+
+func (t tracedDbAdministration) GetMetadata(ctx context.Context, logicalDatabase string) map[string]interface{} {
+	ctx, span := startDbSpan(ctx, "GetMetadata", attribute.String("db.name", logicalDatabase))
+	defer endDbSpan(span, nil)
+	return t.DbAdministration.GetMetadata(ctx, logicalDatabase)
+}
+
+func (t tracedDbAdministration) UpdateMetadata(ctx context.Context, newMetadata map[string]interface{}, logicalDatabase string) {
+	ctx, span := startDbSpan(ctx, "UpdateMetadata", attribute.String("db.name", logicalDatabase))
+	defer endDbSpan(span, nil)
+	t.DbAdministration.UpdateMetadata(ctx, newMetadata, logicalDatabase)
+}
+
+func (t tracedDbAdministration) CreateUser(ctx context.Context, userName string, requestOnCreateUser dto.UserCreateRequest) (user *dto.CreatedUser, err error) {
+	ctx, span := startDbSpan(ctx, "CreateUser", attribute.String("db.name", requestOnCreateUser.DbName))
+	defer endDbSpan(span, &err)
+	return t.DbAdministration.CreateUser(ctx, userName, requestOnCreateUser)
+}
+
+func (t tracedDbAdministration) MigrateToVault(ctx context.Context, dbName, userName string) (err error) {
+	ctx, span := startDbSpan(ctx, "MigrateToVault", attribute.String("db.name", dbName))
+	defer endDbSpan(span, &err)
+	return t.DbAdministration.MigrateToVault(ctx, dbName, userName)
+}
+
+func (t tracedDbAdministration) CreateRoles(ctx context.Context, roles []dto.AdditionalRole) ([]dto.Success, *dto.Failure) {
+	ctx, span := startDbSpan(ctx, "CreateRoles", attribute.Int("db.roles.count", len(roles)))
+	var err error
+	defer endDbSpan(span, &err)
+	success, failure := t.DbAdministration.CreateRoles(ctx, roles)
+	if failure != nil {
+		err = fmt.Errorf("failed to create role %s: %s", failure.Id, failure.Message)
+	}
+	return success, failure
+}
+
+func traceMigrateFromVault(ctx context.Context, reverseAdm VaultReverseMigrationAdministration, dbName, userName, password string) (err error) {
+	ctx, span := startDbSpan(ctx, "MigrateFromVault", attribute.String("db.name", dbName))
+	defer endDbSpan(span, &err)
+	return reverseAdm.MigrateFromVault(ctx, dbName, userName, password)
+}
+
+func traceGetDynamicRoleStatements(ctx context.Context, dynamicAdm DynamicCredentialsAdministration, dbName, userName string) (statements utils.DynamicRoleStatements, err error) {
+	ctx, span := startDbSpan(ctx, "GetDynamicRoleStatements", attribute.String("db.name", dbName))
+	defer endDbSpan(span, &err)
+	return dynamicAdm.GetDynamicRoleStatements(ctx, dbName, userName)
+}
+
+func traceRotatePassword(ctx context.Context, rotator PasswordRotator, dbName, userName, password string) (properties dto.ConnectionProperties, err error) {
+	ctx, span := startDbSpan(ctx, "RotatePassword", attribute.String("db.name", dbName))
+	defer endDbSpan(span, &err)
+	return rotator.RotatePassword(ctx, dbName, userName, password)
+}
@@ -0,0 +1,309 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/helper"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	vaultMigrationCheckpointKey = "vault-migration"
+	// maxVaultMigrationRate bounds the rate, so the interval between migrated users is never truncated to zero
+	maxVaultMigrationRate = 1000
+)
+
+var (
+	ErrVaultDisabled            = NewError(InvalidArgument, dto.VaultDisabledCode, "vault integration is disabled")
+	ErrVaultMigrationInProgress = NewError(Conflict, dto.VaultMigrationInProgressCode, "migration to vault is already in progress")
+)
+
+// vaultMigrationCheckpoint keeps databases processed by the migration, so the migration
+// interrupted by adapter restart continues from the first not processed database
+type vaultMigrationCheckpoint struct {
+	JobId              string                    `json:"jobId"`
+	Request            dto.VaultMigrationRequest `json:"request"`
+	ProcessedDatabases []string                  `json:"processedDatabases"`
+}
+
+type vaultMigration struct {
+	mutex      sync.Mutex
+	job        *dto.VaultMigrationJob
+	checkpoint helper.StateStore
+	// cancel stops the running job, done is closed when it is finished
+	cancel context.CancelFunc
+	done   chan struct{}
+	// defaultRate is the number of users migrated per second if request does not set it
+	defaultRate float64
+}
+
+func newVaultMigration(logger *zap.Logger, rate int, checkpointDir string) *vaultMigration {
+	return &vaultMigration{
+		checkpoint:  newStateStore(logger, checkpointDir, "vault migration checkpoint"),
+		defaultRate: float64(min(max(rate, 1), maxVaultMigrationRate)),
+	}
+}
+
+// newStateStore returns file state store if directory is set, otherwise state is kept in memory
+func newStateStore(logger *zap.Logger, dir, purpose string) helper.StateStore {
+	if dir != "" {
+		store, err := helper.NewFileStateStore(dir)
+		if err == nil {
+			return store
+		}
+		logger.Warn(fmt.Sprintf("Cannot use %s for %s, it is kept in memory: %v", dir, purpose, err))
+	}
+	return helper.NewInMemoryStateStore()
+}
+
+// StartVaultMigration starts background migration to vault of all users which passwords are not stored in vault.
+// If previous migration was interrupted, it is resumed with parameters of the new request,
+// databases processed by the interrupted migration are skipped.
+func (adminService *CoreAdministrationService) StartVaultMigration(ctx context.Context, request dto.VaultMigrationRequest) (*dto.VaultMigrationJob, error) {
+	logger := utils.AddLoggerContext(adminService.logger, ctx)
+	if !adminService.isVaultEnabled {
+		return nil, ErrVaultDisabled
+	}
+	migration := adminService.vaultMigration
+	migration.mutex.Lock()
+	defer migration.mutex.Unlock()
+	if migration.job != nil && migration.job.Status == dto.VaultMigrationProceedingStatus {
+		return nil, ErrVaultMigrationInProgress
+	}
+
+	checkpoint := vaultMigrationCheckpoint{JobId: uuid.New().String(), Request: request}
+	if !request.DryRun {
+		var previous vaultMigrationCheckpoint
+		found, err := migration.checkpoint.Load(vaultMigrationCheckpointKey, &previous)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Cannot load vault migration checkpoint, migration starts from the beginning: %v", err))
+		} else if found {
+			logger.Info(fmt.Sprintf("Resume vault migration %s, %d databases are already processed", previous.JobId, len(previous.ProcessedDatabases)))
+			checkpoint.JobId = previous.JobId
+			checkpoint.ProcessedDatabases = previous.ProcessedDatabases
+		}
+	}
+
+	databases := request.Databases
+	if len(databases) == 0 {
+		databases = adminService.dbAdm.GetDatabases(ctx)
+	}
+	processed := 0
+	for _, dbName := range databases {
+		if slices.Contains(checkpoint.ProcessedDatabases, dbName) {
+			processed++
+		}
+	}
+	rate := request.RatePerSecond
+	if rate <= 0 {
+		rate = migration.defaultRate
+	}
+	rate = min(rate, maxVaultMigrationRate)
+
+	migration.job = &dto.VaultMigrationJob{
+		JobId:              checkpoint.JobId,
+		Status:             dto.VaultMigrationProceedingStatus,
+		DryRun:             checkpoint.Request.DryRun,
+		CreationTime:       time.Now().UTC().Format(time.RFC3339),
+		TotalDatabases:     len(databases),
+		ProcessedDatabases: processed,
+	}
+	logger.Info(fmt.Sprintf("Vault migration %s is started for %d databases, dry run: %t", checkpoint.JobId, len(databases), checkpoint.Request.DryRun))
+	// job outlives the request, it keeps values of the request context for logs and is stopped with the service
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(adminService.loopContext, cancel)
+	done := make(chan struct{})
+	migration.cancel, migration.done = cancel, done
+	go func() {
+		defer close(done)
+		defer stop()
+		defer cancel()
+		adminService.runVaultMigration(jobCtx, checkpoint, databases, rate)
+	}()
+	return copyVaultMigrationJob(migration.job), nil
+}
+
+// Drain stops the running migration to vault and waits until it is finished or ctx is done.
+// Checkpoint of the stopped migration is kept, so the next migration resumes it.
+func (adminService *CoreAdministrationService) Drain(ctx context.Context) error {
+	migration := adminService.vaultMigration
+	migration.mutex.Lock()
+	cancel, done := migration.cancel, migration.done
+	migration.mutex.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("vault migration is not stopped: %w", ctx.Err())
+	}
+}
+
+// GetVaultMigrationJob returns the state of the last migration to vault, nil if migration has not been started
+func (adminService *CoreAdministrationService) GetVaultMigrationJob() *dto.VaultMigrationJob {
+	migration := adminService.vaultMigration
+	migration.mutex.Lock()
+	defer migration.mutex.Unlock()
+	if migration.job == nil {
+		return nil
+	}
+	return copyVaultMigrationJob(migration.job)
+}
+
+func (adminService *CoreAdministrationService) runVaultMigration(ctx context.Context, checkpoint vaultMigrationCheckpoint, databases []string, rate float64) {
+	logger := utils.AddLoggerContext(adminService.logger, ctx)
+	migration := adminService.vaultMigration
+	dryRun := checkpoint.Request.DryRun
+	processed := make(map[string]bool, len(checkpoint.ProcessedDatabases))
+	for _, dbName := range checkpoint.ProcessedDatabases {
+		processed[dbName] = true
+	}
+	limiter := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer limiter.Stop()
+
+	for _, dbName := range databases {
+		if ctx.Err() != nil {
+			break
+		}
+		if processed[dbName] {
+			continue
+		}
+		users, migrated, err := adminService.getUsersToMigrate(ctx, dbName)
+		migration.mutex.Lock()
+		migration.job.AlreadyMigrated += migrated
+		if err != nil {
+			migration.job.Failed = append(migration.job.Failed, dto.VaultMigrationUser{DbName: dbName, Message: err.Error()})
+		}
+		migration.mutex.Unlock()
+
+		for _, userName := range users {
+			user := dto.VaultMigrationUser{DbName: dbName, UserName: userName}
+			if dryRun {
+				migration.mutex.Lock()
+				migration.job.Pending = append(migration.job.Pending, user)
+				migration.mutex.Unlock()
+				continue
+			}
+			select {
+			case <-ctx.Done():
+			case <-limiter.C:
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			user.VaultRole, err = adminService.migrateUserToVault(ctx, dbName, userName)
+			migration.mutex.Lock()
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Cannot migrate user %s of database %s to vault: %v", userName, dbName, err))
+				user.Message = err.Error()
+				migration.job.Failed = append(migration.job.Failed, user)
+			} else {
+				migration.job.MigratedUsers++
+			}
+			migration.mutex.Unlock()
+		}
+
+		if ctx.Err() != nil {
+			// database is processed again by the resumed migration
+			break
+		}
+		migration.mutex.Lock()
+		migration.job.ProcessedDatabases++
+		migration.mutex.Unlock()
+		if !dryRun {
+			checkpoint.ProcessedDatabases = append(checkpoint.ProcessedDatabases, dbName)
+			if err := migration.checkpoint.Save(vaultMigrationCheckpointKey, checkpoint); err != nil {
+				logger.Warn(fmt.Sprintf("Cannot save vault migration checkpoint: %v", err))
+			}
+		}
+	}
+
+	interrupted := ctx.Err() != nil
+	if !dryRun && !interrupted {
+		if err := migration.checkpoint.Delete(vaultMigrationCheckpointKey); err != nil {
+			logger.Warn(fmt.Sprintf("Cannot delete vault migration checkpoint: %v", err))
+		}
+	}
+	migration.mutex.Lock()
+	defer migration.mutex.Unlock()
+	completionTime := time.Now().UTC().Format(time.RFC3339)
+	migration.job.CompletionTime = &completionTime
+	if interrupted {
+		migration.job.Status = dto.VaultMigrationFailStatus
+		migration.job.ErrorMessage = "migration is interrupted by adapter shutdown, it is resumed by the next migration request"
+	} else if len(migration.job.Failed) > 0 {
+		migration.job.Status = dto.VaultMigrationFailStatus
+		migration.job.ErrorMessage = fmt.Sprintf("%d users or databases are not migrated", len(migration.job.Failed))
+	} else {
+		migration.job.Status = dto.VaultMigrationSuccessStatus
+	}
+	logger.Info(fmt.Sprintf("Vault migration %s is finished with status %s, migrated users: %d, failures: %d",
+		migration.job.JobId, migration.job.Status, migration.job.MigratedUsers, len(migration.job.Failed)))
+}
+
+// getUsersToMigrate returns users of the database which don't have vault role yet and the number of users having it
+func (adminService *CoreAdministrationService) getUsersToMigrate(ctx context.Context, dbName string) (users []string, migrated int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cannot describe database: %v", r)
+		}
+	}()
+	metadata := adminService.dbAdm.GetMetadata(ctx, dbName)
+	if err = validateSettingMetadata(metadata); err != nil {
+		return nil, 0, err
+	}
+	described := adminService.dbAdm.DescribeDatabases(ctx, []string{dbName}, true, false)
+	for _, resource := range described[dbName].Resources {
+		if resource.Kind != userResourceKind {
+			continue
+		}
+		userName := strings.TrimPrefix(resource.Name, "admin:")
+		if _, _, found := adminService.findVaultRole(metadata, userName); found {
+			migrated++
+		} else {
+			users = append(users, userName)
+		}
+	}
+	return users, migrated, nil
+}
+
+func (adminService *CoreAdministrationService) migrateUserToVault(ctx context.Context, dbName, userName string) (roleName string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return adminService.MigrateToVault(ctx, dbName, userName)
+}
+
+func copyVaultMigrationJob(job *dto.VaultMigrationJob) *dto.VaultMigrationJob {
+	result := *job
+	result.Pending = append([]dto.VaultMigrationUser(nil), job.Pending...)
+	result.Failed = append([]dto.VaultMigrationUser(nil), job.Failed...)
+	return &result
+}
@@ -0,0 +1,130 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMigrationDatabases() *testDbAdministration {
+	return &testDbAdministration{
+		users: map[string][]string{
+			"db1": {"user1", "user2"},
+			"db2": {"user3"},
+		},
+		metadata: map[string]map[string]interface{}{
+			"db1": testDatabaseMetadata(),
+			"db2": testDatabaseMetadata(utils.LegacyVaultRoleName("host", "ns", "ms", "user3")),
+		},
+	}
+}
+
+func waitVaultMigration(t *testing.T, admin *CoreAdministrationService) *dao.VaultMigrationJob {
+	var job *dao.VaultMigrationJob
+	require.Eventually(t, func() bool {
+		job = admin.GetVaultMigrationJob()
+		return job.Status != dao.VaultMigrationProceedingStatus
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestVaultMigration(t *testing.T) {
+	adm := newTestMigrationDatabases()
+	fakeVault := &testVault{}
+	admin := newTestVaultAdministrationService(t, adm, fakeVault)
+
+	// rate above the max is clamped, otherwise interval between users is zero and the ticker panics
+	started, err := admin.StartVaultMigration(context.Background(), dao.VaultMigrationRequest{RatePerSecond: 1e12})
+	require.NoError(t, err)
+	assert.Equal(t, 2, started.TotalDatabases)
+
+	job := waitVaultMigration(t, admin)
+	assert.Equal(t, dao.VaultMigrationSuccessStatus, job.Status)
+	assert.Equal(t, 2, job.MigratedUsers)
+	assert.Equal(t, 1, job.AlreadyMigrated)
+	assert.Equal(t, 2, job.ProcessedDatabases)
+	assert.Equal(t, []string{"db1/user1", "db1/user2"}, adm.migrated)
+	assert.Len(t, fakeVault.state(), 2)
+	found, err := admin.vaultMigration.checkpoint.Load(vaultMigrationCheckpointKey, &vaultMigrationCheckpoint{})
+	assert.NoError(t, err)
+	assert.False(t, found, "checkpoint is deleted when migration is finished")
+}
+
+func TestVaultMigration_DryRun(t *testing.T) {
+	adm := newTestMigrationDatabases()
+	fakeVault := &testVault{}
+	admin := newTestVaultAdministrationService(t, adm, fakeVault)
+
+	_, err := admin.StartVaultMigration(context.Background(), dao.VaultMigrationRequest{DryRun: true, Databases: []string{"db1"}})
+	require.NoError(t, err)
+
+	job := waitVaultMigration(t, admin)
+	assert.Equal(t, dao.VaultMigrationSuccessStatus, job.Status)
+	assert.Equal(t, []dao.VaultMigrationUser{{DbName: "db1", UserName: "user1"}, {DbName: "db1", UserName: "user2"}}, job.Pending)
+	assert.Empty(t, adm.migrated)
+	assert.Empty(t, fakeVault.state())
+}
+
+func TestVaultMigration_ResumeWithNewParameters(t *testing.T) {
+	adm := newTestMigrationDatabases()
+	adm.users["db3"] = []string{"user4"}
+	adm.metadata["db3"] = testDatabaseMetadata()
+	admin := newTestVaultAdministrationService(t, adm, &testVault{})
+	require.NoError(t, admin.vaultMigration.checkpoint.Save(vaultMigrationCheckpointKey, vaultMigrationCheckpoint{
+		JobId:              "interrupted",
+		Request:            dao.VaultMigrationRequest{Databases: []string{"db1", "db2"}, RatePerSecond: 0.001},
+		ProcessedDatabases: []string{"db1"},
+	}))
+
+	started, err := admin.StartVaultMigration(context.Background(), dao.VaultMigrationRequest{Databases: []string{"db1", "db3"}, RatePerSecond: 100})
+	require.NoError(t, err)
+	assert.Equal(t, "interrupted", started.JobId)
+	assert.Equal(t, 2, started.TotalDatabases)
+	assert.Equal(t, 1, started.ProcessedDatabases)
+
+	job := waitVaultMigration(t, admin)
+	assert.Equal(t, dao.VaultMigrationSuccessStatus, job.Status)
+	assert.Equal(t, []string{"db3/user4"}, adm.migrated, "databases of the new request are migrated, processed ones are skipped")
+}
+
+func TestVaultMigration_StoppedByDrain(t *testing.T) {
+	adm := newTestMigrationDatabases()
+	loopCtx, stopLoop := context.WithCancel(context.Background())
+	defer stopLoop()
+	admin := newTestVaultAdministrationService(t, adm, &testVault{}, WithLoopContext(loopCtx))
+	requestCtx, finishRequest := context.WithCancel(context.Background())
+
+	_, err := admin.StartVaultMigration(requestCtx, dao.VaultMigrationRequest{RatePerSecond: 0.001})
+	require.NoError(t, err)
+	finishRequest()
+	assert.Equal(t, dao.VaultMigrationProceedingStatus, admin.GetVaultMigrationJob().Status, "job outlives the request")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, admin.Drain(ctx))
+
+	job := admin.GetVaultMigrationJob()
+	assert.Equal(t, dao.VaultMigrationFailStatus, job.Status)
+	assert.Zero(t, job.ProcessedDatabases)
+	assert.Empty(t, adm.migrated)
+	assert.Contains(t, job.ErrorMessage, "interrupted")
+}
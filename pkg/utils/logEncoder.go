@@ -0,0 +1,295 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	LogFormatText = "text"
+	LogFormatJson = "json"
+
+	timestampFormat = "2006-01-02T15:04:05.000Z0700"
+)
+
+// headerFields are printed in brackets before the message, in this order, even if they are not set
+var headerFields = []string{"request_id", "tenant_id", "thread", "class"}
+
+var logBufferPool = buffer.NewPool()
+
+// lineBreakEscaper keeps every entry of text format in one line
+var lineBreakEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`)
+
+// jsonLogFormat is set by SetLogFormat, loggers created by GetLogger write text format until it is set
+var jsonLogFormat atomic.Bool
+
+// SetLogFormat changes format of all loggers created by GetLogger: text or json
+func SetLogFormat(format string) error {
+	switch format {
+	case LogFormatText:
+		jsonLogFormat.Store(false)
+	case LogFormatJson:
+		jsonLogFormat.Store(true)
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+	return nil
+}
+
+// formatCore writes entries by text or JSON core depending on the format set by SetLogFormat
+type formatCore struct {
+	zapcore.LevelEnabler
+	text zapcore.Core
+	json zapcore.Core
+}
+
+func newFormatCore(writer zapcore.WriteSyncer, level zapcore.LevelEnabler) zapcore.Core {
+	return &formatCore{
+		LevelEnabler: level,
+		text:         zapcore.NewCore(newTextEncoder(), writer, level),
+		json:         zapcore.NewCore(newJsonEncoder(), writer, level),
+	}
+}
+
+func (c *formatCore) current() zapcore.Core {
+	if jsonLogFormat.Load() {
+		return c.json
+	}
+	return c.text
+}
+
+func (c *formatCore) With(fields []zapcore.Field) zapcore.Core {
+	return &formatCore{LevelEnabler: c.LevelEnabler, text: c.text.With(fields), json: c.json.With(fields)}
+}
+
+func (c *formatCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *formatCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.current().Write(entry, fields)
+}
+
+func (c *formatCore) Sync() error {
+	return c.current().Sync()
+}
+
+// textEncoder writes entries as
+// "[timestamp] [LEVEL] [request_id=...] [tenant_id=...] [thread=...] [class=...] message, key: value, ..."
+// Fields after the message keep the order they were added in. Line breaks in the message and string values
+// are escaped, so a value cannot forge a log entry.
+type textEncoder struct {
+	header    [4]string
+	fields    *buffer.Buffer
+	namespace string
+}
+
+func newTextEncoder() zapcore.Encoder {
+	return &textEncoder{fields: logBufferPool.Get()}
+}
+
+func (e *textEncoder) Clone() zapcore.Encoder {
+	clone := &textEncoder{header: e.header, fields: logBufferPool.Get(), namespace: e.namespace}
+	_, _ = clone.fields.Write(e.fields.Bytes())
+	return clone
+}
+
+func (e *textEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := e.Clone().(*textEncoder)
+	defer final.fields.Free()
+	for _, field := range fields {
+		field.AddTo(final)
+	}
+
+	line := logBufferPool.Get()
+	line.AppendByte('[')
+	line.AppendString(entry.Time.Format(timestampFormat))
+	line.AppendString("] [")
+	line.AppendString(levelName(entry.Level))
+	line.AppendByte(']')
+	for i, key := range headerFields {
+		line.AppendString(" [")
+		line.AppendString(key)
+		line.AppendByte('=')
+		line.AppendString(lineBreakEscaper.Replace(final.header[i]))
+		line.AppendByte(']')
+	}
+	line.AppendByte(' ')
+	line.AppendString(lineBreakEscaper.Replace(entry.Message))
+	_, _ = line.Write(final.fields.Bytes())
+	if entry.Stack != "" {
+		line.AppendByte('\n')
+		line.AppendString(entry.Stack)
+	}
+	line.AppendByte('\n')
+	return line, nil
+}
+
+func levelName(level zapcore.Level) string {
+	if level == logLevelNames["TRACE"] {
+		return "TRACE"
+	}
+	return level.CapitalString()
+}
+
+func (e *textEncoder) appendKey(key string) {
+	e.fields.AppendString(", ")
+	if e.namespace != "" {
+		e.fields.AppendString(e.namespace)
+		e.fields.AppendByte('.')
+	}
+	e.fields.AppendString(key)
+	e.fields.AppendString(": ")
+}
+
+func (e *textEncoder) AddString(key, value string) {
+	if e.namespace == "" {
+		for i, headerKey := range headerFields {
+			if key == headerKey {
+				e.header[i] = value
+				return
+			}
+		}
+	}
+	e.appendKey(key)
+	e.fields.AppendString(lineBreakEscaper.Replace(value))
+}
+
+// appendJson writes complex values as JSON
+func (e *textEncoder) appendJson(key string, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	e.appendKey(key)
+	_, _ = e.fields.Write(encoded)
+	return nil
+}
+
+func (e *textEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	values := zapcore.NewMapObjectEncoder()
+	if err := values.AddArray(key, marshaler); err != nil {
+		return err
+	}
+	return e.appendJson(key, values.Fields[key])
+}
+
+func (e *textEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	values := zapcore.NewMapObjectEncoder()
+	if err := values.AddObject(key, marshaler); err != nil {
+		return err
+	}
+	return e.appendJson(key, values.Fields[key])
+}
+
+func (e *textEncoder) AddReflected(key string, value interface{}) error {
+	return e.appendJson(key, value)
+}
+
+func (e *textEncoder) OpenNamespace(key string) {
+	if e.namespace != "" {
+		key = e.namespace + "." + key
+	}
+	e.namespace = key
+}
+
+func (e *textEncoder) AddBinary(key string, value []byte) {
+	e.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (e *textEncoder) AddByteString(key string, value []byte) {
+	e.AddString(key, string(value))
+}
+
+func (e *textEncoder) AddBool(key string, value bool) {
+	e.appendKey(key)
+	e.fields.AppendBool(value)
+}
+
+func (e *textEncoder) AddComplex128(key string, value complex128) {
+	e.appendKey(key)
+	e.fields.AppendString(strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (e *textEncoder) AddComplex64(key string, value complex64) {
+	e.appendKey(key)
+	e.fields.AppendString(strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (e *textEncoder) AddDuration(key string, value time.Duration) {
+	e.appendKey(key)
+	e.fields.AppendString(value.String())
+}
+
+func (e *textEncoder) AddFloat64(key string, value float64) {
+	e.appendKey(key)
+	e.fields.AppendFloat(value, 64)
+}
+
+func (e *textEncoder) AddFloat32(key string, value float32) {
+	e.appendKey(key)
+	e.fields.AppendFloat(float64(value), 32)
+}
+
+func (e *textEncoder) AddInt(key string, value int)     { e.AddInt64(key, int64(value)) }
+func (e *textEncoder) AddInt32(key string, value int32) { e.AddInt64(key, int64(value)) }
+func (e *textEncoder) AddInt16(key string, value int16) { e.AddInt64(key, int64(value)) }
+func (e *textEncoder) AddInt8(key string, value int8)   { e.AddInt64(key, int64(value)) }
+
+func (e *textEncoder) AddInt64(key string, value int64) {
+	e.appendKey(key)
+	e.fields.AppendInt(value)
+}
+
+func (e *textEncoder) AddTime(key string, value time.Time) {
+	e.appendKey(key)
+	e.fields.AppendString(value.Format(timestampFormat))
+}
+
+func (e *textEncoder) AddUint(key string, value uint)       { e.AddUint64(key, uint64(value)) }
+func (e *textEncoder) AddUint32(key string, value uint32)   { e.AddUint64(key, uint64(value)) }
+func (e *textEncoder) AddUint16(key string, value uint16)   { e.AddUint64(key, uint64(value)) }
+func (e *textEncoder) AddUint8(key string, value uint8)     { e.AddUint64(key, uint64(value)) }
+func (e *textEncoder) AddUintptr(key string, value uintptr) { e.AddUint64(key, uint64(value)) }
+
+func (e *textEncoder) AddUint64(key string, value uint64) {
+	e.appendKey(key)
+	e.fields.AppendUint(value)
+}
+
+// newJsonEncoder writes the same fields as text encoder in JSON objects
+func newJsonEncoder() zapcore.Encoder {
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.TimeKey = "timestamp"
+	encoderCfg.EncodeTime = zapcore.TimeEncoderOfLayout(timestampFormat)
+	encoderCfg.EncodeLevel = func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(levelName(level))
+	}
+	return zapcore.NewJSONEncoder(encoderCfg)
+}
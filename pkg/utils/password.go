@@ -0,0 +1,69 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+const (
+	DefaultPasswordLength = 24
+
+	passwordLowercase = "abcdefghijklmnopqrstuvwxyz"
+	passwordUppercase = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordDigits    = "0123456789"
+)
+
+// GeneratePassword returns random password of the given length which contains lowercase and uppercase
+// letters and digits. Special characters are not used, so the password needs no escaping in statements.
+func GeneratePassword(length int) (string, error) {
+	groups := []string{passwordLowercase, passwordUppercase, passwordDigits}
+	if length < len(groups) {
+		return "", fmt.Errorf("password length must be at least %d", len(groups))
+	}
+	alphabet := passwordLowercase + passwordUppercase + passwordDigits
+	password := make([]byte, length)
+	for i := range password {
+		// every character group is present at least once
+		charset := alphabet
+		if i < len(groups) {
+			charset = groups[i]
+		}
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		password[i] = c
+	}
+	// shuffle to avoid predictable positions of the character groups
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return string(password), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[n.Int64()], nil
+}
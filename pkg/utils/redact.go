@@ -0,0 +1,88 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces secrets in logs and error messages
+const RedactedValue = "***"
+
+// secretKeyParts are parts of keys which values are secrets, keys are compared in lower case without separators
+var secretKeyParts = []string{"password", "passwd", "secret", "token", "credential", "privatekey", "apikey", "authorization"}
+
+var (
+	secretKeyPattern = `(?i)[\w.-]*(?:password|passwd|secret|token|credential|private[_-]?key|api[_-]?key|authorization)[\w.-]*`
+	// "password": "value" in JSON bodies
+	jsonSecretRegexp = regexp.MustCompile(`("` + secretKeyPattern + `"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// password=value, password: value in query strings, headers and texts
+	textSecretRegexp = regexp.MustCompile(`(\b` + secretKeyPattern + `\s*[=:]\s*(?:(?i:basic|bearer)\s+)?)([^\s"',;&}\]]+)`)
+	// user:password@host in connection URLs
+	urlSecretRegexp = regexp.MustCompile(`(\w+://[^\s:/@"']+:)[^\s@/"']+@`)
+)
+
+// IsSecretKey reports whether value of the key, e.g. of connection property, is a secret
+func IsSecretKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+	for _, part := range secretKeyParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactMap returns copy of the map with secret values replaced by RedactedValue, nested maps and slices are redacted too
+func RedactMap(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if IsSecretKey(key) {
+			redacted[key] = RedactedValue
+		} else {
+			redacted[key] = redactValue(value)
+		}
+	}
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return RedactMap(typed)
+	case []interface{}:
+		redacted := make([]interface{}, len(typed))
+		for i, item := range typed {
+			redacted[i] = redactValue(item)
+		}
+		return redacted
+	case string:
+		return RedactSecrets(typed)
+	default:
+		return value
+	}
+}
+
+// RedactSecrets masks secrets in free text, e.g. in raw responses of DBaaS aggregator or backup daemon
+// before they are put into errors: values of password-like JSON fields and key=value pairs and passwords of URLs.
+func RedactSecrets(text string) string {
+	text = jsonSecretRegexp.ReplaceAllString(text, `$1"`+RedactedValue+`"`)
+	text = textSecretRegexp.ReplaceAllString(text, `${1}`+RedactedValue)
+	return urlSecretRegexp.ReplaceAllString(text, `${1}`+RedactedValue+`@`)
+}
@@ -0,0 +1,159 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// CredentialsUsernameKey and CredentialsPasswordKey are file names of mounted credentials secret
+	CredentialsUsernameKey = "username"
+	CredentialsPasswordKey = "password"
+)
+
+// WatchFiles polls the files until ctx is done and calls onChange when content of any of them changes.
+// Kubernetes updates mounted secrets by swapping symlinks, so file content is compared instead of file events.
+// Failed reload is not retried until the files are changed again, so the warning is not repeated every interval.
+func WatchFiles(ctx context.Context, interval time.Duration, logger *zap.Logger, onChange func() error, paths ...string) {
+	last := hashFiles(paths)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			current := hashFiles(paths)
+			if current == last {
+				continue
+			}
+			last = current
+			if err := onChange(); err != nil {
+				logger.Warn(fmt.Sprintf("Cannot reload %s: %v", strings.Join(paths, ", "), err))
+				continue
+			}
+			logger.Info(fmt.Sprintf("Reloaded %s", strings.Join(paths, ", ")))
+		}
+	}()
+}
+
+func hashFiles(paths []string) string {
+	hash := sha256.New()
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			// missing file is a state too, it is reloaded when the file appears
+			content = []byte(err.Error())
+		}
+		hash.Write(content)
+		hash.Write([]byte{0})
+	}
+	return string(hash.Sum(nil))
+}
+
+// ReadCredentialsFromDir reads username and password from files of mounted kubernetes secret
+func ReadCredentialsFromDir(dir string) (username string, password string, err error) {
+	usernameContent, err := os.ReadFile(filepath.Join(dir, CredentialsUsernameKey))
+	if err != nil {
+		return "", "", err
+	}
+	passwordContent, err := os.ReadFile(filepath.Join(dir, CredentialsPasswordKey))
+	if err != nil {
+		return "", "", err
+	}
+	username = strings.TrimSpace(string(usernameContent))
+	password = strings.TrimSpace(string(passwordContent))
+	if username == "" || password == "" {
+		return "", "", fmt.Errorf("username or password in %s is empty", dir)
+	}
+	return username, password, nil
+}
+
+// WatchCredentials calls update with credentials from the secret directory every time they are changed
+func WatchCredentials(ctx context.Context, dir string, interval time.Duration, logger *zap.Logger, update func(username, password string)) {
+	WatchFiles(ctx, interval, logger, func() error {
+		username, password, err := ReadCredentialsFromDir(dir)
+		if err != nil {
+			return err
+		}
+		update(username, password)
+		return nil
+	}, filepath.Join(dir, CredentialsUsernameKey), filepath.Join(dir, CredentialsPasswordKey))
+}
+
+// CertificateReloader serves TLS certificate which is reloaded from files without restart,
+// use GetCertificate in tls.Config
+type CertificateReloader struct {
+	mutex    sync.RWMutex
+	certFile string
+	keyFile  string
+	cert     *tls.Certificate
+}
+
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	reloader := &CertificateReloader{certFile: certFile, keyFile: keyFile}
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// Reload loads certificate and key, previous certificate is kept if they cannot be loaded
+func (r *CertificateReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("cannot load certificate %s: %w", r.certFile, err)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cert = &cert
+	return nil
+}
+
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.cert, nil
+}
+
+// Watch reloads certificate when certificate or key file is changed until ctx is done
+func (r *CertificateReloader) Watch(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	WatchFiles(ctx, interval, logger, r.Reload, r.certFile, r.keyFile)
+}
+
+// WatchLogLevel sets level of loggers created by GetLogger from the file, e.g. mounted config map,
+// every time the file is changed
+func WatchLogLevel(ctx context.Context, fileName string, interval time.Duration, logger *zap.Logger) {
+	WatchFiles(ctx, interval, logger, func() error {
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		return SetLogLevel(strings.TrimSpace(string(content)))
+	}, fileName)
+}
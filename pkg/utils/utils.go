@@ -18,19 +18,19 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"regexp"
-	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/distribution/uuid"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -123,29 +123,23 @@ func OptionalString(src string, defaultStr string) string {
 	return src
 }
 
+// GetLogger creates logger writing to stdout in text format, or in JSON if it is set by SetLogFormat.
+// Request scoped fields are added to the logger by AddLoggerContext.
 func GetLogger(level ...interface{}) *zap.Logger {
 	logLevel := determineLogLevel(level...)
-	atom := zap.NewAtomicLevel()
-	encoderCfg := getEncoderConfig()
-	zapLevel := getLogLevel(logLevel)
+	atom := zap.NewAtomicLevelAt(getLogLevel(logLevel))
 
-	customHandler := &CustomLogHandler{minLevel: zapLevel}
+	core := newFormatCore(zapcore.Lock(os.Stdout), atom)
 
-	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderCfg),
-		zapcore.AddSync(customHandler),
-		atom,
-	)
-
-	baseFields := []zap.Field{
-		zap.String("request_id", os.Getenv("REQUEST_ID")),
-		zap.String("tenant_id", os.Getenv("TENANT_ID")),
-		zap.String("thread", os.Getenv("THREAD")),
-		zap.String("class", os.Getenv("CLASS")),
+	var baseFields []zap.Field
+	for _, key := range []string{"thread", "class"} {
+		if value := os.Getenv(strings.ToUpper(key)); value != "" {
+			baseFields = append(baseFields, zap.String(key, value))
+		}
 	}
 
 	zapLogger := zap.New(core).With(baseFields...)
-	atom.SetLevel(zapLevel)
+	logLevels.add(atom)
 
 	return zapLogger
 }
@@ -169,94 +163,103 @@ func determineLogLevel(level ...interface{}) string {
 	return logLevel
 }
 
+var logLevelNames = map[string]zapcore.Level{
+	"OFF":   zapcore.Level(6),
+	"FATAL": zapcore.FatalLevel,
+	"ERROR": zapcore.ErrorLevel,
+	"WARN":  zapcore.WarnLevel,
+	"INFO":  zapcore.InfoLevel,
+	"DEBUG": zapcore.DebugLevel,
+	"TRACE": zapcore.Level(-1),
+}
+
 func getLogLevel(level string) zapcore.Level {
-	switch level {
-	case "OFF":
-		return zapcore.Level(6)
-	case "FATAL":
-		return zapcore.FatalLevel
-	case "ERROR":
-		return zapcore.ErrorLevel
-	case "WARN":
-		return zapcore.WarnLevel
-	case "INFO":
-		return zapcore.InfoLevel
-	case "DEBUG":
-		return zapcore.DebugLevel
-	case "TRACE":
-		return zapcore.Level(-1)
-	default:
-		return zapcore.InfoLevel
-	}
-}
-
-func getEncoderConfig() zapcore.EncoderConfig {
-	encoderCfg := zap.NewProductionEncoderConfig()
-	encoderCfg.TimeKey = "timestamp"
-	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
-	return encoderCfg
-}
-
-type CustomLogHandler struct {
-	minLevel zapcore.Level
-}
-
-func (h *CustomLogHandler) Write(p []byte) (n int, err error) {
-	var logEntry map[string]interface{}
-	if err := json.Unmarshal(p, &logEntry); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to parse log message: %s\n", p)
-		return 0, fmt.Errorf("failed to parse log message")
-	}
-
-	levelStr := strings.ToUpper(fmt.Sprintf("%v", logEntry["level"]))
-	timestamp := fmt.Sprintf("%v", logEntry["timestamp"])
-	message := fmt.Sprintf("%v", logEntry["msg"])
-	requestID := fmt.Sprintf("%v", logEntry["request_id"])
-	tenantID := fmt.Sprintf("%v", logEntry["tenant_id"])
-	thread := fmt.Sprintf("%v", logEntry["thread"])
-	class := fmt.Sprintf("%v", logEntry["class"])
-
-	output := fmt.Sprintf("[%s] [%s] [request_id=%s] [tenant_id=%s] [thread=%s] [class=%s] %s",
-		timestamp, levelStr, requestID, tenantID, thread, class, message)
-
-	standardFields := []string{
-		"level", "timestamp", "msg",
-		"request_id", "tenant_id", "thread", "class",
-	}
-
-	var additionalFields []string
-	for key, value := range logEntry {
-		if !slices.Contains(standardFields, key) {
-			additionalFields = append(additionalFields, fmt.Sprintf("%s: %v", key, value))
-		}
+	if zapLevel, ok := logLevelNames[level]; ok {
+		return zapLevel
 	}
+	return zapcore.InfoLevel
+}
 
-	if len(additionalFields) > 0 {
-		output = fmt.Sprintf("%s, %s", output, strings.Join(additionalFields, ", "))
+// logLevels keeps levels of all loggers created by GetLogger, so the level can be changed at runtime
+var logLevels = &atomicLevels{}
+
+type atomicLevels struct {
+	mutex  sync.Mutex
+	levels []zap.AtomicLevel
+	// current is the level set by SetLogLevel, it is empty until the level is changed at runtime
+	current string
+}
+
+func (l *atomicLevels) add(level zap.AtomicLevel) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.current != "" {
+		level.SetLevel(getLogLevel(l.current))
 	}
+	l.levels = append(l.levels, level)
+}
 
-	fmt.Println(output)
+// SetLogLevel changes level of all loggers created by GetLogger: OFF, FATAL, ERROR, WARN, INFO, DEBUG or TRACE
+func SetLogLevel(level string) error {
+	level = strings.ToUpper(level)
+	zapLevel, ok := logLevelNames[level]
+	if !ok {
+		return fmt.Errorf("unknown log level %q", level)
+	}
+	logLevels.mutex.Lock()
+	defer logLevels.mutex.Unlock()
+	logLevels.current = level
+	for _, atom := range logLevels.levels {
+		atom.SetLevel(zapLevel)
+	}
+	return nil
+}
 
-	return len(p), nil
+// GetLogLevel returns the level set by SetLogLevel or LOG_LEVEL if it has not been changed
+func GetLogLevel() string {
+	logLevels.mutex.Lock()
+	defer logLevels.mutex.Unlock()
+	if logLevels.current != "" {
+		return logLevels.current
+	}
+	return determineLogLevel()
 }
 
+// AddLoggerContext adds request scoped fields of the context to the logger: request_id and tenant_id values
+// and ids of the current span
 func AddLoggerContext(logger *zap.Logger, ctx context.Context) *zap.Logger {
-	return logger.With(zap.ByteString("request_id", []byte(func() string {
-		if v := ctx.Value("request_id"); v != nil {
-			return fmt.Sprintf("%s", v)
+	fields := make([]zap.Field, 0, 4)
+	for _, key := range []string{"request_id", "tenant_id"} {
+		switch value := ctx.Value(key).(type) {
+		case string:
+			fields = append(fields, zap.String(key, value))
+		case []byte:
+			fields = append(fields, zap.ByteString(key, value))
 		}
-		return ""
-	}())))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields = append(fields,
+			zap.String("trace_id", spanContext.TraceID().String()),
+			zap.String("span_id", spanContext.SpanID().String()))
+	}
+	return logger.With(fields...)
 }
 
+// Deprecated: TLS of adapter API is set by config.AdapterConfig Adapter.InternalTlsEnabled or fiber.WithTLS
 func IsTLSEnabledForMainService() bool {
 	return GetEnv("TLS_ENABLED", "false") == "true"
 }
 
+// IsHttpsEnabled returns INTERNAL_TLS_ENABLED env, it is the default of fiber.WithTLS
 func IsHttpsEnabled() bool {
 	return GetEnv("INTERNAL_TLS_ENABLED", "false") == "true"
 }
 
+// GetGracefulShutdownTimeout returns GRACEFUL_SHUTDOWN_TIMEOUT_SEC env, it is the default of fiber.WithShutdownTimeout
+func GetGracefulShutdownTimeout() time.Duration {
+	return time.Duration(GetEnvAsInt("GRACEFUL_SHUTDOWN_TIMEOUT_SEC", 25)) * time.Second
+}
+
 func ConfigureHttpsForClient(c *http.Client) error {
 	return ConfigureHttpsForClientWithCertificate(c, certificateFilePath+"ca.crt")
 }
@@ -15,12 +15,12 @@
 package utils
 
 import (
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"strings"
 
 	vault "github.com/hashicorp/vault/api"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -30,12 +30,14 @@ const (
 	RolePrefix          = "nc-dbaas-"
 )
 
-var log = GetLogger(GetEnvAsBool("DEBUG_LOG", true))
+var log = GetLogger()
 
 type VaultClient struct {
 	client *vault.Client
 	VaultConfig
-	k8sJWT            string
+	tokenManager      *vaultTokenManager
+	kvMounts          kvMounts
+	leases            *leaseTracker
 	RotationStatement string
 }
 
@@ -53,42 +55,76 @@ type VaultConfig struct {
 	VaultRotPeriod  string
 	VaultAuthMethod string
 	VaultDBName     string
+	// K8STokenPath is the path of service account token used for login, default token path is used if it is empty
+	K8STokenPath string
+	// Authenticator overrides kubernetes auth method configured by VaultAuthMethod and VaultRole
+	Authenticator Authenticator
+	// KvVersion pins version of KV secrets engine, version is detected by the secret mount if it is 0
+	KvVersion int
+	// KvMount is the mount path of KV secrets engine, e.g. "secret". It is used when KvVersion is pinned
+	// or the mount cannot be detected, the first segment of the secret path is used if it is empty.
+	KvMount string
+	// CredentialsMode is VaultStaticCredentials by default
+	CredentialsMode VaultCredentialsMode
+	// DynamicDefaultTTL and DynamicMaxTTL are TTLs of credentials issued for dynamic roles, e.g. "1h".
+	// Defaults of the vault database secrets engine are used if they are empty.
+	DynamicDefaultTTL string
+	DynamicMaxTTL     string
+	// MetricsRegisterer is used to export metrics of the vault token, metrics are not exported if it is nil
+	MetricsRegisterer prometheus.Registerer
+	// RoleNameMaxLength limits length of role names, DefaultVaultRoleNameMaxLength is used if it is 0
+	RoleNameMaxLength int
 }
 
-func NewVaultClient(vaultConfig VaultConfig) *VaultClient {
-	K8SToken, err := GetK8SToken("")
-	if err != nil {
-		panic(err)
+// NewVaultClient logs in to vault and starts background renewal of the vault token.
+// Call Close to stop the renewal. If VaultConfig.Authenticator is not set, kubernetes auth method
+// configured by VaultAuthMethod and VaultRole is used.
+func NewVaultClient(vaultConfig VaultConfig) (*VaultClient, error) {
+	authenticator := vaultConfig.Authenticator
+	if authenticator == nil {
+		authenticator = &KubernetesAuthenticator{
+			MountPath: vaultConfig.VaultAuthMethod,
+			Role:      vaultConfig.VaultRole,
+			TokenPath: vaultConfig.K8STokenPath,
+		}
 	}
 
 	config := vault.DefaultConfig()
-
+	if config.Error != nil {
+		return nil, fmt.Errorf("can not create vault client config: %w", config.Error)
+	}
 	config.Address = vaultConfig.Address
-	vaultClient, err := vault.NewClient(config)
-	if err != nil {
-		log.Error("Can not create vault client", zap.Error(err))
-		panic(err)
+	if configurer, ok := authenticator.(ClientConfigurer); ok {
+		if err := configurer.ConfigureClient(config); err != nil {
+			return nil, fmt.Errorf("can not configure vault client: %w", err)
+		}
 	}
-
-	lr, err := loginByK8S(vaultClient, vaultConfig, K8SToken)
+	if !strings.HasPrefix(config.Address, "unix://") {
+		// vault requires *http.Transport for unix sockets
+		config.HttpClient.Transport = NewTracingTransport(config.HttpClient.Transport)
+	}
+	vaultClient, err := vault.NewClient(config)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("can not create vault client: %w", err)
 	}
 
-	vaultClient.SetToken(lr.Auth.ClientToken)
-
-	adapterVaultClient := VaultClient{
-		client:      vaultClient,
-		k8sJWT:      K8SToken,
-		VaultConfig: vaultConfig,
+	tokenManager := newVaultTokenManager(vaultClient, authenticator, vaultConfig.MetricsRegisterer)
+	if err = tokenManager.start(); err != nil {
+		return nil, fmt.Errorf("can not login to vault at %s: %w", vaultConfig.Address, err)
 	}
 
-	err = adapterVaultClient.RefreshSelfToken()
-	if err != nil {
-		panic(err)
-	}
+	return &VaultClient{
+		client:       vaultClient,
+		tokenManager: tokenManager,
+		leases:       newLeaseTracker(vaultClient),
+		VaultConfig:  vaultConfig,
+	}, nil
+}
 
-	return &adapterVaultClient
+// Close stops background renewal of the vault token and leases of dynamic credentials
+func (vc *VaultClient) Close() {
+	vc.tokenManager.close()
+	vc.leases.close()
 }
 
 func (vc *VaultClient) CreateVaultRole(cloudPublicHost, namespace, microserviceName, dbRole string) (string, error) {
@@ -148,7 +184,13 @@ func (vc *VaultClient) DeleteVaultRole(roleName string) error {
 	return nil
 }
 
+// ForceRefreshCredsFor rotates password of the static role. Dynamic roles have no password to rotate,
+// vault issues new credentials on every request, so credentials already issued to other consumers are kept.
 func (vc *VaultClient) ForceRefreshCredsFor(vaultRole string) error {
+	if vc.IsDynamicCredentials() {
+		log.Debug(fmt.Sprintf("Vault role %s is dynamic, credentials are not rotated", vaultRole))
+		return nil
+	}
 	err := vc.RefreshSelfToken()
 	if err != nil {
 		return err
@@ -163,13 +205,10 @@ func (vc *VaultClient) ForceRefreshCredsFor(vaultRole string) error {
 	return nil
 }
 
+// RefreshSelfToken makes sure the vault token is not expired. Token is renewed in background,
+// so login is performed only if the renewal could not get new token in time.
 func (vc *VaultClient) RefreshSelfToken() error {
-	lr, err := loginByK8S(vc.client, vc.VaultConfig, vc.k8sJWT)
-	if err != nil {
-		return err
-	}
-	vc.client.SetToken(lr.Auth.ClientToken)
-	return nil
+	return vc.tokenManager.ensureToken()
 }
 
 func (vc *VaultClient) ForceRefreshDBCredsFor(vaultRole string) error {
@@ -186,20 +225,22 @@ func (vc *VaultClient) ForceRefreshDBCredsFor(vaultRole string) error {
 	return nil
 }
 
+// GetVaultRoleName returns safe role name for the user, see BuildVaultRoleName.
+// Use ResolveVaultRoleName to find roles created with legacy names.
 func (vc *VaultClient) GetVaultRoleName(cloudPublicHost string, namespace string, microserviceName string, dbRole string) string {
-	return RolePrefix + cloudPublicHost + "_" + namespace + "_" + microserviceName + "_" + dbRole
+	return BuildVaultRoleName(vc.roleNameMaxLength(), cloudPublicHost, namespace, microserviceName, dbRole)
 }
 
-func (vc *VaultClient) ReadPasswordFromKv(secretPath string) (string, error) {
-	secret, err := vc.client.Logical().Read(secretPath)
-	if err != nil {
-		return "", err
-	}
-	if password, ok := secret.Data["password"]; ok {
-		return password.(string), nil
-	} else {
-		return "", errors.New("secret is empty")
+// ResolveVaultRoleName returns the role of the user among existing roles, see ResolveVaultRoleName function
+func (vc *VaultClient) ResolveVaultRoleName(existingRoles []string, cloudPublicHost, namespace, microserviceName, dbRole string) (string, bool) {
+	return ResolveVaultRoleName(vc.roleNameMaxLength(), existingRoles, cloudPublicHost, namespace, microserviceName, dbRole)
+}
+
+func (vc *VaultClient) roleNameMaxLength() int {
+	if vc.RoleNameMaxLength == 0 {
+		return DefaultVaultRoleNameMaxLength
 	}
+	return vc.RoleNameMaxLength
 }
 
 func IsVaultPassword(password string) bool {
@@ -225,37 +266,6 @@ func GetK8SToken(fileName string) (string, error) {
 	return content, nil
 }
 
-type loginResult struct {
-	Auth struct {
-		ClientToken string `json:"client_token"`
-	} `json:"auth"`
-}
-
-func loginByK8S(vaultClient *vault.Client, vaultConfig VaultConfig, k8sJWT string) (*loginResult, error) {
-	req := vaultClient.NewRequest("POST", "/v1/auth/"+vaultConfig.VaultAuthMethod+"/login")
-	err := req.SetJSONBody(map[string]string{"role": vaultConfig.VaultRole, "jwt": k8sJWT})
-
-	if err != nil {
-		log.Error("Can not set Json body in vault login request")
-		return nil, err
-	}
-
-	resp, err := vaultClient.RawRequest(req)
-	if err != nil {
-		log.Error("Can not login via k8s token", zap.Error(err))
-		return nil, err
-	}
-
-	var result loginResult
-	err = resp.DecodeJSON(&result)
-	if err != nil {
-		log.Error("Can not decode login result Json")
-		return nil, err
-	}
-
-	return &result, nil
-}
-
 func getRotationPeriod(rotationPeriod string) string {
 	if rotationPeriod == "" {
 		rotationPeriod = "24h"
@@ -0,0 +1,180 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+const (
+	defaultKubernetesAuthPath = "kubernetes"
+	defaultAppRoleAuthPath    = "approle"
+	defaultCertAuthPath       = "cert"
+)
+
+// Authenticator obtains vault token. Login is called on start and every time the token can not be renewed,
+// so implementations should read rotated credentials (e.g. token files) on every call.
+// Returned secret must contain auth data with client token, its TTL and renewable flag.
+type Authenticator interface {
+	Login(client *vault.Client) (*vault.Secret, error)
+}
+
+// ClientConfigurer is implemented by authenticators which need to change vault client configuration,
+// e.g. to present a client certificate
+type ClientConfigurer interface {
+	ConfigureClient(config *vault.Config) error
+}
+
+// KubernetesAuthenticator logs in with kubernetes service account token
+type KubernetesAuthenticator struct {
+	// MountPath of the auth method, "kubernetes" by default
+	MountPath string
+	Role      string
+	// TokenPath of the service account token, default service account token path is used if it is empty
+	TokenPath string
+}
+
+func (a *KubernetesAuthenticator) Login(client *vault.Client) (*vault.Secret, error) {
+	jwt, err := GetK8SToken(a.TokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("can not read kubernetes service account token: %w", err)
+	}
+	return login(client, authPath(a.MountPath, defaultKubernetesAuthPath), map[string]interface{}{
+		"role": a.Role,
+		"jwt":  jwt,
+	})
+}
+
+// AppRoleAuthenticator logs in with AppRole role id and secret id
+type AppRoleAuthenticator struct {
+	// MountPath of the auth method, "approle" by default
+	MountPath string
+	RoleID    string
+	SecretID  string
+	// SecretIDFile is read on every login if SecretID is empty
+	SecretIDFile string
+}
+
+func (a *AppRoleAuthenticator) Login(client *vault.Client) (*vault.Secret, error) {
+	secretID := a.SecretID
+	if secretID == "" && a.SecretIDFile != "" {
+		content, err := os.ReadFile(a.SecretIDFile)
+		if err != nil {
+			return nil, fmt.Errorf("can not read AppRole secret id from %s: %w", a.SecretIDFile, err)
+		}
+		secretID = strings.TrimSpace(string(content))
+	}
+	return login(client, authPath(a.MountPath, defaultAppRoleAuthPath), map[string]interface{}{
+		"role_id":   a.RoleID,
+		"secret_id": secretID,
+	})
+}
+
+// TokenFileAuthenticator uses a token from file, e.g. issued by vault agent or vault dev server.
+// The file is read again when the token can not be renewed.
+type TokenFileAuthenticator struct {
+	Path string
+}
+
+func (a *TokenFileAuthenticator) Login(client *vault.Client) (*vault.Secret, error) {
+	content, err := os.ReadFile(a.Path)
+	if err != nil {
+		return nil, fmt.Errorf("can not read vault token from %s: %w", a.Path, err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return nil, fmt.Errorf("vault token file %s is empty", a.Path)
+	}
+
+	tokenClient, err := client.Clone()
+	if err != nil {
+		return nil, err
+	}
+	tokenClient.SetToken(token)
+	self, err := tokenClient.Auth().Token().LookupSelf()
+	if err != nil {
+		return nil, fmt.Errorf("can not lookup vault token from %s: %w", a.Path, err)
+	}
+	ttl, err := self.TokenTTL()
+	if err != nil {
+		return nil, err
+	}
+	renewable, err := self.TokenIsRenewable()
+	if err != nil {
+		return nil, err
+	}
+	return &vault.Secret{Auth: &vault.SecretAuth{
+		ClientToken:   token,
+		LeaseDuration: int(ttl.Seconds()),
+		Renewable:     renewable,
+	}}, nil
+}
+
+// CertAuthenticator logs in with TLS client certificate
+type CertAuthenticator struct {
+	// MountPath of the auth method, "cert" by default
+	MountPath string
+	// Name of the certificate role, optional
+	Name       string
+	ClientCert string
+	ClientKey  string
+	// CACert is used to verify vault server certificate, optional
+	CACert string
+}
+
+func (a *CertAuthenticator) ConfigureClient(config *vault.Config) error {
+	return config.ConfigureTLS(&vault.TLSConfig{
+		ClientCert: a.ClientCert,
+		ClientKey:  a.ClientKey,
+		CACert:     a.CACert,
+	})
+}
+
+func (a *CertAuthenticator) Login(client *vault.Client) (*vault.Secret, error) {
+	data := map[string]interface{}{}
+	if a.Name != "" {
+		data["name"] = a.Name
+	}
+	return login(client, authPath(a.MountPath, defaultCertAuthPath), data)
+}
+
+func authPath(mountPath, defaultPath string) string {
+	mountPath = strings.Trim(mountPath, "/")
+	if mountPath == "" {
+		mountPath = defaultPath
+	}
+	return "auth/" + mountPath + "/login"
+}
+
+// login performs login request without the current token, which may be already expired
+func login(client *vault.Client, path string, data map[string]interface{}) (*vault.Secret, error) {
+	loginClient, err := client.Clone()
+	if err != nil {
+		return nil, err
+	}
+	loginClient.ClearToken()
+	secret, err := loginClient.Logical().Write(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("vault login via %s failed: %w", path, err)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, fmt.Errorf("vault login via %s returned no token", path)
+	}
+	return secret, nil
+}
@@ -0,0 +1,244 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"go.uber.org/zap"
+)
+
+type VaultCredentialsMode string
+
+const (
+	// VaultStaticCredentials mode creates vault static roles which rotate password of the database user
+	VaultStaticCredentials VaultCredentialsMode = "static"
+	// VaultDynamicCredentials mode creates vault dynamic roles, so vault issues short-lived users on every request
+	VaultDynamicCredentials VaultCredentialsMode = "dynamic"
+)
+
+// DynamicRoleStatements are database statements used by vault to manage users of a dynamic role.
+// Statements are templates, see vault database secrets engine documentation of the plugin.
+type DynamicRoleStatements struct {
+	Creation   []string `json:"creation_statements"`
+	Revocation []string `json:"revocation_statements,omitempty"`
+	Renew      []string `json:"renew_statements,omitempty"`
+	Rollback   []string `json:"rollback_statements,omitempty"`
+}
+
+type dynamicRoleOptions struct {
+	DbName string `json:"db_name"`
+	DynamicRoleStatements
+	DefaultTTL string `json:"default_ttl,omitempty"`
+	MaxTTL     string `json:"max_ttl,omitempty"`
+}
+
+// DynamicCredentials are credentials issued by vault for dynamic role
+type DynamicCredentials struct {
+	Username      string
+	Password      string
+	LeaseID       string
+	LeaseDuration time.Duration
+	Renewable     bool
+}
+
+// IsDynamicCredentials returns true if vault dynamic roles should be used instead of static ones
+func (vc *VaultClient) IsDynamicCredentials() bool {
+	return vc.CredentialsMode == VaultDynamicCredentials
+}
+
+// CreateDynamicVaultRole creates vault dynamic role for the database user and returns role name
+func (vc *VaultClient) CreateDynamicVaultRole(cloudPublicHost, namespace, microserviceName, dbRole string, statements DynamicRoleStatements) (string, error) {
+	if err := vc.RefreshSelfToken(); err != nil {
+		return "", err
+	}
+	if len(statements.Creation) == 0 {
+		return "", errors.New("creation statements are required for vault dynamic role")
+	}
+
+	roleName := vc.GetVaultRoleName(cloudPublicHost, namespace, microserviceName, dbRole)
+	req := vc.client.NewRequest("POST", "/v1/database/roles/"+roleName)
+	err := req.SetJSONBody(dynamicRoleOptions{
+		DbName:                vc.VaultDBName,
+		DynamicRoleStatements: statements,
+		DefaultTTL:            vc.DynamicDefaultTTL,
+		MaxTTL:                vc.DynamicMaxTTL,
+	})
+	if err != nil {
+		log.Error("can not create Vault dynamic role request body")
+		return "", err
+	}
+
+	resp, err := vc.client.RawRequest(req)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not create Vault dynamic role %s", roleName), zap.Error(err))
+		return "", err
+	}
+	if resp.Error() != nil {
+		return "", resp.Error()
+	}
+	log.Info(fmt.Sprintf("Vault dynamic role %s has been created", roleName))
+	return roleName, nil
+}
+
+// DeleteDynamicVaultRole revokes all credentials issued for the dynamic role and deletes the role
+func (vc *VaultClient) DeleteDynamicVaultRole(roleName string) error {
+	if err := vc.RevokeDynamicCredentials(roleName); err != nil {
+		return err
+	}
+
+	req := vc.client.NewRequest("DELETE", "/v1/database/roles/"+roleName)
+	resp, err := vc.client.RawRequest(req)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not delete Vault dynamic role %s", roleName), zap.Error(err))
+		return err
+	}
+	if err = resp.Error(); err != nil {
+		log.Error(fmt.Sprintf("can not delete Vault dynamic role %s", roleName), zap.Error(err))
+		return err
+	}
+	log.Info(fmt.Sprintf("Vault dynamic role %s has been deleted", roleName))
+	return nil
+}
+
+// GenerateDynamicCredentials requests new credentials for the dynamic role. Lease of the credentials is renewed
+// in background until it reaches max TTL, the credentials are revoked or the client is closed.
+func (vc *VaultClient) GenerateDynamicCredentials(roleName string) (*DynamicCredentials, error) {
+	if err := vc.RefreshSelfToken(); err != nil {
+		return nil, err
+	}
+	secret, err := vc.client.Logical().Read("database/creds/" + roleName)
+	if err != nil {
+		log.Error(fmt.Sprintf("can not generate credentials for Vault dynamic role %s", roleName), zap.Error(err))
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("vault returned no credentials for dynamic role %s", roleName)
+	}
+	username, _ := secret.Data["username"].(string)
+	password, _ := secret.Data["password"].(string)
+	creds := &DynamicCredentials{
+		Username:      username,
+		Password:      password,
+		LeaseID:       secret.LeaseID,
+		LeaseDuration: time.Duration(secret.LeaseDuration) * time.Second,
+		Renewable:     secret.Renewable,
+	}
+	vc.leases.track(roleName, creds)
+	return creds, nil
+}
+
+// RevokeDynamicCredentials revokes all credentials issued for the dynamic role
+func (vc *VaultClient) RevokeDynamicCredentials(roleName string) error {
+	if err := vc.RefreshSelfToken(); err != nil {
+		return err
+	}
+	vc.leases.forget(roleName)
+	if err := vc.client.Sys().RevokePrefix("database/creds/" + roleName); err != nil {
+		log.Error(fmt.Sprintf("can not revoke credentials of Vault dynamic role %s", roleName), zap.Error(err))
+		return err
+	}
+	log.Info(fmt.Sprintf("Credentials of Vault dynamic role %s have been revoked", roleName))
+	return nil
+}
+
+// leaseTracker renews leases of dynamic credentials issued by this client
+type leaseTracker struct {
+	client *vault.Client
+	mutex  sync.Mutex
+	// leases contains stop channels of lease renewals by role name and lease id
+	leases map[string]map[string]chan struct{}
+}
+
+func newLeaseTracker(client *vault.Client) *leaseTracker {
+	return &leaseTracker{
+		client: client,
+		leases: make(map[string]map[string]chan struct{}),
+	}
+}
+
+func (t *leaseTracker) track(roleName string, creds *DynamicCredentials) {
+	if !creds.Renewable || creds.LeaseID == "" || creds.LeaseDuration <= 0 {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.leases[roleName] == nil {
+		t.leases[roleName] = make(map[string]chan struct{})
+	}
+	stop := make(chan struct{})
+	t.leases[roleName][creds.LeaseID] = stop
+	go t.renew(roleName, creds.LeaseID, creds.LeaseDuration, stop)
+}
+
+// renew prolongs the lease after 2/3 of its duration until vault does not extend it anymore
+func (t *leaseTracker) renew(roleName, leaseID string, ttl time.Duration, stop chan struct{}) {
+	defer t.remove(roleName, leaseID)
+	for {
+		timer := time.NewTimer(ttl * 2 / 3)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		secret, err := t.client.Sys().Renew(leaseID, 0)
+		if err != nil {
+			log.Warn(fmt.Sprintf("Can not renew lease %s, it expires in %v", leaseID, ttl/3), zap.Error(err))
+			return
+		}
+		renewedTTL := time.Duration(secret.LeaseDuration) * time.Second
+		if renewedTTL <= 0 || renewedTTL*3 < ttl {
+			log.Info(fmt.Sprintf("Lease %s reaches its max TTL and is not renewed anymore", leaseID))
+			return
+		}
+		log.Debug(fmt.Sprintf("Lease %s is renewed, TTL %v", leaseID, renewedTTL))
+		ttl = renewedTTL
+	}
+}
+
+func (t *leaseTracker) remove(roleName, leaseID string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.leases[roleName], leaseID)
+	if len(t.leases[roleName]) == 0 {
+		delete(t.leases, roleName)
+	}
+}
+
+// forget stops renewal of all leases of the role
+func (t *leaseTracker) forget(roleName string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, stop := range t.leases[roleName] {
+		close(stop)
+	}
+	delete(t.leases, roleName)
+}
+
+func (t *leaseTracker) close() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, leases := range t.leases {
+		for _, stop := range leases {
+			close(stop)
+		}
+	}
+	t.leases = make(map[string]map[string]chan struct{})
+}
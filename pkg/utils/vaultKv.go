@@ -0,0 +1,146 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// kvMount describes KV secrets engine mount which contains a secret
+type kvMount struct {
+	path    string
+	version int
+}
+
+// kvMounts caches detected KV mounts, so mount is looked up only once
+type kvMounts struct {
+	mutex  sync.RWMutex
+	mounts []kvMount
+}
+
+func (m *kvMounts) find(secretPath string) (kvMount, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	found := kvMount{}
+	for _, mount := range m.mounts {
+		if strings.HasPrefix(secretPath, mount.path) && len(mount.path) > len(found.path) {
+			found = mount
+		}
+	}
+	return found, found.path != ""
+}
+
+func (m *kvMounts) add(mount kvMount) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.mounts = append(m.mounts, mount)
+}
+
+// ReadPasswordFromKv reads "password" key of the secret from KV secrets engine. Engine version is taken from
+// VaultConfig.KvVersion or detected by the mount of the secret if the version is not set.
+func (vc *VaultClient) ReadPasswordFromKv(secretPath string) (string, error) {
+	if err := vc.RefreshSelfToken(); err != nil {
+		return "", err
+	}
+	secretPath = strings.TrimPrefix(secretPath, "/")
+	mount, err := vc.getKvMount(secretPath)
+	if err != nil {
+		return "", err
+	}
+
+	var data map[string]interface{}
+	if mount.version == 2 {
+		secret, err := vc.client.Logical().Read(kvV2DataPath(mount.path, secretPath))
+		if err != nil {
+			return "", err
+		}
+		if secret != nil {
+			data, _ = secret.Data["data"].(map[string]interface{})
+		}
+	} else {
+		secret, err := vc.client.Logical().Read(secretPath)
+		if err != nil {
+			return "", err
+		}
+		if secret != nil {
+			data = secret.Data
+		}
+	}
+
+	if password, ok := data["password"].(string); ok {
+		return password, nil
+	}
+	return "", errors.New("secret is empty")
+}
+
+func (vc *VaultClient) getKvMount(secretPath string) (kvMount, error) {
+	if vc.KvVersion != 0 {
+		if vc.KvVersion != 1 && vc.KvVersion != 2 {
+			return kvMount{}, fmt.Errorf("unsupported KV version %d", vc.KvVersion)
+		}
+		return kvMount{path: vc.kvMountPath(secretPath), version: vc.KvVersion}, nil
+	}
+	if mount, ok := vc.kvMounts.find(secretPath); ok {
+		return mount, nil
+	}
+
+	secret, err := vc.client.Logical().Read("sys/internal/ui/mounts/" + secretPath)
+	if err != nil || secret == nil {
+		// detection requires permission on sys/internal/ui/mounts, fall back to the previous behaviour
+		log.Warn(fmt.Sprintf("Can not detect KV version for %s, KV v1 is used", secretPath), zap.Error(err))
+		return kvMount{path: vc.kvMountPath(secretPath), version: 1}, nil
+	}
+	mount := kvMount{version: 1}
+	mount.path, _ = secret.Data["path"].(string)
+	if mount.path == "" {
+		mount.path = vc.kvMountPath(secretPath)
+	}
+	if options, ok := secret.Data["options"].(map[string]interface{}); ok && options["version"] == "2" {
+		mount.version = 2
+	}
+	log.Debug(fmt.Sprintf("KV v%d is detected for mount %s", mount.version, mount.path))
+	vc.kvMounts.add(mount)
+	return mount, nil
+}
+
+// kvV2DataPath converts secret path to KV v2 data path, e.g. "secret/app/db" to "secret/data/app/db".
+// Paths which already point to data are returned as is.
+func kvV2DataPath(mountPath, secretPath string) string {
+	relative := strings.TrimPrefix(secretPath, mountPath)
+	if strings.HasPrefix(relative, "data/") {
+		return secretPath
+	}
+	return mountPath + "data/" + relative
+}
+
+// kvMountPath returns VaultConfig.KvMount if it is set, otherwise the first segment of the secret path
+func (vc *VaultClient) kvMountPath(secretPath string) string {
+	if vc.KvMount != "" {
+		return strings.TrimSuffix(strings.TrimPrefix(vc.KvMount, "/"), "/") + "/"
+	}
+	return firstPathSegment(secretPath)
+}
+
+func firstPathSegment(secretPath string) string {
+	if i := strings.Index(secretPath, "/"); i >= 0 {
+		return secretPath[:i+1]
+	}
+	return secretPath + "/"
+}
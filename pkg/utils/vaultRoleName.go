@@ -0,0 +1,89 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// DefaultVaultRoleNameMaxLength is used if VaultConfig.RoleNameMaxLength is not set
+	DefaultVaultRoleNameMaxLength = 128
+	// vaultRoleNameHashLength is the length of hash suffix added to shortened or escaped role names
+	vaultRoleNameHashLength = 8
+	// minVaultRoleNameLength fits the prefix and the hash suffix
+	minVaultRoleNameLength = len(RolePrefix) + vaultRoleNameHashLength + 1
+	// mongoUserPrefix is the prefix of users returned by mongo adapter
+	mongoUserPrefix = "admin:"
+)
+
+// BuildVaultRoleName builds vault role name for the database user. Legacy name is kept if it is safe in vault paths
+// and fits maxLength, so existing roles keep their names. Otherwise the Mongo "admin:" prefix is trimmed, characters
+// which are not safe are replaced with "-", the name is shortened to maxLength and gets the hash of the legacy name
+// as suffix, so different users do not get the same role. maxLength is raised to fit the prefix and the suffix.
+func BuildVaultRoleName(maxLength int, cloudPublicHost, namespace, microserviceName, dbRole string) string {
+	maxLength = max(maxLength, minVaultRoleNameLength)
+	legacy := LegacyVaultRoleName(cloudPublicHost, namespace, microserviceName, dbRole)
+	if escapeVaultRoleName(legacy) == legacy && len(legacy) <= maxLength {
+		return legacy
+	}
+	name := escapeVaultRoleName(LegacyVaultRoleName(cloudPublicHost, namespace, microserviceName, strings.TrimPrefix(dbRole, mongoUserPrefix)))
+	hash := sha256.Sum256([]byte(legacy))
+	suffix := "-" + hex.EncodeToString(hash[:])[:vaultRoleNameHashLength]
+	if len(name)+len(suffix) > maxLength {
+		name = name[:maxLength-len(suffix)]
+	}
+	return name + suffix
+}
+
+// LegacyVaultRoleName is the role name built before role names were escaped and shortened.
+// Roles with such names are still resolved through database metadata.
+func LegacyVaultRoleName(cloudPublicHost, namespace, microserviceName, dbRole string) string {
+	return RolePrefix + cloudPublicHost + "_" + namespace + "_" + microserviceName + "_" + dbRole
+}
+
+// ResolveVaultRoleName returns the role of the user among existing roles, e.g. roles stored in metadata.
+// Both current and legacy names are checked, with and without the Mongo "admin:" prefix of the user.
+func ResolveVaultRoleName(maxLength int, existingRoles []string, cloudPublicHost, namespace, microserviceName, dbRole string) (string, bool) {
+	dbRoles := []string{dbRole}
+	if !strings.HasPrefix(dbRole, mongoUserPrefix) {
+		dbRoles = append(dbRoles, mongoUserPrefix+dbRole)
+	}
+	var candidates []string
+	for _, role := range dbRoles {
+		candidates = append(candidates,
+			BuildVaultRoleName(maxLength, cloudPublicHost, namespace, microserviceName, role),
+			LegacyVaultRoleName(cloudPublicHost, namespace, microserviceName, role))
+	}
+	for _, candidate := range candidates {
+		for _, role := range existingRoles {
+			if role == candidate {
+				return role, true
+			}
+		}
+	}
+	return "", false
+}
+
+func escapeVaultRoleName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, name)
+}
@@ -0,0 +1,256 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// tokenExpirationMargin is the time before token expiration when token is considered as expired
+	tokenExpirationMargin = 5 * time.Second
+	// tokenRetryDelay is the delay between attempts to renew or obtain token after failure
+	tokenRetryDelay = 5 * time.Second
+	// tokenCheckInterval is used to check tokens without TTL
+	tokenCheckInterval = 5 * time.Minute
+)
+
+// vaultTokenMetrics are metrics of the vault token, they are not exported if VaultConfig.MetricsRegisterer is nil
+type vaultTokenMetrics struct {
+	ttl        prometheus.Gauge
+	expiration prometheus.Gauge
+	operations *prometheus.CounterVec
+}
+
+func newVaultTokenMetrics(registerer prometheus.Registerer) *vaultTokenMetrics {
+	metrics := &vaultTokenMetrics{
+		ttl: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "dbaas_adapter_vault_token_ttl_seconds",
+			Help: "TTL of the vault token at the moment of the last login or renewal",
+		}),
+		expiration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "dbaas_adapter_vault_token_expiration_timestamp_seconds",
+			Help: "Unix time when the current vault token expires, 0 if token does not expire",
+		}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dbaas_adapter_vault_token_operations_total",
+			Help: "Number of vault logins and token renewals",
+		}, []string{"operation", "result"}),
+	}
+	if registerer != nil {
+		metrics.ttl = registerCollector(registerer, metrics.ttl)
+		metrics.expiration = registerCollector(registerer, metrics.expiration)
+		metrics.operations = registerCollector(registerer, metrics.operations)
+	}
+	return metrics
+}
+
+// registerCollector returns collector already registered with the same description if there is one.
+// Collector is not exported if it cannot be registered.
+func registerCollector[T prometheus.Collector](registerer prometheus.Registerer, collector T) T {
+	if err := registerer.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		log.Warn("Can not register vault metrics", zap.Error(err))
+	}
+	return collector
+}
+
+// vaultTokenManager logs in to vault once and renews the token in background before its TTL is over.
+// If renewal fails, it logs in again with the authenticator, which reads rotated credentials on every login.
+type vaultTokenManager struct {
+	client        *vault.Client
+	authenticator Authenticator
+	metrics       *vaultTokenMetrics
+
+	// refreshMutex serializes logins and renewals, vault is called under it
+	refreshMutex sync.Mutex
+	// mutex guards the token state, it is never held during calls to vault
+	mutex sync.Mutex
+	// obtained is the time of the last login or renewal
+	obtained time.Time
+	// ttl is the token TTL at the moment of the last login or renewal, 0 if token does not expire
+	ttl       time.Duration
+	loginTTL  time.Duration
+	renewable bool
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newVaultTokenManager(client *vault.Client, authenticator Authenticator, registerer prometheus.Registerer) *vaultTokenManager {
+	return &vaultTokenManager{
+		client:        client,
+		authenticator: authenticator,
+		metrics:       newVaultTokenMetrics(registerer),
+		stop:          make(chan struct{}),
+	}
+}
+
+// start logs in to vault and starts background token renewal
+func (m *vaultTokenManager) start() error {
+	m.refreshMutex.Lock()
+	defer m.refreshMutex.Unlock()
+	if err := m.login(); err != nil {
+		return err
+	}
+	go m.run()
+	return nil
+}
+
+// close stops background token renewal
+func (m *vaultTokenManager) close() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+}
+
+// ensureToken logs in to vault if the token is expired, e.g. background renewal could not get new one in time
+func (m *vaultTokenManager) ensureToken() error {
+	if !m.isExpired() {
+		return nil
+	}
+	m.refreshMutex.Lock()
+	defer m.refreshMutex.Unlock()
+	// token could be obtained by concurrent call while waiting for the lock
+	if !m.isExpired() {
+		return nil
+	}
+	log.Info("Vault token is expired, login again")
+	return m.login()
+}
+
+func (m *vaultTokenManager) isExpired() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.ttl > 0 && time.Now().After(m.obtained.Add(m.ttl-tokenExpirationMargin))
+}
+
+func (m *vaultTokenManager) run() {
+	for {
+		timer := time.NewTimer(m.nextRenewal())
+		select {
+		case <-m.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			m.renewOrLogin()
+		}
+	}
+}
+
+// nextRenewal returns delay before token renewal, which is performed after 2/3 of the token TTL
+func (m *vaultTokenManager) nextRenewal() time.Duration {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.ttl == 0 {
+		return tokenCheckInterval
+	}
+	delay := time.Until(m.obtained.Add(m.ttl * 2 / 3))
+	if delay <= 0 {
+		return tokenRetryDelay
+	}
+	return delay
+}
+
+func (m *vaultTokenManager) renewOrLogin() {
+	m.refreshMutex.Lock()
+	defer m.refreshMutex.Unlock()
+	m.mutex.Lock()
+	ttl, renewable := m.ttl, m.renewable
+	m.mutex.Unlock()
+	if ttl == 0 {
+		return
+	}
+	if renewable {
+		err := m.renew()
+		if err == nil {
+			return
+		}
+		log.Warn("Can not renew vault token, login again", zap.Error(err))
+	}
+	if err := m.login(); err != nil {
+		log.Error(fmt.Sprintf("Can not login to vault, next attempt in %v", tokenRetryDelay), zap.Error(err))
+	}
+}
+
+// renew prolongs the current token. Error is returned if token can not be renewed or
+// the token is close to its max TTL, so new token should be obtained by login.
+func (m *vaultTokenManager) renew() error {
+	secret, err := m.client.Auth().Token().RenewSelf(0)
+	if err != nil {
+		m.metrics.operations.WithLabelValues("renewal", "failure").Inc()
+		return err
+	}
+	ttl, err := secret.TokenTTL()
+	if err != nil {
+		m.metrics.operations.WithLabelValues("renewal", "failure").Inc()
+		return err
+	}
+	m.mutex.Lock()
+	loginTTL := m.loginTTL
+	m.mutex.Unlock()
+	if ttl*3 < loginTTL {
+		m.metrics.operations.WithLabelValues("renewal", "failure").Inc()
+		return fmt.Errorf("vault token is close to its max TTL, renewed TTL is %v", ttl)
+	}
+	renewable, _ := secret.TokenIsRenewable()
+	m.setTokenState(ttl, renewable)
+	m.metrics.operations.WithLabelValues("renewal", "success").Inc()
+	log.Debug(fmt.Sprintf("Vault token is renewed, TTL %v", ttl))
+	return nil
+}
+
+func (m *vaultTokenManager) login() error {
+	secret, err := m.authenticator.Login(m.client)
+	if err != nil {
+		m.metrics.operations.WithLabelValues("login", "failure").Inc()
+		return err
+	}
+	m.client.SetToken(secret.Auth.ClientToken)
+	ttl := time.Duration(secret.Auth.LeaseDuration) * time.Second
+	m.mutex.Lock()
+	m.loginTTL = ttl
+	m.mutex.Unlock()
+	m.setTokenState(ttl, secret.Auth.Renewable)
+	m.metrics.operations.WithLabelValues("login", "success").Inc()
+	log.Info(fmt.Sprintf("Logged in to vault, token TTL %v", ttl))
+	return nil
+}
+
+func (m *vaultTokenManager) setTokenState(ttl time.Duration, renewable bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.obtained = time.Now()
+	m.ttl = ttl
+	m.renewable = renewable
+	m.metrics.ttl.Set(ttl.Seconds())
+	if ttl > 0 {
+		m.metrics.expiration.Set(float64(m.obtained.Add(ttl).Unix()))
+	} else {
+		m.metrics.expiration.Set(0)
+	}
+}
@@ -0,0 +1,360 @@
+// Copyright 2024-2025 NetCracker Technology Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeVault struct {
+	mutex         sync.Mutex
+	logins        []string
+	renewals      int
+	failRenew     bool
+	leaseSec      int
+	tokenCount    int
+	requests      []string
+	leaseRenewals int
+}
+
+func (f *fakeVault) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/kubernetes/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.mutex.Lock()
+		f.logins = append(f.logins, body["jwt"])
+		f.tokenCount++
+		f.mutex.Unlock()
+		writeAuth(w, "token", f.leaseSec)
+	})
+	mux.HandleFunc("/v1/auth/token/renew-self", func(w http.ResponseWriter, r *http.Request) {
+		f.mutex.Lock()
+		f.renewals++
+		fail := f.failRenew
+		f.mutex.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		writeAuth(w, r.Header.Get("X-Vault-Token"), f.leaseSec)
+	})
+	mux.HandleFunc("/v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["role_id"] != "role" || body["secret_id"] != "secret-id" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+			return
+		}
+		writeAuth(w, "approle-token", f.leaseSec)
+	})
+	mux.HandleFunc("/v1/auth/token/lookup-self", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "static-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"static-token","ttl":0,"renewable":false}}`))
+	})
+	mux.HandleFunc("/v1/sys/internal/ui/mounts/", func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v1/sys/internal/ui/mounts/kv2/") {
+			_, _ = w.Write([]byte(`{"data":{"path":"kv2/","type":"kv","options":{"version":"2"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"path":"secret/","type":"kv","options":null}}`))
+	})
+	mux.HandleFunc("/v1/kv2/data/app/password", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"data":{"password":"secret-v2"},"metadata":{"version":1}}}`))
+	})
+	mux.HandleFunc("/v1/team/kv/data/app/password", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"data":{"password":"secret-team"},"metadata":{"version":1}}}`))
+	})
+	mux.HandleFunc("/v1/database/", func(w http.ResponseWriter, r *http.Request) {
+		f.mutex.Lock()
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+		f.mutex.Unlock()
+		if strings.HasPrefix(r.URL.Path, "/v1/database/creds/") {
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/role/lease1","lease_duration":1,"renewable":true,` +
+				`"data":{"username":"v-user","password":"v-password"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/v1/sys/leases/", func(w http.ResponseWriter, r *http.Request) {
+		f.mutex.Lock()
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+		if strings.HasPrefix(r.URL.Path, "/v1/sys/leases/renew") {
+			f.leaseRenewals++
+			f.mutex.Unlock()
+			_, _ = w.Write([]byte(`{"lease_id":"database/creds/role/lease1","lease_duration":1,"renewable":true}`))
+			return
+		}
+		f.mutex.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/v1/secret/password", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"password":"secret"}}`))
+	})
+	return mux
+}
+
+func writeAuth(w http.ResponseWriter, token string, leaseSec int) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token":   token,
+			"lease_duration": leaseSec,
+			"renewable":      true,
+		},
+	})
+}
+
+func (f *fakeVault) state() ([]string, int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]string{}, f.logins...), f.renewals
+}
+
+func newTestVaultClient(t *testing.T, fake *fakeVault, registerer ...prometheus.Registerer) (*VaultClient, string) {
+	server := httptest.NewServer(fake.handler())
+	t.Cleanup(server.Close)
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("jwt-1"), 0600))
+	config := VaultConfig{
+		IsVaultEnabled:  true,
+		Address:         server.URL,
+		VaultRole:       "adapter",
+		VaultAuthMethod: "kubernetes",
+		K8STokenPath:    tokenPath,
+	}
+	if len(registerer) > 0 {
+		config.MetricsRegisterer = registerer[0]
+	}
+	client, err := NewVaultClient(config)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client, tokenPath
+}
+
+func TestVaultTokenIsNotObtainedOnEveryOperation(t *testing.T) {
+	fake := &fakeVault{leaseSec: 3600}
+	client, _ := newTestVaultClient(t, fake)
+
+	for i := 0; i < 10; i++ {
+		password, err := client.ReadPasswordFromKv("secret/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", password)
+	}
+
+	logins, renewals := fake.state()
+	assert.Equal(t, []string{"jwt-1"}, logins)
+	assert.Equal(t, 0, renewals)
+}
+
+func TestVaultTokenIsRenewedBeforeExpiration(t *testing.T) {
+	fake := &fakeVault{leaseSec: 3}
+	registry := prometheus.NewRegistry()
+	newTestVaultClient(t, fake, registry)
+
+	assert.Eventually(t, func() bool {
+		_, renewals := fake.state()
+		return renewals > 0
+	}, 5*time.Second, 100*time.Millisecond)
+	logins, _ := fake.state()
+	assert.Len(t, logins, 1)
+	assert.Eventually(t, func() bool {
+		operations, err := testutil.GatherAndCount(registry, "dbaas_adapter_vault_token_operations_total")
+		return err == nil && operations == 2
+	}, time.Second, 10*time.Millisecond, "login and renewal are counted in the registerer of the client")
+}
+
+func TestVaultLoginWithRotatedTokenWhenRenewalFails(t *testing.T) {
+	fake := &fakeVault{leaseSec: 3, failRenew: true}
+	_, tokenPath := newTestVaultClient(t, fake)
+	require.NoError(t, os.WriteFile(tokenPath, []byte("jwt-2"), 0600))
+
+	assert.Eventually(t, func() bool {
+		logins, _ := fake.state()
+		return len(logins) > 1
+	}, 5*time.Second, 100*time.Millisecond)
+	logins, renewals := fake.state()
+	assert.Equal(t, []string{"jwt-1", "jwt-2"}, logins[:2])
+	assert.Positive(t, renewals)
+}
+
+func TestVaultClientWithAuthenticators(t *testing.T) {
+	fake := &fakeVault{leaseSec: 3600}
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	t.Run("AppRole", func(t *testing.T) {
+		secretIDFile := filepath.Join(t.TempDir(), "secret-id")
+		require.NoError(t, os.WriteFile(secretIDFile, []byte("secret-id\n"), 0600))
+		client, err := NewVaultClient(VaultConfig{
+			Address:       server.URL,
+			Authenticator: &AppRoleAuthenticator{RoleID: "role", SecretIDFile: secretIDFile},
+		})
+		require.NoError(t, err)
+		defer client.Close()
+		assert.Equal(t, "approle-token", client.client.Token())
+	})
+	t.Run("Token file", func(t *testing.T) {
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("static-token"), 0600))
+		client, err := NewVaultClient(VaultConfig{
+			Address:       server.URL,
+			Authenticator: &TokenFileAuthenticator{Path: tokenFile},
+		})
+		require.NoError(t, err)
+		defer client.Close()
+		password, err := client.ReadPasswordFromKv("secret/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", password)
+	})
+	t.Run("Missing kubernetes token", func(t *testing.T) {
+		client, err := NewVaultClient(VaultConfig{
+			Address:         server.URL,
+			VaultAuthMethod: "kubernetes",
+			K8STokenPath:    filepath.Join(t.TempDir(), "missing"),
+		})
+		assert.Nil(t, client)
+		assert.ErrorContains(t, err, "can not read kubernetes service account token")
+	})
+	t.Run("Login failure", func(t *testing.T) {
+		client, err := NewVaultClient(VaultConfig{
+			Address:       server.URL,
+			Authenticator: &AppRoleAuthenticator{RoleID: "role", SecretID: "wrong"},
+		})
+		assert.Nil(t, client)
+		assert.ErrorContains(t, err, "invalid role or secret ID")
+	})
+}
+
+func TestReadPasswordFromKv(t *testing.T) {
+	fake := &fakeVault{leaseSec: 3600}
+	client, _ := newTestVaultClient(t, fake)
+
+	password, err := client.ReadPasswordFromKv("kv2/app/password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-v2", password)
+
+	password, err = client.ReadPasswordFromKv("kv2/data/app/password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-v2", password)
+
+	password, err = client.ReadPasswordFromKv("secret/password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", password)
+
+	client.KvVersion = 2
+	_, err = client.ReadPasswordFromKv("secret/password")
+	assert.Error(t, err)
+
+	client.KvMount = "team/kv"
+	password, err = client.ReadPasswordFromKv("team/kv/app/password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-team", password, "configured mount is used instead of the first path segment")
+}
+
+func TestDynamicVaultRole(t *testing.T) {
+	fake := &fakeVault{leaseSec: 3600}
+	client, _ := newTestVaultClient(t, fake)
+	client.CredentialsMode = VaultDynamicCredentials
+	client.VaultDBName = "postgres"
+
+	_, err := client.CreateDynamicVaultRole("host", "ns", "ms", "user", DynamicRoleStatements{})
+	assert.Error(t, err)
+
+	roleName, err := client.CreateDynamicVaultRole("host", "ns", "ms", "user", DynamicRoleStatements{
+		Creation: []string{"CREATE ROLE \"{{name}}\" WITH LOGIN PASSWORD '{{password}}';"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "nc-dbaas-host_ns_ms_user", roleName)
+
+	require.NoError(t, client.ForceRefreshCredsFor(roleName))
+	fake.mutex.Lock()
+	assert.Equal(t, []string{"POST /v1/database/roles/" + roleName}, fake.requests, "credentials of other consumers are not revoked")
+	fake.mutex.Unlock()
+
+	creds, err := client.GenerateDynamicCredentials(roleName)
+	require.NoError(t, err)
+	assert.Equal(t, "v-user", creds.Username)
+	assert.Equal(t, "v-password", creds.Password)
+	assert.Eventually(t, func() bool {
+		fake.mutex.Lock()
+		defer fake.mutex.Unlock()
+		return fake.leaseRenewals > 0
+	}, 5*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, client.DeleteDynamicVaultRole(roleName))
+	fake.mutex.Lock()
+	requests := append([]string{}, fake.requests...)
+	fake.mutex.Unlock()
+	assert.Contains(t, requests, "POST /v1/database/roles/"+roleName)
+	assert.Contains(t, requests, "PUT /v1/sys/leases/revoke-prefix/database/creds/"+roleName)
+	assert.Equal(t, "DELETE /v1/database/roles/"+roleName, requests[len(requests)-1])
+	client.leases.mutex.Lock()
+	assert.Empty(t, client.leases.leases, "renewal of revoked credentials is stopped")
+	client.leases.mutex.Unlock()
+}
+
+func TestBuildVaultRoleName(t *testing.T) {
+	t.Run("Short safe name is not changed", func(t *testing.T) {
+		name := BuildVaultRoleName(128, "host", "ns", "ms", "user")
+		assert.Equal(t, LegacyVaultRoleName("host", "ns", "ms", "user"), name)
+	})
+	t.Run("Mongo prefix is trimmed", func(t *testing.T) {
+		name := BuildVaultRoleName(128, "host", "ns", "ms", "admin:user")
+		assert.Regexp(t, `^nc-dbaas-host_ns_ms_user-[0-9a-f]{8}$`, name)
+		assert.NotEqual(t, BuildVaultRoleName(128, "host", "ns", "ms", "user"), name, "name does not collide with user without prefix")
+	})
+	t.Run("Unsafe characters are escaped", func(t *testing.T) {
+		name := BuildVaultRoleName(128, "host", "ns", "ms", "us/er@1")
+		assert.Regexp(t, `^nc-dbaas-host_ns_ms_us-er-1-[0-9a-f]{8}$`, name)
+		assert.NotEqual(t, name, BuildVaultRoleName(128, "host", "ns", "ms", "us@er/1"))
+	})
+	t.Run("Long name is shortened", func(t *testing.T) {
+		long := strings.Repeat("namespace", 20)
+		name := BuildVaultRoleName(64, "host", long, "ms", "user")
+		assert.Len(t, name, 64)
+		assert.NotEqual(t, name, BuildVaultRoleName(64, "host", long, "ms", "user2"))
+		assert.Equal(t, name, BuildVaultRoleName(64, "host", long, "ms", "user"))
+		assert.Equal(t, LegacyVaultRoleName("host", long, "ms", "user"), BuildVaultRoleName(256, "host", long, "ms", "user"))
+	})
+	t.Run("Legacy names are resolved", func(t *testing.T) {
+		long := strings.Repeat("namespace", 20)
+		legacy := LegacyVaultRoleName("host", long, "ms", "admin:user")
+		role, found := ResolveVaultRoleName(128, []string{"other", legacy}, "host", long, "ms", "user")
+		assert.True(t, found)
+		assert.Equal(t, legacy, role)
+		_, found = ResolveVaultRoleName(128, []string{"other"}, "host", long, "ms", "user")
+		assert.False(t, found)
+	})
+}
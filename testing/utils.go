@@ -24,7 +24,9 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/config"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dao"
 	"github.com/Netcracker/qubership-dbaas-adapter-core/pkg/dbaas"
 	fiber2 "github.com/Netcracker/qubership-dbaas-adapter-core/pkg/impl/fiber"
@@ -54,55 +56,53 @@ func GetDefaultApp(
 	logger *zap.Logger,
 	profiler bool,
 	promServiceName string) (context.CancelFunc, *fiber.App, error) {
-	appPath := "/" + appName
+	cancel, server, err := GetDefaultServer(dbaasClient, namespace, appName, apiUser, apiPass, aggregatorRegistrationLabels,
+		adapterAddress, dbAdmin, backupAddress, backupDaemonApiUser, backupDaemonApiUPass, backupFullRestore, httpClient,
+		supports, logger, profiler, promServiceName)
+	return cancel, server.App(), err
+}
 
-	administrationService := service.NewCoreAdministrationService(
-		namespace,
-		8080,
-		dbAdmin,
-		logger,
-		false,
-		nil,
-		"",
-	)
-	return fiber2.GetFiberServer(func(app *fiber.App, ctx context.Context) error {
-		fiber2.BuildFiberDBaaSAdapterHandlers(
-			app,
-			apiUser,
-			apiPass,
-			appPath,
-			administrationService,
-			service.NewPhysicalRegistrationService(
-				appName,
-				logger,
-				appName,
-				adapterAddress,
-				dao.BasicAuth{
-					Username: apiUser,
-					Password: apiPass,
-				},
-				aggregatorRegistrationLabels,
-				dbaasClient,
-				150000,
-				60000,
-				5000,
-				administrationService,
-				ctx,
-			),
-			service.DefaultBackupAdministrationService(
-				logger,
-				backupAddress,
-				backupDaemonApiUser,
-				backupDaemonApiUPass,
-				backupFullRestore,
-				httpClient,
-				64,
-				nil),
-			supports.ToMap(),
-			logger,
-			profiler,
-			Simplstr())
-		return nil
+// GetDefaultServer is GetDefaultApp which returns state of the app, e.g. to drain it or update its credentials
+func GetDefaultServer(
+	dbaasClient *dbaas.Client,
+	namespace string,
+	appName string,
+	apiUser string,
+	apiPass string,
+	aggregatorRegistrationLabels map[string]string,
+	adapterAddress string,
+	dbAdmin service.DbAdministration,
+	backupAddress string,
+	backupDaemonApiUser string,
+	backupDaemonApiUPass string,
+	backupFullRestore bool,
+	httpClient utils.HttpClient,
+	supports dao.SupportsBase,
+	logger *zap.Logger,
+	profiler bool,
+	promServiceName string) (context.CancelFunc, *fiber2.AdapterServer, error) {
+	cfg := config.Default()
+	cfg.Namespace = namespace
+	cfg.Adapter.Name = appName
+	cfg.Adapter.Address = adapterAddress
+	cfg.Adapter.Username = apiUser
+	cfg.Adapter.Password = apiPass
+	cfg.Adapter.PhysicalDatabaseId = appName
+	cfg.Adapter.Labels = aggregatorRegistrationLabels
+	cfg.Adapter.Profiler = profiler
+	cfg.Adapter.MetricsServiceName = promServiceName
+	cfg.Backup.Address = backupAddress
+	cfg.Backup.Username = backupDaemonApiUser
+	cfg.Backup.Password = backupDaemonApiUPass
+	cfg.Backup.FullRestore = backupFullRestore
+
+	backupService := service.DefaultBackupAdministrationService(logger, backupAddress, backupDaemonApiUser,
+		backupDaemonApiUPass, backupFullRestore, httpClient, cfg.Backup.DbNameMaxLength, nil)
+	return fiber2.GetAdapterServer(func(app *fiber.App, ctx context.Context) error {
+		return fiber2.BuildAdapter(app, ctx, cfg, dbAdmin, logger,
+			fiber2.WithDbaasClient(dbaasClient),
+			fiber2.WithBackupService(backupService),
+			fiber2.WithSupports(supports))
 	})
 }
 
@@ -274,12 +274,61 @@ func UseFullFeaturedConfig(logger *zap.Logger, t *testing.T, app *fiber.App, ver
 	assert.Equal(t, nil, respErr)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
+	// Force registration requires auth
 	resp, respErr = HandlerTest(logger, app,
 		http.MethodGet,
 		defautRoute+"/physical_database/force_registration",
 		nil)
 	assert.Equal(t, nil, respErr)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, respErr = HandlerTest(logger, app,
+		http.MethodGet,
+		defautRoute+"/physical_database/force_registration",
+		nil,
+		adapterApiUser,
+		adapterApiPass)
+	assert.Equal(t, nil, respErr)
 	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	registrationTrackBody, _ := ioutil.ReadAll(resp.Body)
+	var registrationTrack dao.PhysicalDatabaseRegistrationTrack
+	json.Unmarshal(registrationTrackBody, &registrationTrack)
+	resp.Body.Close()
+	assert.NotEqual(t, "", registrationTrack.TrackId)
+
+	resp, respErr = HandlerTest(logger, app,
+		http.MethodGet,
+		defautRoute+"/physical_database/force_registration/"+registrationTrack.TrackId,
+		nil,
+		adapterApiUser,
+		adapterApiPass)
+	assert.Equal(t, nil, respErr)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+	assert.Eventually(t, func() bool {
+		resp, respErr := HandlerTest(logger, app,
+			http.MethodGet,
+			defautRoute+"/physical_database/force_registration/"+registrationTrack.TrackId,
+			nil,
+			adapterApiUser,
+			adapterApiPass)
+		if respErr != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		json.NewDecoder(resp.Body).Decode(&registrationTrack)
+		return registrationTrack.Status != dao.RegistrationProceedingStatus
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, dao.RegistrationSuccessStatus, registrationTrack.Status)
+
+	resp, respErr = HandlerTest(logger, app,
+		http.MethodGet,
+		defautRoute+"/physical_database/force_registration/"+Simplstr(),
+		nil,
+		adapterApiUser,
+		adapterApiPass)
+	assert.Equal(t, nil, respErr)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 
 	// Check Auth Working
 	resp, respErr = HandlerTest(logger, app,
@@ -506,6 +555,13 @@ type AppCredentials struct {
 
 func PrepateTestApp(dbaasClient *dbaas.Client, logger *zap.Logger, dbAdmin service.DbAdministration,
 	testApp AppCredentials, backupAddress string) (context.CancelFunc, *fiber.App, error, AppCredentials) {
+	c, server, e, credentials := PrepareTestServer(dbaasClient, logger, dbAdmin, testApp, backupAddress)
+	return c, server.App(), e, credentials
+}
+
+// PrepareTestServer is PrepateTestApp which returns state of the app, e.g. to drain it or update its credentials
+func PrepareTestServer(dbaasClient *dbaas.Client, logger *zap.Logger, dbAdmin service.DbAdministration,
+	testApp AppCredentials, backupAddress string) (context.CancelFunc, *fiber2.AdapterServer, error, AppCredentials) {
 	logger.Debug("Setting up test environment...")
 
 	namespace := Simplstr()
@@ -526,7 +582,7 @@ func PrepateTestApp(dbaasClient *dbaas.Client, logger *zap.Logger, dbAdmin servi
 
 	logger.Debug("Created Aggregator Test Server Handlers...")
 
-	c, a, e := GetDefaultApp(
+	c, a, e := GetDefaultServer(
 		dbaasClient,
 		namespace,
 		testApp.AppName,